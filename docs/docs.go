@@ -310,8 +310,11 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Action deleted"
+                    "200": {
+                        "description": "Action soft deleted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ActionResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad request",
@@ -343,9 +346,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/resources": {
+        "/api/admin/decision-cache": {
             "get": {
-                "description": "Get a paginated list of all resources",
+                "description": "Get the number of cached permission decisions and the hits, misses, evictions, expirations and invalidations of the cache since the server started, across tenants",
                 "consumes": [
                     "application/json"
                 ],
@@ -353,43 +356,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "resources"
-                ],
-                "summary": "List resources",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Number of items to return (default: 10)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to skip (default: 0)",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "admin"
                 ],
+                "summary": "Get decision cache statistics",
                 "responses": {
                     "200": {
-                        "description": "List of resources",
+                        "description": "Decision cache statistics",
                         "schema": {
-                            "$ref": "#/definitions/dto.ListResourcesResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/dto.DecisionCacheStatsResponse"
                         }
                     }
                 }
             },
+            "delete": {
+                "description": "Drop every cached permission decision, of every tenant. Statistics are kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Clear the decision cache",
+                "responses": {
+                    "204": {
+                        "description": "Decision cache cleared"
+                    }
+                }
+            }
+        },
+        "/api/check-permission": {
             "post": {
-                "description": "Create a new resource with the provided information",
+                "description": "Checks if a user has permission to perform an action on a resource. Permission conditions are evaluated against the optional request context. Passing the revision token returned by a change as at_least_as_fresh guarantees the decision reflects that change; fully_consistent decides from the latest data.",
                 "consumes": [
                     "application/json"
                 ],
@@ -397,29 +397,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "resources"
+                    "permissions"
                 ],
-                "summary": "Create a new resource",
+                "summary": "Check permission",
                 "parameters": [
                     {
-                        "description": "Resource information",
-                        "name": "resource",
+                        "description": "Permission check request",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateResourceRequest"
+                            "$ref": "#/definitions/dto.PermissionCheckRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Resource created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ResourceResponse"
+                            "$ref": "#/definitions/dto.PermissionCheckResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -428,7 +428,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -439,9 +439,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/resources/{id}": {
-            "get": {
-                "description": "Retrieve a specific resource by its unique identifier",
+        "/api/check-permission/explain": {
+            "post": {
+                "description": "Checks a permission like /api/check-permission and returns a trace of the roles, user sets, resource sets, permissions and relationships considered, the conditions that matched or failed with their actual and expected values, and the rule that decided the outcome",
                 "consumes": [
                     "application/json"
                 ],
@@ -449,27 +449,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "resources"
+                    "permissions"
                 ],
-                "summary": "Get a resource by ID",
+                "summary": "Explain a permission check",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Resource ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Permission check request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PermissionCheckRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Resource found",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ResourceResponse"
+                            "$ref": "#/definitions/dto.PermissionExplainResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -477,8 +479,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Resource not found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -487,9 +489,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing resource by its ID",
+            }
+        },
+        "/api/check-permissions/batch": {
+            "post": {
+                "description": "Checks up to 100 permissions at once and returns the decisions in request order. Lookups shared by the checks are made once and the checks are evaluated concurrently. A failing check reports its error without failing the batch. The batch is decided from data meeting the consistency options of every check.",
                 "consumes": [
                     "application/json"
                 ],
@@ -497,36 +501,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "resources"
+                    "permissions"
                 ],
-                "summary": "Update a resource",
+                "summary": "Check permissions in batch",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Resource ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated resource information",
-                        "name": "resource",
+                        "description": "Permission checks",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateResourceRequest"
+                            "$ref": "#/definitions/dto.BatchPermissionCheckRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Resource updated",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ResourceResponse"
+                            "$ref": "#/definitions/dto.BatchPermissionCheckResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -534,14 +531,35 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Resource not found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/api/export": {
+            "get": {
+                "description": "Stream every resource, action, role, user, role assignment, user set, resource set, permission and relationship of the tenant, and the relation schema in use, as a single versioned JSON bundle.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bundle"
+                ],
+                "summary": "Export the model",
+                "responses": {
+                    "200": {
+                        "description": "Bundle",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
                     "500": {
                         "description": "Internal server error",
@@ -553,9 +571,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a resource by its ID",
+            }
+        },
+        "/api/import": {
+            "post": {
+                "description": "Restore an exported bundle into the tenant, keeping entity IDs. The import is atomic. Entities that already exist are skipped, overwritten or fail the import depending on the conflict strategy.",
                 "consumes": [
                     "application/json"
                 ],
@@ -563,24 +583,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "resources"
+                    "bundle"
                 ],
-                "summary": "Delete a resource",
+                "summary": "Import a bundle",
                 "parameters": [
+                    {
+                        "description": "Bundle",
+                        "name": "bundle",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "Resource ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Conflict strategy: skip, overwrite or fail (default)",
+                        "name": "conflict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Resource deleted"
+                    "200": {
+                        "description": "Import summary",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportBundleResponse"
+                        }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid bundle or conflict strategy",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -588,8 +619,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Resource not found",
+                    "409": {
+                        "description": "Entity already exists",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -609,9 +640,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/roles": {
-            "get": {
-                "description": "Get a paginated list of all roles",
+        "/api/lookup/resources": {
+            "post": {
+                "description": "Get a page of the IDs of the resources on which the user may perform the action, honoring roles, user sets, resource sets, relationships, conditions and deny rules",
                 "consumes": [
                     "application/json"
                 ],
@@ -619,32 +650,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "permissions"
                 ],
-                "summary": "List roles",
+                "summary": "Lookup resources",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Number of items to return (default: 10)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to skip (default: 0)",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "User and action",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LookupResourcesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of roles",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ListRolesResponse"
+                            "$ref": "#/definitions/dto.LookupResourcesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -653,9 +699,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/lookup/users": {
             "post": {
-                "description": "Create a new role with the provided information",
+                "description": "Get every user that may perform the action on the resource, with the path that grants it (roles, user set, relationship tuples or default effect). Roles and user sets whose own permissions allow the action can be included.",
                 "consumes": [
                     "application/json"
                 ],
@@ -663,29 +711,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "permissions"
                 ],
-                "summary": "Create a new role",
+                "summary": "Lookup users",
                 "parameters": [
                     {
-                        "description": "Role information",
-                        "name": "role",
+                        "description": "Action and resource",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateRoleRequest"
+                            "$ref": "#/definitions/dto.LookupUsersRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Role created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.RoleResponse"
+                            "$ref": "#/definitions/dto.LookupUsersResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Resource or action not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -694,7 +751,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -705,37 +762,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/roles/{id}": {
-            "get": {
-                "description": "Retrieve a specific role by its unique identifier",
+        "/api/policy/apply": {
+            "post": {
+                "description": "Reconcile the tenant with a YAML or JSON policy document atomically. Entities are matched by name; declared entities are created or updated, and the actions, role parents and permissions of declared entities the document does not list are deleted. With prune, undeclared resources, resource sets, user sets and roles are deleted as well.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/yaml"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "policy"
                 ],
-                "summary": "Get a role by ID",
+                "summary": "Apply a policy document",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Role ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Policy document",
+                        "name": "document",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also delete resources, resource sets, user sets and roles the document does not declare",
+                        "name": "prune",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Role found",
+                        "description": "Changes made",
                         "schema": {
-                            "$ref": "#/definitions/dto.RoleResponse"
+                            "$ref": "#/definitions/dto.PolicyPlanResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid policy",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -743,8 +809,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Role not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -753,42 +819,129 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing role by its ID",
+            }
+        },
+        "/api/policy/plan": {
+            "post": {
+                "description": "List the creates, updates and deletes applying a YAML or JSON policy document would make, without making them.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/yaml"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "policy"
                 ],
-                "summary": "Update a role",
+                "summary": "Plan a policy document",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Role ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated role information",
-                        "name": "role",
+                        "description": "Policy document",
+                        "name": "document",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateRoleRequest"
+                            "type": "string"
                         }
-                    }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also delete resources, resource sets, user sets and roles the document does not declare",
+                        "name": "prune",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Role updated",
+                        "description": "Planned changes",
                         "schema": {
-                            "$ref": "#/definitions/dto.RoleResponse"
+                            "$ref": "#/definitions/dto.PolicyPlanResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid policy",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/relationships": {
+            "get": {
+                "description": "Get a paginated list of relationship tuples, optionally filtered by object, relation and subject",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relationships"
+                ],
+                "summary": "List relationships",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Object (type:id)",
+                        "name": "object",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Object type",
+                        "name": "object_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relation",
+                        "name": "relation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject (type:id or type:id#relation)",
+                        "name": "subject",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject type",
+                        "name": "subject_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of relationships",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListRelationshipsResponse"
                         }
                     },
                     "400": {
@@ -800,8 +953,49 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Role not found",
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Write a relationship tuple \"object#relation@subject\". The subject is either \"type:id\" or a userset \"type:id#relation\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relationships"
+                ],
+                "summary": "Create a relationship",
+                "parameters": [
+                    {
+                        "description": "Relationship",
+                        "name": "relationship",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RelationshipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Relationship created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RelationshipResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -821,7 +1015,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a role by its ID",
+                "description": "Delete a relationship tuple. Deleting a relationship that does not exist is a no-op.",
                 "consumes": [
                     "application/json"
                 ],
@@ -829,21 +1023,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "relationships"
                 ],
-                "summary": "Delete a role",
+                "summary": "Delete a relationship",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Role ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Relationship",
+                        "name": "relationship",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RelationshipRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "Role deleted"
+                        "description": "Relationship deleted"
                     },
                     "400": {
                         "description": "Bad request",
@@ -854,8 +1050,60 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Role not found",
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/relationships/check": {
+            "post": {
+                "description": "Walk the relationship graph to decide whether the subject holds the relation on the object, following at most depth hops",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relationships"
+                ],
+                "summary": "Check a relationship",
+                "parameters": [
+                    {
+                        "description": "Relationship check",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CheckRelationshipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Check result",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CheckRelationshipResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Maximum depth exceeded",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -875,9 +1123,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users": {
+        "/api/relationships/write": {
+            "post": {
+                "description": "Atomically write and delete a batch of relationship tuples. Either every change is applied or none is.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relationships"
+                ],
+                "summary": "Write relationships in batch",
+                "parameters": [
+                    {
+                        "description": "Relationships to write and delete",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.WriteRelationshipsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Relationships written"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/resource-sets": {
             "get": {
-                "description": "Get a paginated list of all users",
+                "description": "Get a paginated list of all resource sets",
                 "consumes": [
                     "application/json"
                 ],
@@ -885,9 +1182,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "resource-sets"
                 ],
-                "summary": "List users",
+                "summary": "List resource sets",
                 "parameters": [
                     {
                         "type": "integer",
@@ -904,9 +1201,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of users",
+                        "description": "List of resource sets",
                         "schema": {
-                            "$ref": "#/definitions/dto.ListUsersResponse"
+                            "$ref": "#/definitions/dto.ListResourceSetsResponse"
                         }
                     },
                     "500": {
@@ -921,7 +1218,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new user with the provided information",
+                "description": "Create a new resource set whose members are the resources and actions matching its conditions",
                 "consumes": [
                     "application/json"
                 ],
@@ -929,25 +1226,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "resource-sets"
                 ],
-                "summary": "Create a new user",
+                "summary": "Create a new resource set",
                 "parameters": [
                     {
-                        "description": "User information",
-                        "name": "user",
+                        "description": "Resource set information",
+                        "name": "resourceSet",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateUserRequest"
+                            "$ref": "#/definitions/dto.CreateResourceSetRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "User created",
+                        "description": "Resource set created",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserResponse"
+                            "$ref": "#/definitions/dto.ResourceSetResponse"
                         }
                     },
                     "400": {
@@ -971,9 +1268,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/{id}": {
+        "/api/resource-sets/{id}": {
             "get": {
-                "description": "Retrieve a specific user by their unique identifier",
+                "description": "Retrieve a specific resource set by its unique identifier",
                 "consumes": [
                     "application/json"
                 ],
@@ -981,13 +1278,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "resource-sets"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "Get a resource set by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Resource set ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -995,9 +1292,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "User found",
+                        "description": "Resource set found",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserResponse"
+                            "$ref": "#/definitions/dto.ResourceSetResponse"
                         }
                     },
                     "400": {
@@ -1010,7 +1307,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Resource set not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1021,7 +1318,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update an existing user by their ID",
+                "description": "Update an existing resource set by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1029,32 +1326,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "resource-sets"
                 ],
-                "summary": "Update a user",
+                "summary": "Update a resource set",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Resource set ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated user information",
-                        "name": "user",
+                        "description": "Updated resource set information",
+                        "name": "resourceSet",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                            "$ref": "#/definitions/dto.UpdateResourceSetRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User updated",
+                        "description": "Resource set updated",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserResponse"
+                            "$ref": "#/definitions/dto.ResourceSetResponse"
                         }
                     },
                     "400": {
@@ -1067,7 +1364,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Resource set not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1087,7 +1384,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a user by their ID",
+                "description": "Delete a resource set by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1095,21 +1392,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "resource-sets"
                 ],
-                "summary": "Delete a user",
+                "summary": "Delete a resource set",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Resource set ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "User deleted"
+                    "200": {
+                        "description": "Resource set soft deleted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResourceSetResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad request",
@@ -1121,7 +1421,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Resource set not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1140,223 +1440,4220 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.ActionResponse": {
-            "type": "object",
-            "properties": {
+        },
+        "/api/resource-sets/{id}/members/{resourceId}": {
+            "get": {
+                "description": "Evaluate the conditions of a resource set against the attributes of a resource and, optionally, one of its actions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resource-sets"
+                ],
+                "summary": "Check resource set membership",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resource set ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource ID",
+                        "name": "resourceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action ID",
+                        "name": "action_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Membership result",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResourceSetMembershipResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Resource set, resource or action not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/resources": {
+            "get": {
+                "description": "Get a paginated list of all resources",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resources"
+                ],
+                "summary": "List resources",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of resources",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListResourcesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new resource with the provided information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resources"
+                ],
+                "summary": "Create a new resource",
+                "parameters": [
+                    {
+                        "description": "Resource information",
+                        "name": "resource",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateResourceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Resource created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResourceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/resources/{id}": {
+            "get": {
+                "description": "Retrieve a specific resource by its unique identifier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resources"
+                ],
+                "summary": "Get a resource by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resource found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResourceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Resource not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing resource by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resources"
+                ],
+                "summary": "Update a resource",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated resource information",
+                        "name": "resource",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateResourceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resource updated",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResourceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Resource not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a resource by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resources"
+                ],
+                "summary": "Delete a resource",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resource soft deleted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResourceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Resource not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/resources/{id}/ancestors": {
+            "get": {
+                "description": "Get the ancestors of a resource, from its parent up to the root",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resources"
+                ],
+                "summary": "List resource ancestors",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of ancestors, nearest first",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListResourcesResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Resource not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/resources/{id}/children": {
+            "get": {
+                "description": "Get a paginated list of the resources nested directly under a resource",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resources"
+                ],
+                "summary": "List child resources",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of child resources",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListResourcesResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Resource not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/resources/{id}/parent": {
+            "put": {
+                "description": "Nest a resource under a new parent, or make it a root resource with a null parent_id. The resource then inherits the permissions granted on its new ancestors.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resources"
+                ],
+                "summary": "Move a resource",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New parent",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MoveResourceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resource moved",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResourceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Resource not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Move would create a cycle",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/revisions": {
+            "get": {
+                "description": "Get a paginated list of the revisions of the model, latest first. Every change to the authorization data produces a new revision.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "List revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of revisions",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListRevisionsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/revisions/{id}": {
+            "get": {
+                "description": "Get a revision with the state of every entity it changed before and after the change",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Get a revision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Revision ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revision found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevisionDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Revision not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/revisions/{id}/model": {
+            "get": {
+                "description": "Get the model of the tenant as it was right after a revision, in the bundle format used by exports",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Get the model as of a revision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Revision ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Bundle",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Revision not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/revisions/{id}/rollback": {
+            "post": {
+                "description": "Atomically restore the model of the tenant to its state right after a revision. The rollback is recorded as a new revision, which is returned; nothing is returned when nothing changed since.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Roll back to a revision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Revision ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revision recording the rollback",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevisionDetailResponse"
+                        }
+                    },
+                    "204": {
+                        "description": "Nothing changed since the revision"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Revision not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Entity cannot be restored",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles": {
+            "get": {
+                "description": "Get a paginated list of all roles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "List roles",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of roles",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListRolesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new role with the provided information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Create a new role",
+                "parameters": [
+                    {
+                        "description": "Role information",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Role created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles/{id}": {
+            "get": {
+                "description": "Retrieve a specific role by its unique identifier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Get a role by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing role by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Update a role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated role information",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role updated",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a role by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Delete a role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role soft deleted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles/{id}/effective": {
+            "get": {
+                "description": "Get a role together with every role it inherits from, directly or transitively, and the permissions granted through all of them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Get the effective role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Effective role",
+                        "schema": {
+                            "$ref": "#/definitions/dto.EffectiveRoleResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles/{id}/parents": {
+            "get": {
+                "description": "Get the roles a role directly inherits from",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "List parent roles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Parent roles",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListRolesResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Make a role inherit every grant of a parent role. Links that would create a cycle are rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Add a parent role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Parent role",
+                        "name": "parent",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RoleParentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Parent role added",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RoleParentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Parent role already assigned or cycle detected",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove an inheritance link between a role and its parent",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Remove a parent role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Parent role",
+                        "name": "parent",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RoleParentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Parent role removed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RoleParentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Parent role not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles/{id}/permissions": {
+            "get": {
+                "description": "Get every permission granted directly to a role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "List permissions of a role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Permissions of the role",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListPermissionsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Allow or deny a role to perform an action on a resource. The resource defaults to the resource the action belongs to. A resource set can be given instead of an action to cover every matching resource and action.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "Grant a permission to a role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Permission to grant",
+                        "name": "permission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.GrantPermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Permission granted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PermissionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Role, action or resource not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Permission already granted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a permission granted directly to a role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "Revoke a permission from a role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Permission to revoke",
+                        "name": "permission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevokePermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Permission revoked",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PermissionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Permission not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles/{id}/users": {
+            "get": {
+                "description": "Get every user that has been assigned a role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "List users of a role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users assigned to the role",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListUsersResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/schemas": {
+            "get": {
+                "description": "Get a paginated list of schema versions, latest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemas"
+                ],
+                "summary": "List schema versions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of schema versions",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListSchemasResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Validate a schema declaring object types, their relations and rewrite rules, and store it as the next version. Checks always use the latest version.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemas"
+                ],
+                "summary": "Write a schema",
+                "parameters": [
+                    {
+                        "description": "Schema definition",
+                        "name": "schema",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.WriteSchemaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Schema version created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SchemaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/schemas/latest": {
+            "get": {
+                "description": "Get the schema version currently used by checks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemas"
+                ],
+                "summary": "Get the latest schema",
+                "responses": {
+                    "200": {
+                        "description": "Schema found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SchemaResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No schema written",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/schemas/{version}": {
+            "get": {
+                "description": "Get a schema by version number",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemas"
+                ],
+                "summary": "Get a schema version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schema version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schema found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SchemaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/simulate": {
+            "post": {
+                "description": "Decide a list of checks, make the proposed changes (granted or revoked permissions, role assignments, deleted roles, attribute edits) and decide the checks again, returning both decisions. Nothing is persisted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "Simulate changes",
+                "parameters": [
+                    {
+                        "description": "Proposed changes and checks",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SimulateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Decisions before and after the changes",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SimulateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid change",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user-sets": {
+            "get": {
+                "description": "Get a paginated list of all user sets",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-sets"
+                ],
+                "summary": "List user sets",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of user sets",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListUserSetsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new user set whose members are the users matching its conditions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-sets"
+                ],
+                "summary": "Create a new user set",
+                "parameters": [
+                    {
+                        "description": "User set information",
+                        "name": "userSet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateUserSetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User set created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserSetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user-sets/{id}": {
+            "get": {
+                "description": "Retrieve a specific user set by its unique identifier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-sets"
+                ],
+                "summary": "Get a user set by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User set ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User set found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserSetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User set not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing user set by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-sets"
+                ],
+                "summary": "Update a user set",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User set ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated user set information",
+                        "name": "userSet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserSetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User set updated",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserSetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User set not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a user set by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-sets"
+                ],
+                "summary": "Delete a user set",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User set ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User set soft deleted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserSetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User set not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user-sets/{id}/members/{userId}": {
+            "get": {
+                "description": "Evaluate the conditions of a user set against the attributes of a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-sets"
+                ],
+                "summary": "Check user set membership",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User set ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Membership result",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserSetMembershipResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User set or user not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user-sets/{id}/permissions": {
+            "get": {
+                "description": "Get every permission granted to a user set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "List permissions of a user set",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User set ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Permissions of the user set",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListPermissionsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User set not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Allow or deny the members of a user set to perform an action on a resource. The resource defaults to the resource the action belongs to. A resource set can be given instead of an action to cover every matching resource and action.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "Grant a permission to a user set",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User set ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Permission to grant",
+                        "name": "permission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.GrantPermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Permission granted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PermissionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User set, action or resource not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Permission already granted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a permission granted to a user set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "Revoke a permission from a user set",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User set ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Permission to revoke",
+                        "name": "permission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevokePermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Permission revoked",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PermissionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Permission not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users": {
+            "get": {
+                "description": "Get a paginated list of all users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of users",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListUsersResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new user with the provided information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User information",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}": {
+            "get": {
+                "description": "Retrieve a specific user by their unique identifier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated user information",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User updated",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User soft deleted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/roles": {
+            "get": {
+                "description": "Get every role assigned to a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List roles of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Roles assigned to the user",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListRolesResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Assign an existing role to an existing user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Assign a role to a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role to assign",
+                        "name": "assignment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Role assigned",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserRoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User or role not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Role already assigned",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a role assignment from a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Revoke a role from a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role to revoke",
+                        "name": "assignment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role revoked",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserRoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Role assignment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/watch": {
+            "get": {
+                "description": "Stream every revision of the model committed after the given revision, or from now on, as Server-Sent Events in revision order. Each \"revision\" event carries the revision ID as its event ID and the changes to the watched entity types; revisions without any are skipped. Reconnecting clients resume with the Last-Event-ID header. Comments are sent periodically to keep the connection alive.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Watch changes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Revision to stream the following revisions of (default: the latest)",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated entity types to watch, such as user,role,resource,action,permission (default: all)",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Revision to resume after, overriding after",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of revision events",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WatchEventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Revision not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "condition.Trace": {
+            "type": "object",
+            "properties": {
+                "actual": {},
+                "attribute": {
+                    "type": "string"
+                },
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/condition.Trace"
+                    }
+                },
+                "expected": {},
+                "matched": {
+                    "type": "boolean"
+                },
+                "missing": {
+                    "type": "boolean"
+                },
+                "operator": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DecisionRule": {
+            "type": "object",
+            "properties": {
+                "decided_by": {
+                    "type": "string"
+                },
+                "effect": {
+                    "type": "string"
+                },
+                "granted": {
+                    "type": "boolean"
+                },
+                "permission_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DecisionTrace": {
+            "type": "object",
+            "properties": {
+                "combining_algorithm": {
+                    "type": "string"
+                },
+                "decision": {
+                    "$ref": "#/definitions/domain.DecisionRule"
+                },
+                "default_effect": {
+                    "type": "string"
+                },
+                "inherits_from": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PermissionTrace"
+                    }
+                },
+                "relationship": {
+                    "$ref": "#/definitions/domain.RelationshipTrace"
+                },
+                "resource_sets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SetTrace"
+                    }
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RoleTrace"
+                    }
+                },
+                "user_sets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SetTrace"
+                    }
+                }
+            }
+        },
+        "domain.PermissionTrace": {
+            "type": "object",
+            "properties": {
+                "applicable": {
+                    "type": "boolean"
+                },
+                "conditions": {
+                    "$ref": "#/definitions/condition.Trace"
+                },
+                "effect": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.RelationshipTrace": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "found": {
+                    "type": "boolean"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tuple": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.RoleTrace": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "inherited": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SetTrace": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "$ref": "#/definitions/condition.Trace"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "member": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AccessGrantResponse": {
+            "type": "object",
+            "properties": {
+                "path": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user:alice",
+                        "role:accountant",
+                        "permission:123e4567-e89b-12d3-a456-426614174000"
+                    ]
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "allowed by permission"
+                },
+                "subject_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "subject_name": {
+                    "type": "string",
+                    "example": "alice"
+                },
+                "subject_type": {
+                    "type": "string",
+                    "example": "user"
+                }
+            }
+        },
+        "dto.ActionResponse": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Permission to read the resource"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "read"
+                },
+                "resource_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                }
+            }
+        },
+        "dto.BatchPermissionCheckRequest": {
+            "type": "object",
+            "required": [
+                "checks"
+            ],
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.PermissionCheckRequest"
+                    }
+                }
+            }
+        },
+        "dto.BatchPermissionCheckResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BatchPermissionCheckResult"
+                    }
+                }
+            }
+        },
+        "dto.BatchPermissionCheckResult": {
+            "type": "object",
+            "properties": {
+                "context": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "error": {
+                    "type": "string"
+                },
+                "grant": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CheckRelationshipRequest": {
+            "type": "object",
+            "required": [
+                "object",
+                "relation",
+                "subject"
+            ],
+            "properties": {
+                "depth": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 25
+                },
+                "object": {
+                    "type": "string",
+                    "example": "document:readme"
+                },
+                "relation": {
+                    "type": "string",
+                    "example": "viewer"
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "user:alice"
+                }
+            }
+        },
+        "dto.CheckRelationshipResponse": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "path": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "document:readme#viewer@team:engineering#member",
+                        "team:engineering#member@user:alice"
+                    ]
+                }
+            }
+        },
+        "dto.CreateActionRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "resource_id"
+            ],
+            "properties": {
+                "attributes": {
+                    "type": "object"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Permission to read the resource"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "read"
+                },
+                "resource_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "dto.CreateResourceRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "attributes": {
+                    "type": "object"
+                },
+                "block_inheritance": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "combining_algorithm": {
+                    "description": "CombiningAlgorithm and DefaultEffect override the global policy for this resource",
+                    "type": "string",
+                    "enum": [
+                        "deny-overrides",
+                        "permit-overrides",
+                        "first-applicable"
+                    ],
+                    "example": "deny-overrides"
+                },
+                "default_effect": {
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ],
+                    "example": "deny"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "This is a sample resource description"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Sample Resource"
+                },
+                "parent_id": {
+                    "description": "ParentID nests the resource under another one, whose permissions it inherits unless BlockInheritance is set",
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "dto.CreateResourceSetRequest": {
+            "type": "object",
+            "required": [
+                "conditions",
+                "name"
+            ],
+            "properties": {
+                "conditions": {
+                    "type": "object"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Every resource classified as internal"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "internal-documents"
+                }
+            }
+        },
+        "dto.CreateRoleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "username"
+            ],
+            "properties": {
                 "attributes": {
                     "type": "object"
                 },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "dto.CreateUserSetRequest": {
+            "type": "object",
+            "required": [
+                "conditions",
+                "name"
+            ],
+            "properties": {
+                "conditions": {
+                    "type": "object"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Users working in the finance department"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "finance-team"
+                }
+            }
+        },
+        "dto.DecisionCacheStatsResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "entries": {
+                    "type": "integer",
+                    "example": 1250
+                },
+                "evictions": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "expirations": {
+                    "type": "integer",
+                    "example": 1840
+                },
+                "hit_rate": {
+                    "type": "number",
+                    "example": 0.94
+                },
+                "hits": {
+                    "type": "integer",
+                    "example": 48210
+                },
+                "invalidations": {
+                    "type": "integer",
+                    "example": 35
+                },
+                "max_entries": {
+                    "type": "integer",
+                    "example": 10000
+                },
+                "misses": {
+                    "type": "integer",
+                    "example": 3120
+                },
+                "ttl_seconds": {
+                    "type": "number",
+                    "example": 30
+                }
+            }
+        },
+        "dto.EffectiveRoleResponse": {
+            "type": "object",
+            "properties": {
+                "inherited_roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RoleResponse"
+                    }
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PermissionResponse"
+                    }
+                },
+                "role": {
+                    "$ref": "#/definitions/dto.RoleResponse"
+                }
+            }
+        },
+        "dto.GrantPermissionRequest": {
+            "type": "object",
+            "properties": {
+                "action_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "action_name": {
+                    "description": "ActionName restricts a resource set grant to the actions of that name",
+                    "type": "string",
+                    "example": "read"
+                },
+                "conditions": {
+                    "description": "Conditions restrict when the permission applies, see the README for the attributes available",
+                    "type": "object"
+                },
+                "effect": {
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ],
+                    "example": "allow"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "resource_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "resource_set_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "dto.ImportBundleResponse": {
+            "type": "object",
+            "properties": {
+                "summary": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/dto.ImportCountsResponse"
+                    }
+                }
+            }
+        },
+        "dto.ImportCountsResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "updated": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "dto.ListActionsResponse": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ActionResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "dto.ListPermissionsResponse": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PermissionResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "dto.ListRelationshipsResponse": {
+            "type": "object",
+            "properties": {
+                "relationships": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RelationshipResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "dto.ListResourceSetsResponse": {
+            "type": "object",
+            "properties": {
+                "resource_sets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ResourceSetResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "dto.ListResourcesResponse": {
+            "type": "object",
+            "properties": {
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ResourceResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "dto.ListRevisionsResponse": {
+            "type": "object",
+            "properties": {
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RevisionResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "dto.ListRolesResponse": {
+            "type": "object",
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RoleResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "dto.ListSchemasResponse": {
+            "type": "object",
+            "properties": {
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SchemaResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "dto.ListUserSetsResponse": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "user_sets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserSetResponse"
+                    }
+                }
+            }
+        },
+        "dto.ListUsersResponse": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserResponse"
+                    }
+                }
+            }
+        },
+        "dto.LookupResourcesRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "user"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "edit"
+                },
+                "context": {
+                    "type": "object"
+                },
+                "limit": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 10
+                },
+                "offset": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "user": {
+                    "type": "string",
+                    "example": "alice"
+                }
+            }
+        },
+        "dto.LookupResourcesResponse": {
+            "type": "object",
+            "properties": {
+                "resource_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "dto.LookupUsersRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "resource"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "delete"
+                },
+                "context": {
+                    "type": "object"
+                },
+                "include_roles": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "include_user_sets": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "resource": {
+                    "type": "string",
+                    "example": "invoices"
+                }
+            }
+        },
+        "dto.LookupUsersResponse": {
+            "type": "object",
+            "properties": {
+                "subjects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AccessGrantResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "dto.MoveResourceRequest": {
+            "type": "object",
+            "properties": {
+                "parent_id": {
+                    "description": "ParentID is the new parent of the resource; null makes it a root resource",
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "dto.PermissionCheckRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "resource",
+                "user"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "at_least_as_fresh": {
+                    "description": "AtLeastAsFresh is the revision token returned by a change the decision must reflect",
+                    "type": "string",
+                    "example": "cjE6NDI"
+                },
+                "context": {
+                    "description": "Context carries request-time attributes (client IP, time, device, ...) permission conditions are evaluated against",
+                    "type": "object"
+                },
+                "fully_consistent": {
+                    "description": "FullyConsistent decides the check from the latest data rather than from the decision cache",
+                    "type": "boolean"
+                },
+                "resource": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PermissionCheckResponse": {
+            "type": "object",
+            "properties": {
+                "context": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "grant": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.PermissionExplainResponse": {
+            "type": "object",
+            "properties": {
+                "context": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "grant": {
+                    "type": "boolean"
+                },
+                "trace": {
+                    "$ref": "#/definitions/domain.DecisionTrace"
+                }
+            }
+        },
+        "dto.PermissionResponse": {
+            "type": "object",
+            "properties": {
+                "action_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "action_name": {
+                    "type": "string",
+                    "example": "read"
+                },
+                "conditions": {
+                    "type": "object"
+                },
                 "created_at": {
                     "type": "string",
                     "example": "2025-04-19T12:00:00Z"
                 },
-                "description": {
+                "effect": {
                     "type": "string",
-                    "example": "Permission to read the resource"
+                    "example": "allow"
                 },
                 "id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "name": {
-                    "type": "string",
-                    "example": "read"
+                "priority": {
+                    "type": "integer",
+                    "example": 10
                 },
                 "resource_id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
+                "resource_set_id": {
+                    "type": "string"
+                },
+                "role_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
                 "updated_at": {
                     "type": "string",
                     "example": "2025-04-19T12:00:00Z"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_set_id": {
+                    "type": "string"
                 }
             }
         },
-        "dto.CreateActionRequest": {
+        "dto.PolicyChangeCountsResponse": {
+            "type": "object",
+            "properties": {
+                "create": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "delete": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "update": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.PolicyChangeResponse": {
+            "type": "object",
+            "properties": {
+                "entity_type": {
+                    "type": "string",
+                    "example": "permission"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "role:accountant allow invoices#read"
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "create"
+                }
+            }
+        },
+        "dto.PolicyPlanResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PolicyChangeResponse"
+                    }
+                },
+                "summary": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/dto.PolicyChangeCountsResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "dto.RelationshipRequest": {
             "type": "object",
             "required": [
-                "name",
-                "resource_id"
+                "object",
+                "relation",
+                "subject"
             ],
+            "properties": {
+                "object": {
+                    "type": "string",
+                    "example": "document:readme"
+                },
+                "relation": {
+                    "type": "string",
+                    "example": "viewer"
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "team:engineering#member"
+                }
+            }
+        },
+        "dto.RelationshipResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "object": {
+                    "type": "string",
+                    "example": "document:readme"
+                },
+                "relation": {
+                    "type": "string",
+                    "example": "viewer"
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "team:engineering#member"
+                },
+                "tuple": {
+                    "type": "string",
+                    "example": "document:readme#viewer@team:engineering#member"
+                }
+            }
+        },
+        "dto.ResourceResponse": {
+            "type": "object",
             "properties": {
                 "attributes": {
                     "type": "object"
                 },
+                "block_inheritance": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "combining_algorithm": {
+                    "description": "CombiningAlgorithm and DefaultEffect are empty when the resource uses the global policy",
+                    "type": "string",
+                    "example": "deny-overrides"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                },
+                "default_effect": {
+                    "type": "string",
+                    "example": "deny"
+                },
                 "description": {
                     "type": "string",
-                    "example": "Permission to read the resource"
+                    "example": "This is a sample resource description"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
                 "name": {
                     "type": "string",
-                    "example": "read"
+                    "example": "Sample Resource"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                }
+            }
+        },
+        "dto.ResourceSetMembershipResponse": {
+            "type": "object",
+            "properties": {
+                "action_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "member": {
+                    "type": "boolean",
+                    "example": true
                 },
                 "resource_id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "resource_set_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 }
             }
         },
-        "dto.CreateResourceRequest": {
+        "dto.ResourceSetResponse": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Every resource classified as internal"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "internal-documents"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                }
+            }
+        },
+        "dto.RevisionChangeResponse": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "entity_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "entity_type": {
+                    "type": "string",
+                    "example": "role"
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "update"
+                }
+            }
+        },
+        "dto.RevisionDetailResponse": {
+            "type": "object",
+            "properties": {
+                "change_count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RevisionChangeResponse"
+                    }
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "update role"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "dto.RevisionResponse": {
+            "type": "object",
+            "properties": {
+                "change_count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "update role"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "dto.RevokePermissionRequest": {
             "type": "object",
             "required": [
-                "name"
+                "permission_id"
             ],
             "properties": {
-                "attributes": {
-                    "type": "object"
+                "permission_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "dto.RoleParentRequest": {
+            "type": "object",
+            "required": [
+                "parent_id"
+            ],
+            "properties": {
+                "parent_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "dto.RoleParentResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
                 },
-                "description": {
+                "parent_id": {
                     "type": "string",
-                    "example": "This is a sample resource description"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "name": {
+                "role_id": {
                     "type": "string",
-                    "example": "Sample Resource"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 }
             }
         },
-        "dto.CreateRoleRequest": {
+        "dto.RoleResponse": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                },
                 "description": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Administrator role with full access"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "admin"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
                 }
             }
         },
-        "dto.CreateUserRequest": {
+        "dto.SchemaResponse": {
             "type": "object",
-            "required": [
-                "username"
-            ],
             "properties": {
-                "attributes": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                },
+                "definition": {
                     "type": "object"
                 },
-                "username": {
+                "id": {
                     "type": "string",
-                    "example": "john_doe"
-                }
-            }
-        },
-        "dto.ListActionsResponse": {
-            "type": "object",
-            "properties": {
-                "actions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.ActionResponse"
-                    }
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "total": {
+                "version": {
                     "type": "integer",
-                    "example": 10
+                    "example": 3
                 }
             }
         },
-        "dto.ListResourcesResponse": {
+        "dto.SimulateRequest": {
             "type": "object",
+            "required": [
+                "changes",
+                "checks"
+            ],
             "properties": {
-                "resources": {
+                "changes": {
                     "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/dto.ResourceResponse"
+                        "$ref": "#/definitions/dto.SimulationChangeRequest"
                     }
                 },
-                "total": {
-                    "type": "integer",
-                    "example": 10
-                }
-            }
-        },
-        "dto.ListRolesResponse": {
-            "type": "object",
-            "properties": {
-                "roles": {
+                "checks": {
                     "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/dto.RoleResponse"
+                        "$ref": "#/definitions/dto.PermissionCheckRequest"
                     }
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 10
                 }
             }
         },
-        "dto.ListUsersResponse": {
+        "dto.SimulateResponse": {
             "type": "object",
             "properties": {
-                "total": {
+                "changed": {
                     "type": "integer",
-                    "example": 10
+                    "example": 1
                 },
-                "users": {
+                "results": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.UserResponse"
+                        "$ref": "#/definitions/dto.SimulationResultResponse"
                     }
                 }
             }
         },
-        "dto.ResourceResponse": {
+        "dto.SimulationChangeRequest": {
             "type": "object",
+            "required": [
+                "type"
+            ],
             "properties": {
                 "attributes": {
                     "type": "object"
                 },
-                "created_at": {
-                    "type": "string",
-                    "example": "2025-04-19T12:00:00Z"
+                "entity_id": {
+                    "type": "string"
                 },
-                "description": {
+                "entity_type": {
                     "type": "string",
-                    "example": "This is a sample resource description"
+                    "enum": [
+                        "user",
+                        "resource",
+                        "action"
+                    ],
+                    "example": "user"
                 },
-                "id": {
+                "permission": {
+                    "$ref": "#/definitions/dto.GrantPermissionRequest"
+                },
+                "permission_id": {
+                    "type": "string"
+                },
+                "role_id": {
+                    "description": "RoleID or UserSetID is the subject of grant_permission and revoke_permission",
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "name": {
+                "type": {
                     "type": "string",
-                    "example": "Sample Resource"
+                    "enum": [
+                        "grant_permission",
+                        "revoke_permission",
+                        "assign_role",
+                        "revoke_role",
+                        "delete_role",
+                        "set_attributes"
+                    ],
+                    "example": "revoke_role"
                 },
-                "updated_at": {
+                "user_id": {
                     "type": "string",
-                    "example": "2025-04-19T12:00:00Z"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "user_set_id": {
+                    "type": "string"
                 }
             }
         },
-        "dto.RoleResponse": {
+        "dto.SimulationResultResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "action": {
                     "type": "string",
-                    "example": "2025-04-19T12:00:00Z"
+                    "example": "read"
                 },
-                "description": {
-                    "type": "string",
-                    "example": "Administrator role with full access"
+                "after": {
+                    "$ref": "#/definitions/dto.BatchPermissionCheckResult"
                 },
-                "id": {
-                    "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                "before": {
+                    "$ref": "#/definitions/dto.BatchPermissionCheckResult"
                 },
-                "name": {
+                "changed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "resource": {
                     "type": "string",
-                    "example": "admin"
+                    "example": "invoices"
                 },
-                "updated_at": {
+                "user": {
                     "type": "string",
-                    "example": "2025-04-19T12:00:00Z"
+                    "example": "john_doe"
                 }
             }
         },
@@ -1393,6 +5690,29 @@ const docTemplate = `{
                 "attributes": {
                     "type": "object"
                 },
+                "block_inheritance": {
+                    "description": "BlockInheritance stops permissions granted on the ancestors of the resource from applying to it",
+                    "type": "boolean",
+                    "example": false
+                },
+                "combining_algorithm": {
+                    "description": "CombiningAlgorithm and DefaultEffect override the global policy for this resource",
+                    "type": "string",
+                    "enum": [
+                        "deny-overrides",
+                        "permit-overrides",
+                        "first-applicable"
+                    ],
+                    "example": "first-applicable"
+                },
+                "default_effect": {
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ],
+                    "example": "deny"
+                },
                 "description": {
                     "type": "string",
                     "example": "This is an updated resource description"
@@ -1403,6 +5723,25 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UpdateResourceSetRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "conditions": {
+                    "type": "object"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Every resource classified as internal"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "internal-documents"
+                }
+            }
+        },
         "dto.UpdateRoleRequest": {
             "type": "object",
             "required": [
@@ -1432,6 +5771,25 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UpdateUserSetRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "conditions": {
+                    "type": "object"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Users working in the finance department"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "finance-team"
+                }
+            }
+        },
         "dto.UserResponse": {
             "type": "object",
             "properties": {
@@ -1455,6 +5813,139 @@ const docTemplate = `{
                     "example": "john_doe"
                 }
             }
+        },
+        "dto.UserRoleRequest": {
+            "type": "object",
+            "required": [
+                "role_id"
+            ],
+            "properties": {
+                "role_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "dto.UserRoleResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "role_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "dto.UserSetMembershipResponse": {
+            "type": "object",
+            "properties": {
+                "member": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "user_set_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "dto.UserSetResponse": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Users working in the finance department"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "finance-team"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                }
+            }
+        },
+        "dto.WatchEventResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RevisionChangeResponse"
+                    }
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-19T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "update role"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "token": {
+                    "type": "string",
+                    "example": "cjE6NDI"
+                }
+            }
+        },
+        "dto.WriteRelationshipsRequest": {
+            "type": "object",
+            "properties": {
+                "deletes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RelationshipRequest"
+                    }
+                },
+                "writes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RelationshipRequest"
+                    }
+                }
+            }
+        },
+        "dto.WriteSchemaRequest": {
+            "type": "object",
+            "required": [
+                "definition"
+            ],
+            "properties": {
+                "definition": {
+                    "type": "object"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,43 @@
+// Command validra runs Validra Engine tools that do not need the server or a database.
+//
+//	validra test [-v] suite.yaml...
+//
+// test evaluates the assertions of policy test suites against the permission engine, holding the
+// model in memory, and exits with a non-zero status when an assertion fails.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// Exit statuses
+const (
+	exitFailed = 1 // an assertion failed
+	exitError  = 2 // the command could not run
+)
+
+const usage = `Usage: validra <command> [arguments]
+
+Commands:
+  test    evaluate policy test suites
+
+Run "validra <command> -h" for the arguments of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitError)
+	}
+
+	switch os.Args[1] {
+	case "test":
+		os.Exit(runTest(os.Args[2:]))
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "validra: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(exitError)
+	}
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/arifsetyawan/validra/src/config"
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/internal/repository/memory"
+	"github.com/arifsetyawan/validra/src/internal/service"
+	"github.com/arifsetyawan/validra/src/pkg/policytest"
+)
+
+const testUsage = `Usage: validra test [flags] suite.yaml...
+
+Loads the policy model of each suite in memory, runs its tests through the permission engine
+and reports the tests whose decision is not the expected one, with the rule that decided it.
+The combining policy defaults to POLICY_COMBINING_ALGORITHM and POLICY_DEFAULT_EFFECT, like
+the server.
+
+Flags:
+`
+
+// runTest runs the test command and returns the exit status
+func runTest(args []string) int {
+	cfg := config.Load()
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	verbose := flags.Bool("v", false, "explain the decision of every test, not only of failed ones")
+	algorithm := flags.String("combining-algorithm", cfg.Policy.CombiningAlgorithm, "global permission combining algorithm")
+	defaultEffect := flags.String("default-effect", cfg.Policy.DefaultEffect, "effect applied when no permission matches")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), testUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return exitError
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitError
+	}
+
+	policy := domain.CombiningPolicy{
+		Algorithm:     *algorithm,
+		DefaultEffect: *defaultEffect,
+	}
+	if err := policy.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "validra: invalid policy configuration: %v\n", err)
+		return exitError
+	}
+
+	status := 0
+	for _, path := range flags.Args() {
+		failed, err := runSuite(path, policy, *verbose)
+		switch {
+		case err != nil:
+			fmt.Printf("FAIL\t%s\t%v\n", path, err)
+			status = exitError
+		case failed > 0 && status == 0:
+			status = exitFailed
+		}
+	}
+	return status
+}
+
+// runSuite loads a suite and runs its tests, returning how many failed
+func runSuite(path string, policy domain.CombiningPolicy, verbose bool) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	suite, err := policytest.Parse(data)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx := context.Background()
+	e := newEngine(policy)
+	if err := e.load(ctx, suite, filepath.Dir(path)); err != nil {
+		return 0, err
+	}
+
+	failed := 0
+	for _, test := range suite.Tests {
+		granted, _, trace, err := e.permissions.ExplainPermission(ctx, test.User, test.Action, test.Resource, test.Context)
+		if err != nil {
+			failed++
+			fmt.Printf("--- FAIL: %s\n    error: %v\n", test.Name, err)
+			continue
+		}
+
+		got := policytest.ExpectDeny
+		if granted {
+			got = policytest.ExpectAllow
+		}
+		if got != test.Expect {
+			failed++
+			fmt.Printf("--- FAIL: %s\n    expected %s, got %s\n", test.Name, test.Expect, got)
+			e.explain(test, trace)
+			continue
+		}
+
+		fmt.Printf("--- PASS: %s\n", test.Name)
+		if verbose {
+			e.explain(test, trace)
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("FAIL\t%s\t%d of %d tests failed\n", path, failed, len(suite.Tests))
+	} else {
+		fmt.Printf("ok\t%s\t%d tests passed\n", path, len(suite.Tests))
+	}
+	return failed, nil
+}
+
+// engine is the permission engine of a suite, with its model held in memory
+type engine struct {
+	resourceRepo  domain.ResourceRepository
+	roleRepo      domain.RoleRepository
+	userRepo      domain.UserRepository
+	users         *service.UserService
+	userRoles     *service.UserRoleService
+	relationships *service.RelationshipService
+	schemas       *service.SchemaService
+	policies      *service.PolicyService
+	bundles       *service.BundleService
+	permissions   *service.PermissionService
+
+	// ids maps "resource:<name>" and "user:<username>" to the ID of the entity and names replaces
+	// the IDs in tuples with names, as relationships refer to resources and users by ID
+	ids   map[string]string
+	names *strings.Replacer
+}
+
+// newEngine wires the services the server uses to a new in-memory store
+func newEngine(policy domain.CombiningPolicy) *engine {
+	store := memory.NewStore()
+	resourceRepo := memory.NewResourceRepository(store)
+	actionRepo := memory.NewActionRepository(store)
+	roleRepo := memory.NewRoleRepository(store)
+	userRepo := memory.NewUserRepository(store)
+	userRoleRepo := memory.NewUserRoleRepository(store)
+	userSetRepo := memory.NewUserSetRepository(store)
+	resourceSetRepo := memory.NewResourceSetRepository(store)
+	permissionRepo := memory.NewPermissionRepository(store)
+	tupleRepo := memory.NewRelationTupleRepository(store)
+	schemaRepo := memory.NewRelationSchemaRepository(store)
+	revisionService := service.NewRevisionService(store, memory.NewRevisionRepository(store))
+
+	return &engine{
+		resourceRepo:  resourceRepo,
+		roleRepo:      roleRepo,
+		userRepo:      userRepo,
+		users:         service.NewUserService(userRepo, revisionService),
+		userRoles:     service.NewUserRoleService(userRoleRepo, userRepo, roleRepo, revisionService),
+		relationships: service.NewRelationshipService(tupleRepo, schemaRepo, revisionService),
+		schemas:       service.NewSchemaService(schemaRepo, revisionService),
+		policies: service.NewPolicyService(
+			revisionService,
+			resourceRepo,
+			actionRepo,
+			roleRepo,
+			userRoleRepo,
+			userSetRepo,
+			resourceSetRepo,
+			permissionRepo,
+			revisionService,
+		),
+		bundles: service.NewBundleService(
+			revisionService,
+			resourceRepo,
+			actionRepo,
+			roleRepo,
+			userRepo,
+			userRoleRepo,
+			userSetRepo,
+			resourceSetRepo,
+			permissionRepo,
+			tupleRepo,
+			schemaRepo,
+			revisionService,
+		),
+		permissions: service.NewPermissionService(
+			userRepo,
+			actionRepo,
+			resourceRepo,
+			roleRepo,
+			permissionRepo,
+			userRoleRepo,
+			userSetRepo,
+			resourceSetRepo,
+			tupleRepo,
+			schemaRepo,
+			policy,
+			revisionService,
+			nil,
+		),
+	}
+}
+
+// load builds the model of a suite: the bundle, then the policy, the schema, the users and their
+// roles and finally the relationships. Paths are relative to dir. Relationships may name resources
+// and users instead of identifying them, such as "resource:invoices#read@user:alice".
+func (e *engine) load(ctx context.Context, suite *policytest.Suite, dir string) error {
+	if suite.Bundle != "" {
+		f, err := os.Open(resolvePath(dir, suite.Bundle))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := e.bundles.Import(ctx, f, domain.ImportConflictFail); err != nil {
+			return fmt.Errorf("bundle %s: %w", suite.Bundle, err)
+		}
+	}
+
+	if suite.Policy != "" {
+		data, err := os.ReadFile(resolvePath(dir, suite.Policy))
+		if err != nil {
+			return err
+		}
+		if _, err := e.policies.ApplyDocument(ctx, data, false); err != nil {
+			return fmt.Errorf("policy %s: %w", suite.Policy, err)
+		}
+	}
+
+	if len(suite.Schema) > 0 && string(suite.Schema) != "null" {
+		if _, err := e.schemas.WriteSchema(ctx, suite.Schema); err != nil {
+			return fmt.Errorf("schema: %w", err)
+		}
+	}
+
+	roles, err := e.roleRepo.List(ctx, 0, 0)
+	if err != nil {
+		return err
+	}
+	roleIDs := make(map[string]string, len(roles))
+	for _, role := range roles {
+		if role.DeletedAt == nil {
+			roleIDs[role.Name] = role.ID
+		}
+	}
+
+	for _, u := range suite.Users {
+		user := &domain.User{
+			Username:   u.Username,
+			Attributes: u.Attributes,
+		}
+		if err := e.users.CreateUser(ctx, user); err != nil {
+			return fmt.Errorf("user %q: %w", u.Username, err)
+		}
+		for _, name := range u.Roles {
+			roleID, ok := roleIDs[name]
+			if !ok {
+				return fmt.Errorf("user %q: role %q not found", u.Username, name)
+			}
+			if _, err := e.userRoles.AssignRole(ctx, user.ID, roleID); err != nil {
+				return fmt.Errorf("user %q: role %q: %w", u.Username, name, err)
+			}
+		}
+	}
+
+	if err := e.index(ctx); err != nil {
+		return err
+	}
+
+	if len(suite.Relationships) > 0 {
+		tuples := make([]*domain.RelationTuple, len(suite.Relationships))
+		for i, r := range suite.Relationships {
+			tuple, err := domain.ParseRelationTuple(r)
+			if err != nil {
+				return fmt.Errorf("relationship %q: %w", r, err)
+			}
+			tuple.ObjectID = e.resolve(tuple.ObjectType, tuple.ObjectID)
+			tuple.SubjectID = e.resolve(tuple.SubjectType, tuple.SubjectID)
+			tuples[i] = tuple
+		}
+		if err := e.relationships.WriteRelationships(ctx, tuples, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// index maps the names of the active resources and users to their IDs
+func (e *engine) index(ctx context.Context) error {
+	resources, err := e.resourceRepo.List(ctx, 0, 0)
+	if err != nil {
+		return err
+	}
+	users, err := e.userRepo.List(ctx, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	e.ids = make(map[string]string, len(resources)+len(users))
+	var names []string
+	add := func(objectType, id, name string) {
+		e.ids[objectType+":"+name] = id
+		names = append(names, objectType+":"+id, objectType+":"+name)
+	}
+	for _, resource := range resources {
+		if resource.DeletedAt == nil {
+			add(domain.ObjectTypeResource, resource.ID, resource.Name)
+		}
+	}
+	for _, user := range users {
+		if user.DeletedAt == nil {
+			add(domain.SubjectTypeUser, user.ID, user.Username)
+		}
+	}
+	e.names = strings.NewReplacer(names...)
+
+	return nil
+}
+
+// resolve returns the ID of the resource or user an object names, or the object ID unchanged
+func (e *engine) resolve(objectType, objectID string) string {
+	if id, ok := e.ids[objectType+":"+objectID]; ok {
+		return id
+	}
+	return objectID
+}
+
+// resolvePath resolves a path relative to dir unless it is absolute
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// explain prints what a check considered and the rule that decided it. Subjects are named
+// instead of being identified by the IDs the in-memory model generated.
+func (e *engine) explain(test policytest.Test, trace *domain.DecisionTrace) {
+	names := map[string]string{}
+	roles := make([]string, len(trace.Roles))
+	for i, role := range trace.Roles {
+		names[domain.GrantSubjectRole+":"+role.ID] = "role " + role.Name
+		roles[i] = role.Name
+		if role.Inherited {
+			roles[i] += " (inherited)"
+		}
+	}
+	var userSets []string
+	for _, userSet := range trace.UserSets {
+		names[domain.GrantSubjectUserSet+":"+userSet.ID] = "user set " + userSet.Name
+		if userSet.Member {
+			userSets = append(userSets, userSet.Name)
+		}
+	}
+	var resourceSets []string
+	for _, resourceSet := range trace.ResourceSets {
+		if resourceSet.Member {
+			resourceSets = append(resourceSets, resourceSet.Name)
+		}
+	}
+	subjectName := func(subject string) string {
+		if name, ok := names[subject]; ok {
+			return name
+		}
+		if strings.HasPrefix(subject, domain.GrantSubjectUser+":") {
+			return "user " + test.User
+		}
+		return subject
+	}
+
+	decision := trace.Decision
+	fmt.Printf("    decided by %s: %s", decision.DecidedBy, decision.Reason)
+	for _, p := range trace.Permissions {
+		if p.ID == decision.PermissionID {
+			fmt.Printf(" (%s, %s, priority %d)", subjectName(p.Subject), p.Effect, p.Priority)
+		}
+	}
+	fmt.Println()
+
+	if len(roles) > 0 {
+		fmt.Printf("    roles: %s\n", strings.Join(roles, ", "))
+	}
+	if len(userSets) > 0 {
+		fmt.Printf("    user sets: %s\n", strings.Join(userSets, ", "))
+	}
+	if len(resourceSets) > 0 {
+		fmt.Printf("    resource sets: %s\n", strings.Join(resourceSets, ", "))
+	}
+	if len(trace.InheritsFrom) > 0 {
+		fmt.Printf("    inherits from: %s\n", strings.Join(trace.InheritsFrom, ", "))
+	}
+	for _, p := range trace.Permissions {
+		fmt.Printf("    permission of %s, %s, priority %d: %s\n", subjectName(p.Subject), p.Effect, p.Priority, p.Reason)
+	}
+	if r := trace.Relationship; r != nil {
+		found := "not found"
+		if r.Found {
+			found = "found via " + strings.Join(r.Path, ", ")
+		}
+		fmt.Printf("    relationship %s: %s\n", e.names.Replace(r.Tuple), e.names.Replace(found))
+		if r.Error != "" {
+			fmt.Printf("    relationship error: %s\n", r.Error)
+		}
+	}
+}
@@ -12,6 +12,9 @@ import (
 type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
+	Policy   PolicyConfig
+	Tenant   TenantConfig
+	Cache    CacheConfig
 }
 
 // ServerConfig holds server-related configuration
@@ -32,6 +35,26 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
+// PolicyConfig holds the global permission combining configuration
+type PolicyConfig struct {
+	CombiningAlgorithm string // deny-overrides, permit-overrides or first-applicable
+	DefaultEffect      string // allow or deny, applied when no permission matches
+	File               string // Policy document applied on startup, if any
+	Prune              bool   // Delete entities the startup policy document does not declare
+}
+
+// TenantConfig holds the configuration used to resolve the tenant of a request
+type TenantConfig struct {
+	Header  string // Request header carrying the tenant ID
+	Default string // Tenant used when a request names none; empty makes the tenant mandatory
+}
+
+// CacheConfig holds the configuration of the permission decision cache
+type CacheConfig struct {
+	TTL        int // Seconds a decision is cached for; 0 disables the cache
+	MaxEntries int // Decisions kept at most, the least recently used are dropped first
+}
+
 // Load loads configuration from environment variables
 // It first attempts to load from a .env file if it exists
 func Load() *Config {
@@ -56,6 +79,20 @@ func Load() *Config {
 			Name:     getEnv("DB_NAME", "validra"),
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
 		},
+		Policy: PolicyConfig{
+			CombiningAlgorithm: getEnv("POLICY_COMBINING_ALGORITHM", "deny-overrides"),
+			DefaultEffect:      getEnv("POLICY_DEFAULT_EFFECT", "deny"),
+			File:               getEnv("POLICY_FILE", ""),
+			Prune:              getEnvAsBool("POLICY_PRUNE", false),
+		},
+		Tenant: TenantConfig{
+			Header:  getEnv("TENANT_HEADER", "X-Tenant-ID"),
+			Default: getEnv("TENANT_DEFAULT", "default"),
+		},
+		Cache: CacheConfig{
+			TTL:        getEnvAsInt("DECISION_CACHE_TTL", 30),
+			MaxEntries: getEnvAsInt("DECISION_CACHE_MAX_ENTRIES", 10000),
+		},
 	}
 }
 
@@ -75,3 +112,12 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvAsBool retrieves environment variables as bool or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/arifsetyawan/validra/src/internal/domain"
+
+// ImportCountsResponse represents what happened to the entities of one section of an imported bundle
+type ImportCountsResponse struct {
+	Created int `json:"created" example:"12"`
+	Updated int `json:"updated" example:"0"`
+	Skipped int `json:"skipped" example:"3"`
+}
+
+// ImportBundleResponse represents the outcome of an import per bundle section
+type ImportBundleResponse struct {
+	Summary map[string]ImportCountsResponse `json:"summary"`
+}
+
+// ToImportBundleResponse converts an import result to ImportBundleResponse
+func ToImportBundleResponse(result *domain.ImportResult) ImportBundleResponse {
+	summary := make(map[string]ImportCountsResponse, len(result.Summary))
+	for section, counts := range result.Summary {
+		summary[section] = ImportCountsResponse{
+			Created: counts.Created,
+			Updated: counts.Updated,
+			Skipped: counts.Skipped,
+		}
+	}
+
+	return ImportBundleResponse{
+		Summary: summary,
+	}
+}
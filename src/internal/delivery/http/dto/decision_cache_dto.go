@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// DecisionCacheStatsResponse represents the use of the permission decision cache
+type DecisionCacheStatsResponse struct {
+	Enabled       bool    `json:"enabled" example:"true"`
+	Entries       int     `json:"entries" example:"1250"`
+	MaxEntries    int     `json:"max_entries" example:"10000"`
+	TTLSeconds    float64 `json:"ttl_seconds" example:"30"`
+	Hits          uint64  `json:"hits" example:"48210"`
+	Misses        uint64  `json:"misses" example:"3120"`
+	HitRate       float64 `json:"hit_rate" example:"0.94"`
+	Evictions     uint64  `json:"evictions" example:"0"`
+	Expirations   uint64  `json:"expirations" example:"1840"`
+	Invalidations uint64  `json:"invalidations" example:"35"`
+}
+
+// ToDecisionCacheStatsResponse converts domain.DecisionCacheStats to DecisionCacheStatsResponse
+func ToDecisionCacheStatsResponse(s domain.DecisionCacheStats) DecisionCacheStatsResponse {
+	response := DecisionCacheStatsResponse{
+		Enabled:       s.Enabled,
+		Entries:       s.Entries,
+		MaxEntries:    s.MaxEntries,
+		TTLSeconds:    s.TTL.Seconds(),
+		Hits:          s.Hits,
+		Misses:        s.Misses,
+		Evictions:     s.Evictions,
+		Expirations:   s.Expirations,
+		Invalidations: s.Invalidations,
+	}
+	if lookups := s.Hits + s.Misses; lookups > 0 {
+		response.HitRate = float64(s.Hits) / float64(lookups)
+	}
+	return response
+}
@@ -1,10 +1,36 @@
 package dto
 
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
 // PermissionCheckRequest represents the request structure for checking permissions
 type PermissionCheckRequest struct {
 	User     string `json:"user" validate:"required"`
 	Action   string `json:"action" validate:"required"`
 	Resource string `json:"resource" validate:"required"`
+	// Context carries request-time attributes (client IP, time, device, ...) permission conditions are evaluated against
+	Context map[string]interface{} `json:"context,omitempty" swaggertype:"object"`
+	// AtLeastAsFresh is the revision token returned by a change the decision must reflect
+	AtLeastAsFresh string `json:"at_least_as_fresh,omitempty" validate:"excluded_with=FullyConsistent" example:"cjE6NDI"`
+	// FullyConsistent decides the check from the latest data rather than from the decision cache
+	FullyConsistent bool `json:"fully_consistent,omitempty"`
+}
+
+// ToConsistencyDomain converts the consistency options of a PermissionCheckRequest to domain.Consistency
+func (r *PermissionCheckRequest) ToConsistencyDomain() (domain.Consistency, error) {
+	consistency := domain.Consistency{FullyConsistent: r.FullyConsistent}
+	if r.AtLeastAsFresh != "" {
+		id, err := domain.ParseRevisionToken(r.AtLeastAsFresh)
+		if err != nil {
+			return domain.Consistency{}, err
+		}
+		consistency.AtLeastAsFresh = id
+	}
+	return consistency, nil
 }
 
 // PermissionCheckResponse represents the response structure for permission check results
@@ -12,3 +38,214 @@ type PermissionCheckResponse struct {
 	Grant   bool                   `json:"grant"`
 	Context map[string]interface{} `json:"context"`
 }
+
+// BatchPermissionCheckRequest represents a batch of permission checks
+type BatchPermissionCheckRequest struct {
+	Checks []PermissionCheckRequest `json:"checks" validate:"required,min=1,max=100,dive"`
+}
+
+// BatchPermissionCheckResult represents the result of a single check of a batch
+type BatchPermissionCheckResult struct {
+	Grant   bool                   `json:"grant"`
+	Context map[string]interface{} `json:"context"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// BatchPermissionCheckResponse represents the results of a batch of permission checks, in request order
+type BatchPermissionCheckResponse struct {
+	Results []BatchPermissionCheckResult `json:"results"`
+}
+
+// ToPermissionChecksDomain converts a BatchPermissionCheckRequest to domain.PermissionCheck
+func (r *BatchPermissionCheckRequest) ToPermissionChecksDomain() []domain.PermissionCheck {
+	checks := make([]domain.PermissionCheck, len(r.Checks))
+	for i, c := range r.Checks {
+		checks[i] = domain.PermissionCheck{
+			User:     c.User,
+			Action:   c.Action,
+			Resource: c.Resource,
+			Context:  c.Context,
+		}
+	}
+	return checks
+}
+
+// ToConsistencyDomain merges the consistency options of the checks of a batch, which is decided from
+// data meeting all of them
+func (r *BatchPermissionCheckRequest) ToConsistencyDomain() (domain.Consistency, error) {
+	var consistency domain.Consistency
+	for _, c := range r.Checks {
+		checkConsistency, err := c.ToConsistencyDomain()
+		if err != nil {
+			return domain.Consistency{}, err
+		}
+		consistency = consistency.Merge(checkConsistency)
+	}
+	return consistency, nil
+}
+
+// ToBatchPermissionCheckResponse converts a list of domain.PermissionDecision to BatchPermissionCheckResponse
+func ToBatchPermissionCheckResponse(decisions []domain.PermissionDecision) BatchPermissionCheckResponse {
+	results := make([]BatchPermissionCheckResult, len(decisions))
+	for i, d := range decisions {
+		results[i] = BatchPermissionCheckResult{
+			Grant:   d.Granted,
+			Context: d.Context,
+			Error:   d.Error,
+		}
+	}
+	return BatchPermissionCheckResponse{Results: results}
+}
+
+// LookupResourcesRequest represents the request payload for listing the resources a user may act on
+type LookupResourcesRequest struct {
+	User    string                 `json:"user" validate:"required" example:"alice"`
+	Action  string                 `json:"action" validate:"required" example:"edit"`
+	Context map[string]interface{} `json:"context,omitempty" swaggertype:"object"`
+	Limit   int                    `json:"limit,omitempty" validate:"omitempty,min=1,max=1000" example:"10"`
+	Offset  int                    `json:"offset,omitempty" validate:"omitempty,min=0" example:"0"`
+}
+
+// LookupResourcesResponse represents a page of the resources a user may act on
+type LookupResourcesResponse struct {
+	ResourceIDs []string `json:"resource_ids"`
+	Total       int      `json:"total" example:"10"`
+}
+
+// LookupUsersRequest represents the request payload for listing the users that may perform an action on a resource
+type LookupUsersRequest struct {
+	Action          string                 `json:"action" validate:"required" example:"delete"`
+	Resource        string                 `json:"resource" validate:"required" example:"invoices"`
+	Context         map[string]interface{} `json:"context,omitempty" swaggertype:"object"`
+	IncludeRoles    bool                   `json:"include_roles,omitempty" example:"true"`
+	IncludeUserSets bool                   `json:"include_user_sets,omitempty" example:"false"`
+}
+
+// AccessGrantResponse represents a subject granted access and the path that grants it
+type AccessGrantResponse struct {
+	SubjectType string   `json:"subject_type" example:"user"`
+	SubjectID   string   `json:"subject_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	SubjectName string   `json:"subject_name" example:"alice"`
+	Reason      string   `json:"reason" example:"allowed by permission"`
+	Path        []string `json:"path" example:"user:alice,role:accountant,permission:123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// LookupUsersResponse represents the subjects that may perform an action on a resource
+type LookupUsersResponse struct {
+	Subjects []AccessGrantResponse `json:"subjects"`
+	Total    int                   `json:"total" example:"10"`
+}
+
+// ToAccessGrantResponse converts a domain.AccessGrant to AccessGrantResponse
+func ToAccessGrantResponse(g domain.AccessGrant) AccessGrantResponse {
+	return AccessGrantResponse{
+		SubjectType: g.SubjectType,
+		SubjectID:   g.SubjectID,
+		SubjectName: g.SubjectName,
+		Reason:      g.Reason,
+		Path:        g.Path,
+	}
+}
+
+// PermissionExplainResponse represents the result of a permission check together with its evaluation trace
+type PermissionExplainResponse struct {
+	Grant   bool                   `json:"grant"`
+	Context map[string]interface{} `json:"context"`
+	Trace   *domain.DecisionTrace  `json:"trace"`
+}
+
+// GrantPermissionRequest represents the request payload for granting a permission to a role or user set
+// Either an action, optionally scoped to a resource, or a resource set, optionally restricted to an
+// action given by ID or name, must be given.
+type GrantPermissionRequest struct {
+	ActionID      string `json:"action_id,omitempty" validate:"required_without=ResourceSetID" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ResourceID    string `json:"resource_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ResourceSetID string `json:"resource_set_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// ActionName restricts a resource set grant to the actions of that name
+	ActionName string `json:"action_name,omitempty" validate:"excluded_without=ResourceSetID" example:"read"`
+	Effect     string `json:"effect,omitempty" validate:"omitempty,oneof=allow deny" example:"allow"`
+	Priority   int    `json:"priority,omitempty" example:"10"`
+	// Conditions restrict when the permission applies, see the README for the attributes available
+	Conditions interface{} `json:"conditions,omitempty" swaggertype:"object"`
+}
+
+// RevokePermissionRequest represents the request payload for revoking a permission from a role or user set
+type RevokePermissionRequest struct {
+	PermissionID string `json:"permission_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ListPermissionsResponse represents a list of permissions
+type ListPermissionsResponse struct {
+	Permissions []PermissionResponse `json:"permissions"`
+	Total       int                  `json:"total" example:"10"`
+}
+
+// PermissionResponse represents the response model for a permission
+type PermissionResponse struct {
+	ID            string      `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	RoleID        string      `json:"role_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserID        *string     `json:"user_id,omitempty"`
+	UserSetID     *string     `json:"user_set_id,omitempty"`
+	ResourceID    *string     `json:"resource_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ResourceSetID *string     `json:"resource_set_id,omitempty"`
+	ActionID      *string     `json:"action_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ActionName    *string     `json:"action_name,omitempty" example:"read"`
+	Effect        string      `json:"effect" example:"allow"`
+	Priority      int         `json:"priority" example:"10"`
+	Conditions    interface{} `json:"conditions,omitempty" swaggertype:"object"`
+	CreatedAt     time.Time   `json:"created_at" example:"2025-04-19T12:00:00Z"`
+	UpdatedAt     time.Time   `json:"updated_at" example:"2025-04-19T12:00:00Z"`
+}
+
+// ToPermissionResponse converts a domain.Permission to PermissionResponse
+func ToPermissionResponse(p *domain.Permission) PermissionResponse {
+	var conditions interface{}
+	// Only unmarshal if there are conditions
+	if len(p.Conditions) > 0 {
+		if err := json.Unmarshal(p.Conditions, &conditions); err != nil {
+			// Fall back to raw bytes if unmarshaling fails
+			conditions = p.Conditions
+		}
+	}
+
+	return PermissionResponse{
+		ID:            p.ID,
+		RoleID:        p.RoleID,
+		UserID:        p.UserID,
+		UserSetID:     p.UserSetID,
+		ResourceID:    p.ResourceID,
+		ResourceSetID: p.ResourceSetID,
+		ActionID:      p.ActionID,
+		ActionName:    p.ActionName,
+		Effect:        p.Effect,
+		Priority:      p.Priority,
+		Conditions:    conditions,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+// ToPermissionDomain converts a GrantPermissionRequest to domain.Permission.
+// The caller is responsible for setting the subject the permission is granted to.
+func (r *GrantPermissionRequest) ToPermissionDomain() *domain.Permission {
+	permission := &domain.Permission{
+		Effect:   r.Effect,
+		Priority: r.Priority,
+	}
+	if r.ActionID != "" {
+		permission.ActionID = &r.ActionID
+	}
+	if r.ResourceID != "" {
+		permission.ResourceID = &r.ResourceID
+	}
+	if r.ResourceSetID != "" {
+		permission.ResourceSetID = &r.ResourceSetID
+	}
+	if r.ActionName != "" {
+		permission.ActionName = &r.ActionName
+	}
+	if r.Conditions != nil {
+		permission.Conditions, _ = json.Marshal(r.Conditions)
+	}
+	return permission
+}
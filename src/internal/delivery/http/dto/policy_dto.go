@@ -0,0 +1,51 @@
+package dto
+
+import "github.com/arifsetyawan/validra/src/internal/domain"
+
+// PolicyChangeResponse represents a change made to an entity to match a policy document
+type PolicyChangeResponse struct {
+	EntityType string `json:"entity_type" example:"permission"`
+	Name       string `json:"name" example:"role:accountant allow invoices#read"`
+	Operation  string `json:"operation" example:"create"`
+}
+
+// PolicyChangeCountsResponse represents the number of changes made to entities of one type
+type PolicyChangeCountsResponse struct {
+	Create int `json:"create" example:"2"`
+	Update int `json:"update" example:"1"`
+	Delete int `json:"delete" example:"0"`
+}
+
+// PolicyPlanResponse represents the changes planned or made to match a policy document
+type PolicyPlanResponse struct {
+	Changes []PolicyChangeResponse                `json:"changes"`
+	Summary map[string]PolicyChangeCountsResponse `json:"summary"`
+	Total   int                                   `json:"total" example:"3"`
+}
+
+// ToPolicyPlanResponse converts a policy plan to PolicyPlanResponse
+func ToPolicyPlanResponse(plan *domain.PolicyPlan) PolicyPlanResponse {
+	responses := make([]PolicyChangeResponse, len(plan.Changes))
+	for i, change := range plan.Changes {
+		responses[i] = PolicyChangeResponse{
+			EntityType: change.EntityType,
+			Name:       change.Name,
+			Operation:  change.Operation,
+		}
+	}
+
+	summary := make(map[string]PolicyChangeCountsResponse, len(plan.Summary))
+	for entityType, counts := range plan.Summary {
+		summary[entityType] = PolicyChangeCountsResponse{
+			Create: counts.Create,
+			Update: counts.Update,
+			Delete: counts.Delete,
+		}
+	}
+
+	return PolicyPlanResponse{
+		Changes: responses,
+		Summary: summary,
+		Total:   len(responses),
+	}
+}
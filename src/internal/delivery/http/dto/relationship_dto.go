@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// RelationshipRequest represents a relationship tuple "object#relation@subject"
+type RelationshipRequest struct {
+	Object   string `json:"object" validate:"required" example:"document:readme"`
+	Relation string `json:"relation" validate:"required" example:"viewer"`
+	Subject  string `json:"subject" validate:"required" example:"team:engineering#member"`
+}
+
+// WriteRelationshipsRequest represents a batch of relationship tuples to write and delete atomically
+type WriteRelationshipsRequest struct {
+	Writes  []RelationshipRequest `json:"writes" validate:"dive"`
+	Deletes []RelationshipRequest `json:"deletes" validate:"dive"`
+}
+
+// CheckRelationshipRequest represents the request payload for checking a relationship
+type CheckRelationshipRequest struct {
+	Object   string `json:"object" validate:"required" example:"document:readme"`
+	Relation string `json:"relation" validate:"required" example:"viewer"`
+	Subject  string `json:"subject" validate:"required" example:"user:alice"`
+	Depth    int    `json:"depth,omitempty" validate:"omitempty,min=1" example:"25"`
+}
+
+// RelationshipResponse represents the response model for a relationship tuple
+type RelationshipResponse struct {
+	ID        string    `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Object    string    `json:"object" example:"document:readme"`
+	Relation  string    `json:"relation" example:"viewer"`
+	Subject   string    `json:"subject" example:"team:engineering#member"`
+	Tuple     string    `json:"tuple" example:"document:readme#viewer@team:engineering#member"`
+	CreatedAt time.Time `json:"created_at" example:"2025-04-19T12:00:00Z"`
+}
+
+// ListRelationshipsResponse represents a paginated list of relationship tuples
+type ListRelationshipsResponse struct {
+	Relationships []RelationshipResponse `json:"relationships"`
+	Total         int                    `json:"total" example:"10"`
+}
+
+// CheckRelationshipResponse represents the result of a relationship check
+type CheckRelationshipResponse struct {
+	Allowed bool     `json:"allowed" example:"true"`
+	Path    []string `json:"path" example:"document:readme#viewer@team:engineering#member,team:engineering#member@user:alice"`
+}
+
+// ToRelationTupleDomain converts a RelationshipRequest to domain.RelationTuple
+func (r *RelationshipRequest) ToRelationTupleDomain() (*domain.RelationTuple, error) {
+	return domain.ParseRelationTuple(r.Object + "#" + r.Relation + "@" + r.Subject)
+}
+
+// ToRelationTuplesDomain converts a list of RelationshipRequest to domain.RelationTuple
+func ToRelationTuplesDomain(requests []RelationshipRequest) ([]*domain.RelationTuple, error) {
+	tuples := make([]*domain.RelationTuple, len(requests))
+	for i := range requests {
+		tuple, err := requests[i].ToRelationTupleDomain()
+		if err != nil {
+			return nil, err
+		}
+		tuples[i] = tuple
+	}
+	return tuples, nil
+}
+
+// ToRelationshipResponse converts a domain.RelationTuple to RelationshipResponse
+func ToRelationshipResponse(t *domain.RelationTuple) RelationshipResponse {
+	return RelationshipResponse{
+		ID:        t.ID,
+		Object:    t.Object(),
+		Relation:  t.Relation,
+		Subject:   t.Subject(),
+		Tuple:     t.String(),
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// ToRelationshipPath converts a chain of domain.RelationTuple to their string form
+func ToRelationshipPath(tuples []*domain.RelationTuple) []string {
+	path := make([]string, len(tuples))
+	for i, t := range tuples {
+		path[i] = t.String()
+	}
+	return path
+}
@@ -12,6 +12,12 @@ type CreateResourceRequest struct {
 	Name        string      `json:"name" validate:"required" example:"Sample Resource"`
 	Description string      `json:"description" example:"This is a sample resource description"`
 	Attributes  interface{} `json:"attributes,omitempty" swaggertype:"object"`
+	// CombiningAlgorithm and DefaultEffect override the global policy for this resource
+	CombiningAlgorithm string `json:"combining_algorithm,omitempty" validate:"omitempty,oneof=deny-overrides permit-overrides first-applicable" example:"deny-overrides"`
+	DefaultEffect      string `json:"default_effect,omitempty" validate:"omitempty,oneof=allow deny" example:"deny"`
+	// ParentID nests the resource under another one, whose permissions it inherits unless BlockInheritance is set
+	ParentID         *string `json:"parent_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	BlockInheritance bool    `json:"block_inheritance,omitempty" example:"false"`
 }
 
 // UpdateResourceRequest represents the request payload for updating a resource
@@ -19,6 +25,17 @@ type UpdateResourceRequest struct {
 	Name        string      `json:"name" validate:"required" example:"Updated Resource"`
 	Description string      `json:"description" example:"This is an updated resource description"`
 	Attributes  interface{} `json:"attributes,omitempty" swaggertype:"object"`
+	// CombiningAlgorithm and DefaultEffect override the global policy for this resource
+	CombiningAlgorithm string `json:"combining_algorithm,omitempty" validate:"omitempty,oneof=deny-overrides permit-overrides first-applicable" example:"first-applicable"`
+	DefaultEffect      string `json:"default_effect,omitempty" validate:"omitempty,oneof=allow deny" example:"deny"`
+	// BlockInheritance stops permissions granted on the ancestors of the resource from applying to it
+	BlockInheritance bool `json:"block_inheritance,omitempty" example:"false"`
+}
+
+// MoveResourceRequest represents the request payload for moving a resource in the hierarchy
+type MoveResourceRequest struct {
+	// ParentID is the new parent of the resource; null makes it a root resource
+	ParentID *string `json:"parent_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
 }
 
 // ResourceResponse represents the response model for a resource
@@ -27,8 +44,13 @@ type ResourceResponse struct {
 	Name        string      `json:"name" example:"Sample Resource"`
 	Description string      `json:"description" example:"This is a sample resource description"`
 	Attributes  interface{} `json:"attributes,omitempty" swaggertype:"object"`
-	CreatedAt   time.Time   `json:"created_at" example:"2025-04-19T12:00:00Z"`
-	UpdatedAt   time.Time   `json:"updated_at" example:"2025-04-19T12:00:00Z"`
+	// CombiningAlgorithm and DefaultEffect are empty when the resource uses the global policy
+	CombiningAlgorithm string    `json:"combining_algorithm,omitempty" example:"deny-overrides"`
+	DefaultEffect      string    `json:"default_effect,omitempty" example:"deny"`
+	ParentID           *string   `json:"parent_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	BlockInheritance   bool      `json:"block_inheritance" example:"false"`
+	CreatedAt          time.Time `json:"created_at" example:"2025-04-19T12:00:00Z"`
+	UpdatedAt          time.Time `json:"updated_at" example:"2025-04-19T12:00:00Z"`
 }
 
 // ListResourcesResponse represents a paginated list of resources
@@ -49,12 +71,16 @@ func ToResourceResponse(r *domain.Resource) ResourceResponse {
 	}
 
 	return ResourceResponse{
-		ID:          r.ID,
-		Name:        r.Name,
-		Description: r.Description,
-		Attributes:  attributes,
-		CreatedAt:   r.CreatedAt,
-		UpdatedAt:   r.UpdatedAt,
+		ID:                 r.ID,
+		Name:               r.Name,
+		Description:        r.Description,
+		Attributes:         attributes,
+		CombiningAlgorithm: r.CombiningAlgorithm,
+		DefaultEffect:      r.DefaultEffect,
+		ParentID:           r.ParentID,
+		BlockInheritance:   r.BlockInheritance,
+		CreatedAt:          r.CreatedAt,
+		UpdatedAt:          r.UpdatedAt,
 	}
 }
 
@@ -73,9 +99,13 @@ func (r *CreateResourceRequest) ToResourceDomain() *domain.Resource {
 	}
 
 	return &domain.Resource{
-		Name:        r.Name,
-		Description: r.Description,
-		Attributes:  attributesBytes,
+		Name:               r.Name,
+		Description:        r.Description,
+		Attributes:         attributesBytes,
+		CombiningAlgorithm: r.CombiningAlgorithm,
+		DefaultEffect:      r.DefaultEffect,
+		ParentID:           r.ParentID,
+		BlockInheritance:   r.BlockInheritance,
 	}
 }
 
@@ -83,6 +113,9 @@ func (r *CreateResourceRequest) ToResourceDomain() *domain.Resource {
 func (r *UpdateResourceRequest) UpdateResourceDomain(resource *domain.Resource) {
 	resource.Name = r.Name
 	resource.Description = r.Description
+	resource.CombiningAlgorithm = r.CombiningAlgorithm
+	resource.DefaultEffect = r.DefaultEffect
+	resource.BlockInheritance = r.BlockInheritance
 
 	// Only update attributes if provided
 	if r.Attributes != nil {
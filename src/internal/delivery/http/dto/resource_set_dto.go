@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// CreateResourceSetRequest represents the request payload for creating a resource set
+type CreateResourceSetRequest struct {
+	Name        string      `json:"name" validate:"required" example:"internal-documents"`
+	Description string      `json:"description" example:"Every resource classified as internal"`
+	Conditions  interface{} `json:"conditions" validate:"required" swaggertype:"object"`
+}
+
+// UpdateResourceSetRequest represents the request payload for updating a resource set
+type UpdateResourceSetRequest struct {
+	Name        string      `json:"name" validate:"required" example:"internal-documents"`
+	Description string      `json:"description" example:"Every resource classified as internal"`
+	Conditions  interface{} `json:"conditions,omitempty" swaggertype:"object"`
+}
+
+// ResourceSetResponse represents the response model for a resource set
+type ResourceSetResponse struct {
+	ID          string      `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name        string      `json:"name" example:"internal-documents"`
+	Description string      `json:"description" example:"Every resource classified as internal"`
+	Conditions  interface{} `json:"conditions,omitempty" swaggertype:"object"`
+	CreatedAt   time.Time   `json:"created_at" example:"2025-04-19T12:00:00Z"`
+	UpdatedAt   time.Time   `json:"updated_at" example:"2025-04-19T12:00:00Z"`
+}
+
+// ListResourceSetsResponse represents a paginated list of resource sets
+type ListResourceSetsResponse struct {
+	ResourceSets []ResourceSetResponse `json:"resource_sets"`
+	Total        int                   `json:"total" example:"10"`
+}
+
+// ResourceSetMembershipResponse represents the result of evaluating a resource against a resource set
+type ResourceSetMembershipResponse struct {
+	ResourceSetID string `json:"resource_set_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ResourceID    string `json:"resource_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ActionID      string `json:"action_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Member        bool   `json:"member" example:"true"`
+}
+
+// ToResourceSetResponse converts a domain.ResourceSet to ResourceSetResponse
+func ToResourceSetResponse(rs *domain.ResourceSet) ResourceSetResponse {
+	var conditions interface{}
+	// Only unmarshal if there are conditions
+	if len(rs.Conditions) > 0 {
+		if err := json.Unmarshal(rs.Conditions, &conditions); err != nil {
+			// Fall back to raw bytes if unmarshaling fails
+			conditions = rs.Conditions
+		}
+	}
+
+	return ResourceSetResponse{
+		ID:          rs.ID,
+		Name:        rs.Name,
+		Description: rs.Description,
+		Conditions:  conditions,
+		CreatedAt:   rs.CreatedAt,
+		UpdatedAt:   rs.UpdatedAt,
+	}
+}
+
+// ToResourceSetDomain converts a CreateResourceSetRequest to domain.ResourceSet
+func (r *CreateResourceSetRequest) ToResourceSetDomain() *domain.ResourceSet {
+	var conditionsBytes []byte
+	if r.Conditions != nil {
+		conditionsBytes, _ = json.Marshal(r.Conditions)
+	}
+
+	return &domain.ResourceSet{
+		Name:        r.Name,
+		Description: r.Description,
+		Conditions:  conditionsBytes,
+	}
+}
+
+// UpdateResourceSetDomain updates a domain.ResourceSet with values from UpdateResourceSetRequest
+func (r *UpdateResourceSetRequest) UpdateResourceSetDomain(resourceSet *domain.ResourceSet) {
+	resourceSet.Name = r.Name
+	resourceSet.Description = r.Description
+
+	// Only update conditions if provided
+	if r.Conditions != nil {
+		conditionsBytes, err := json.Marshal(r.Conditions)
+		if err == nil {
+			resourceSet.Conditions = conditionsBytes
+		}
+	}
+}
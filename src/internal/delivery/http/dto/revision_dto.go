@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// RevisionChangeResponse represents the states of an entity before and after a change
+type RevisionChangeResponse struct {
+	EntityType string          `json:"entity_type" example:"role"`
+	EntityID   string          `json:"entity_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Operation  string          `json:"operation" example:"update"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+}
+
+// RevisionResponse represents the response model for a revision
+type RevisionResponse struct {
+	ID          int64     `json:"id" example:"42"`
+	Description string    `json:"description" example:"update role"`
+	ChangeCount int       `json:"change_count" example:"1"`
+	CreatedAt   time.Time `json:"created_at" example:"2025-04-19T12:00:00Z"`
+}
+
+// RevisionDetailResponse represents a revision together with its changes
+type RevisionDetailResponse struct {
+	RevisionResponse
+	Changes []RevisionChangeResponse `json:"changes"`
+}
+
+// ListRevisionsResponse represents a paginated list of revisions
+type ListRevisionsResponse struct {
+	Revisions []RevisionResponse `json:"revisions"`
+	Total     int                `json:"total" example:"10"`
+}
+
+// ToRevisionResponse converts a domain.Revision to RevisionResponse
+func ToRevisionResponse(r *domain.Revision) RevisionResponse {
+	return RevisionResponse{
+		ID:          r.ID,
+		Description: r.Description,
+		ChangeCount: len(r.Changes),
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+// ToRevisionDetailResponse converts a domain.Revision to RevisionDetailResponse
+func ToRevisionDetailResponse(r *domain.Revision) RevisionDetailResponse {
+	changes := make([]RevisionChangeResponse, len(r.Changes))
+	for i, change := range r.Changes {
+		changes[i] = RevisionChangeResponse{
+			EntityType: change.EntityType,
+			EntityID:   change.EntityID,
+			Operation:  change.Operation,
+			Before:     change.Before,
+			After:      change.After,
+		}
+	}
+
+	return RevisionDetailResponse{
+		RevisionResponse: ToRevisionResponse(r),
+		Changes:          changes,
+	}
+}
+
+// WatchEventResponse represents a revision streamed by the watch API, with its changes to the
+// watched entity types
+type WatchEventResponse struct {
+	Revision    int64                    `json:"revision" example:"42"`
+	Token       string                   `json:"token" example:"cjE6NDI"`
+	Description string                   `json:"description" example:"update role"`
+	CreatedAt   time.Time                `json:"created_at" example:"2025-04-19T12:00:00Z"`
+	Changes     []RevisionChangeResponse `json:"changes"`
+}
+
+// ToWatchEventResponse converts a domain.Revision to WatchEventResponse
+func ToWatchEventResponse(r *domain.Revision) WatchEventResponse {
+	detail := ToRevisionDetailResponse(r)
+	return WatchEventResponse{
+		Revision:    r.ID,
+		Token:       domain.RevisionToken(r.ID),
+		Description: r.Description,
+		CreatedAt:   r.CreatedAt,
+		Changes:     detail.Changes,
+	}
+}
@@ -33,6 +33,25 @@ type ListRolesResponse struct {
 	Total int            `json:"total" example:"10"`
 }
 
+// RoleParentRequest is the DTO for adding or removing a parent role
+type RoleParentRequest struct {
+	ParentID string `json:"parent_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// RoleParentResponse is the DTO for role inheritance responses
+type RoleParentResponse struct {
+	RoleID    string    `json:"role_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ParentID  string    `json:"parent_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CreatedAt time.Time `json:"created_at" example:"2025-04-19T12:00:00Z"`
+}
+
+// EffectiveRoleResponse is the DTO for a role flattened with everything it inherits
+type EffectiveRoleResponse struct {
+	Role           RoleResponse         `json:"role"`
+	InheritedRoles []RoleResponse       `json:"inherited_roles"`
+	Permissions    []PermissionResponse `json:"permissions"`
+}
+
 // ToRoleDomain converts a CreateRoleRequest to domain.Role
 func (r *CreateRoleRequest) ToRoleDomain() *domain.Role {
 	return &domain.Role{
@@ -57,3 +76,12 @@ func ToRoleResponse(role *domain.Role) RoleResponse {
 		UpdatedAt:   role.UpdatedAt,
 	}
 }
+
+// ToRoleParentResponse converts a domain.RoleParent to RoleParentResponse
+func ToRoleParentResponse(rp *domain.RoleParent) RoleParentResponse {
+	return RoleParentResponse{
+		RoleID:    rp.RoleID,
+		ParentID:  rp.ParentID,
+		CreatedAt: rp.CreatedAt,
+	}
+}
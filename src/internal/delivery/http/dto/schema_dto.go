@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// WriteSchemaRequest represents the request payload for writing a new schema version
+type WriteSchemaRequest struct {
+	Definition interface{} `json:"definition" validate:"required" swaggertype:"object"`
+}
+
+// SchemaResponse represents the response model for a schema version
+type SchemaResponse struct {
+	ID         string      `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Version    int         `json:"version" example:"3"`
+	Definition interface{} `json:"definition" swaggertype:"object"`
+	CreatedAt  time.Time   `json:"created_at" example:"2025-04-19T12:00:00Z"`
+}
+
+// ListSchemasResponse represents a paginated list of schema versions
+type ListSchemasResponse struct {
+	Schemas []SchemaResponse `json:"schemas"`
+	Total   int              `json:"total" example:"10"`
+}
+
+// DefinitionBytes returns the JSON serialized schema definition
+func (r *WriteSchemaRequest) DefinitionBytes() []byte {
+	definition, _ := json.Marshal(r.Definition)
+	return definition
+}
+
+// ToSchemaResponse converts a domain.RelationSchema to SchemaResponse
+func ToSchemaResponse(s *domain.RelationSchema) SchemaResponse {
+	var definition interface{}
+	if err := json.Unmarshal(s.Definition, &definition); err != nil {
+		// Fall back to raw bytes if unmarshaling fails
+		definition = s.Definition
+	}
+
+	return SchemaResponse{
+		ID:         s.ID,
+		Version:    s.Version,
+		Definition: definition,
+		CreatedAt:  s.CreatedAt,
+	}
+}
@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// SimulationChangeRequest represents a change proposed to a simulation.
+// The fields used depend on the type, see domain.SimulationChange.
+type SimulationChangeRequest struct {
+	Type string `json:"type" validate:"required,oneof=grant_permission revoke_permission assign_role revoke_role delete_role set_attributes" example:"revoke_role"`
+	// RoleID or UserSetID is the subject of grant_permission and revoke_permission
+	RoleID       string                  `json:"role_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserSetID    string                  `json:"user_set_id,omitempty"`
+	Permission   *GrantPermissionRequest `json:"permission,omitempty" validate:"required_if=Type grant_permission"`
+	PermissionID string                  `json:"permission_id,omitempty" validate:"required_if=Type revoke_permission"`
+	UserID       string                  `json:"user_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	EntityType   string                  `json:"entity_type,omitempty" validate:"required_if=Type set_attributes,omitempty,oneof=user resource action" example:"user"`
+	EntityID     string                  `json:"entity_id,omitempty" validate:"required_if=Type set_attributes"`
+	Attributes   interface{}             `json:"attributes,omitempty" swaggertype:"object"`
+}
+
+// SimulateRequest represents the request payload for simulating changes
+type SimulateRequest struct {
+	Changes []SimulationChangeRequest `json:"changes" validate:"required,min=1,max=100,dive"`
+	Checks  []PermissionCheckRequest  `json:"checks" validate:"required,min=1,max=100,dive"`
+}
+
+// SimulationResultResponse represents the decisions of a check before and after the proposed changes
+type SimulationResultResponse struct {
+	User     string                     `json:"user" example:"john_doe"`
+	Action   string                     `json:"action" example:"read"`
+	Resource string                     `json:"resource" example:"invoices"`
+	Before   BatchPermissionCheckResult `json:"before"`
+	After    BatchPermissionCheckResult `json:"after"`
+	Changed  bool                       `json:"changed" example:"true"`
+}
+
+// SimulateResponse represents the outcome of a simulation, in check order
+type SimulateResponse struct {
+	Results []SimulationResultResponse `json:"results"`
+	Changed int                        `json:"changed" example:"1"`
+}
+
+// ToSimulationDomain converts a SimulateRequest to domain changes and checks
+func (r *SimulateRequest) ToSimulationDomain() ([]domain.SimulationChange, []domain.PermissionCheck) {
+	changes := make([]domain.SimulationChange, len(r.Changes))
+	for i, c := range r.Changes {
+		change := domain.SimulationChange{
+			Type:         c.Type,
+			PermissionID: c.PermissionID,
+			RoleID:       c.RoleID,
+			UserSetID:    c.UserSetID,
+			UserID:       c.UserID,
+			EntityType:   c.EntityType,
+			EntityID:     c.EntityID,
+		}
+		if c.Permission != nil {
+			change.Permission = c.Permission.ToPermissionDomain()
+			change.Permission.RoleID = c.RoleID
+			if c.UserSetID != "" {
+				userSetID := c.UserSetID
+				change.Permission.UserSetID = &userSetID
+			}
+		}
+		if c.Attributes != nil {
+			change.Attributes, _ = json.Marshal(c.Attributes)
+		}
+		changes[i] = change
+	}
+
+	checks := (&BatchPermissionCheckRequest{Checks: r.Checks}).ToPermissionChecksDomain()
+	return changes, checks
+}
+
+// ToSimulateResponse converts simulation results to SimulateResponse
+func ToSimulateResponse(results []domain.SimulationResult) SimulateResponse {
+	response := SimulateResponse{Results: make([]SimulationResultResponse, len(results))}
+	for i, r := range results {
+		response.Results[i] = SimulationResultResponse{
+			User:     r.Check.User,
+			Action:   r.Check.Action,
+			Resource: r.Check.Resource,
+			Before:   BatchPermissionCheckResult{Grant: r.Before.Granted, Context: r.Before.Context, Error: r.Before.Error},
+			After:    BatchPermissionCheckResult{Grant: r.After.Granted, Context: r.After.Context, Error: r.After.Error},
+			Changed:  r.Changed,
+		}
+		if r.Changed {
+			response.Changed++
+		}
+	}
+	return response
+}
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// UserRoleRequest is the DTO for assigning a role to or revoking a role from a user
+type UserRoleRequest struct {
+	RoleID string `json:"role_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// UserRoleResponse is the DTO for role assignment responses
+type UserRoleResponse struct {
+	ID        string    `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserID    string    `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	RoleID    string    `json:"role_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CreatedAt time.Time `json:"created_at" example:"2025-04-19T12:00:00Z"`
+}
+
+// ToUserRoleResponse converts a domain.UserRole to UserRoleResponse
+func ToUserRoleResponse(ur *domain.UserRole) UserRoleResponse {
+	return UserRoleResponse{
+		ID:        ur.ID,
+		UserID:    ur.UserID,
+		RoleID:    ur.RoleID,
+		CreatedAt: ur.CreatedAt,
+	}
+}
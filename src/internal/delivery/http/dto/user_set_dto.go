@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// CreateUserSetRequest represents the request payload for creating a user set
+type CreateUserSetRequest struct {
+	Name        string      `json:"name" validate:"required" example:"finance-team"`
+	Description string      `json:"description" example:"Users working in the finance department"`
+	Conditions  interface{} `json:"conditions" validate:"required" swaggertype:"object"`
+}
+
+// UpdateUserSetRequest represents the request payload for updating a user set
+type UpdateUserSetRequest struct {
+	Name        string      `json:"name" validate:"required" example:"finance-team"`
+	Description string      `json:"description" example:"Users working in the finance department"`
+	Conditions  interface{} `json:"conditions,omitempty" swaggertype:"object"`
+}
+
+// UserSetResponse represents the response model for a user set
+type UserSetResponse struct {
+	ID          string      `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name        string      `json:"name" example:"finance-team"`
+	Description string      `json:"description" example:"Users working in the finance department"`
+	Conditions  interface{} `json:"conditions,omitempty" swaggertype:"object"`
+	CreatedAt   time.Time   `json:"created_at" example:"2025-04-19T12:00:00Z"`
+	UpdatedAt   time.Time   `json:"updated_at" example:"2025-04-19T12:00:00Z"`
+}
+
+// ListUserSetsResponse represents a paginated list of user sets
+type ListUserSetsResponse struct {
+	UserSets []UserSetResponse `json:"user_sets"`
+	Total    int               `json:"total" example:"10"`
+}
+
+// UserSetMembershipResponse represents the result of evaluating a user against a user set
+type UserSetMembershipResponse struct {
+	UserSetID string `json:"user_set_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserID    string `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Member    bool   `json:"member" example:"true"`
+}
+
+// ToUserSetResponse converts a domain.UserSet to UserSetResponse
+func ToUserSetResponse(us *domain.UserSet) UserSetResponse {
+	var conditions interface{}
+	// Only unmarshal if there are conditions
+	if len(us.Conditions) > 0 {
+		if err := json.Unmarshal(us.Conditions, &conditions); err != nil {
+			// Fall back to raw bytes if unmarshaling fails
+			conditions = us.Conditions
+		}
+	}
+
+	return UserSetResponse{
+		ID:          us.ID,
+		Name:        us.Name,
+		Description: us.Description,
+		Conditions:  conditions,
+		CreatedAt:   us.CreatedAt,
+		UpdatedAt:   us.UpdatedAt,
+	}
+}
+
+// ToUserSetDomain converts a CreateUserSetRequest to domain.UserSet
+func (r *CreateUserSetRequest) ToUserSetDomain() *domain.UserSet {
+	var conditionsBytes []byte
+	if r.Conditions != nil {
+		conditionsBytes, _ = json.Marshal(r.Conditions)
+	}
+
+	return &domain.UserSet{
+		Name:        r.Name,
+		Description: r.Description,
+		Conditions:  conditionsBytes,
+	}
+}
+
+// UpdateUserSetDomain updates a domain.UserSet with values from UpdateUserSetRequest
+func (r *UpdateUserSetRequest) UpdateUserSetDomain(userSet *domain.UserSet) {
+	userSet.Name = r.Name
+	userSet.Description = r.Description
+
+	// Only update conditions if provided
+	if r.Conditions != nil {
+		conditionsBytes, err := json.Marshal(r.Conditions)
+		if err == nil {
+			userSet.Conditions = conditionsBytes
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/arifsetyawan/validra/src/internal/delivery/http/dto"
+	"github.com/arifsetyawan/validra/src/internal/service"
+	"github.com/arifsetyawan/validra/src/pkg/tenant"
+	"github.com/labstack/echo/v4"
+)
+
+// maxBundleSize is the maximum size of a bundle accepted by the API
+const maxBundleSize = 64 << 20
+
+// BundleHandler handles HTTP requests for exporting and importing the model
+type BundleHandler struct {
+	bundleService *service.BundleService
+}
+
+// NewBundleHandler creates a new BundleHandler
+func NewBundleHandler(bundleService *service.BundleService) *BundleHandler {
+	return &BundleHandler{
+		bundleService: bundleService,
+	}
+}
+
+// Register registers the routes to the given echo instance
+func (h *BundleHandler) Register(e *echo.Echo) {
+	e.GET("/api/export", h.Export)
+	e.POST("/api/import", h.Import)
+}
+
+// Export streams the model of the tenant as a bundle
+// @Summary Export the model
+// @Description Stream every resource, action, role, user, role assignment, user set, resource set, permission and relationship of the tenant, and the relation schema in use, as a single versioned JSON bundle.
+// @Tags bundle
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Bundle"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/export [get]
+func (h *BundleHandler) Export(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	response := c.Response()
+	response.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	response.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "validra-"+tenant.FromContext(ctx)+".json"))
+	response.WriteHeader(http.StatusOK)
+
+	// The status is sent before the first entity, so a failure can only be reported by the
+	// bundle ending early, which fails to decode on import
+	return h.bundleService.Export(ctx, response)
+}
+
+// Import restores a bundle
+// @Summary Import a bundle
+// @Description Restore an exported bundle into the tenant, keeping entity IDs. The import is atomic. Entities that already exist are skipped, overwritten or fail the import depending on the conflict strategy.
+// @Tags bundle
+// @Accept json
+// @Produce json
+// @Param bundle body object true "Bundle"
+// @Param conflict query string false "Conflict strategy: skip, overwrite or fail (default)"
+// @Success 200 {object} dto.ImportBundleResponse "Import summary"
+// @Failure 400 {object} map[string]string "Invalid bundle or conflict strategy"
+// @Failure 409 {object} map[string]string "Entity already exists"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/import [post]
+func (h *BundleHandler) Import(c echo.Context) error {
+	body := io.LimitReader(c.Request().Body, maxBundleSize)
+
+	result, err := h.bundleService.Import(c.Request().Context(), body, c.QueryParam("conflict"))
+	if err != nil {
+		switch {
+		case strings.HasPrefix(err.Error(), "invalid bundle"), strings.HasPrefix(err.Error(), "invalid conflict strategy"):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case strings.HasPrefix(err.Error(), "import conflict"):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, dto.ToImportBundleResponse(result))
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/arifsetyawan/validra/src/internal/delivery/http/dto"
+	"github.com/arifsetyawan/validra/src/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// DecisionCacheHandler handles HTTP requests administering the permission decision cache
+type DecisionCacheHandler struct {
+	decisionCache *service.DecisionCache
+}
+
+// NewDecisionCacheHandler creates a new DecisionCacheHandler. The cache is nil when it is disabled.
+func NewDecisionCacheHandler(decisionCache *service.DecisionCache) *DecisionCacheHandler {
+	return &DecisionCacheHandler{
+		decisionCache: decisionCache,
+	}
+}
+
+// Register registers the routes to the given echo instance
+func (h *DecisionCacheHandler) Register(e *echo.Echo) {
+	cache := e.Group("/api/admin/decision-cache")
+	cache.GET("", h.GetStats)
+	cache.DELETE("", h.Clear)
+}
+
+// GetStats reports the use of the decision cache
+// @Summary Get decision cache statistics
+// @Description Get the number of cached permission decisions and the hits, misses, evictions, expirations and invalidations of the cache since the server started, across tenants
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.DecisionCacheStatsResponse "Decision cache statistics"
+// @Router /api/admin/decision-cache [get]
+func (h *DecisionCacheHandler) GetStats(c echo.Context) error {
+	return c.JSON(http.StatusOK, dto.ToDecisionCacheStatsResponse(h.decisionCache.Stats()))
+}
+
+// Clear drops every cached decision
+// @Summary Clear the decision cache
+// @Description Drop every cached permission decision, of every tenant. Statistics are kept.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Success 204 "Decision cache cleared"
+// @Router /api/admin/decision-cache [delete]
+func (h *DecisionCacheHandler) Clear(c echo.Context) error {
+	h.decisionCache.Clear()
+	return c.NoContent(http.StatusNoContent)
+}
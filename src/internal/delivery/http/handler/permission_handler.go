@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/arifsetyawan/validra/src/internal/delivery/http/dto"
 	"github.com/arifsetyawan/validra/src/internal/service"
@@ -24,14 +25,30 @@ func NewPermissionHandler(permissionService *service.PermissionService) *Permiss
 func (h *PermissionHandler) Register(e *echo.Echo) {
 	// Update the path to include the /api prefix like other endpoints
 	e.POST("/api/check-permission", h.CheckPermission)
+	e.POST("/api/check-permission/explain", h.ExplainPermission)
+	e.POST("/api/check-permissions/batch", h.CheckPermissions)
+
+	// Reverse lookups
+	e.POST("/api/lookup/resources", h.LookupResources)
+	e.POST("/api/lookup/users", h.LookupUsers)
 
 	// Keep the original path for backward compatibility
 	e.POST("/check-permission", h.CheckPermission)
+
+	// Role permission management
+	e.POST("/api/roles/:id/permissions", h.GrantRolePermission)
+	e.GET("/api/roles/:id/permissions", h.ListRolePermissions)
+	e.DELETE("/api/roles/:id/permissions", h.RevokeRolePermission)
+
+	// User set permission management
+	e.POST("/api/user-sets/:id/permissions", h.GrantUserSetPermission)
+	e.GET("/api/user-sets/:id/permissions", h.ListUserSetPermissions)
+	e.DELETE("/api/user-sets/:id/permissions", h.RevokeUserSetPermission)
 }
 
 // CheckPermission godoc
 // @Summary Check permission
-// @Description Checks if a user has permission to perform an action on a resource
+// @Description Checks if a user has permission to perform an action on a resource. Permission conditions are evaluated against the optional request context. Passing the revision token returned by a change as at_least_as_fresh guarantees the decision reflects that change; fully_consistent decides from the latest data.
 // @Tags permissions
 // @Accept json
 // @Produce json
@@ -56,8 +73,16 @@ func (h *PermissionHandler) CheckPermission(c echo.Context) error {
 		})
 	}
 
+	consistency, err := req.ToConsistencyDomain()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	ctx := service.WithConsistency(c.Request().Context(), consistency)
+
 	// Check permission
-	granted, context, err := h.permissionService.CheckPermission(c.Request().Context(), req.User, req.Action, req.Resource)
+	granted, context, err := h.permissionService.CheckPermission(ctx, req.User, req.Action, req.Resource, req.Context)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
@@ -70,3 +95,404 @@ func (h *PermissionHandler) CheckPermission(c echo.Context) error {
 		Context: context,
 	})
 }
+
+// CheckPermissions checks a batch of permissions
+// @Summary Check permissions in batch
+// @Description Checks up to 100 permissions at once and returns the decisions in request order. Lookups shared by the checks are made once and the checks are evaluated concurrently. A failing check reports its error without failing the batch. The batch is decided from data meeting the consistency options of every check.
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param request body dto.BatchPermissionCheckRequest true "Permission checks"
+// @Success 200 {object} dto.BatchPermissionCheckResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/check-permissions/batch [post]
+func (h *PermissionHandler) CheckPermissions(c echo.Context) error {
+	var req dto.BatchPermissionCheckRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	consistency, err := req.ToConsistencyDomain()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	ctx := service.WithConsistency(c.Request().Context(), consistency)
+
+	decisions, err := h.permissionService.CheckPermissions(ctx, req.ToPermissionChecksDomain())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, dto.ToBatchPermissionCheckResponse(decisions))
+}
+
+// LookupResources lists the resources a user may perform an action on
+// @Summary Lookup resources
+// @Description Get a page of the IDs of the resources on which the user may perform the action, honoring roles, user sets, resource sets, relationships, conditions and deny rules
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param request body dto.LookupResourcesRequest true "User and action"
+// @Success 200 {object} dto.LookupResourcesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string
+// @Router /api/lookup/resources [post]
+func (h *PermissionHandler) LookupResources(c echo.Context) error {
+	var req dto.LookupResourcesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	resourceIDs, err := h.permissionService.LookupResources(c.Request().Context(), req.User, req.Action, req.Context, req.Limit, req.Offset)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, dto.LookupResourcesResponse{
+		ResourceIDs: resourceIDs,
+		Total:       len(resourceIDs),
+	})
+}
+
+// LookupUsers lists the users that may perform an action on a resource
+// @Summary Lookup users
+// @Description Get every user that may perform the action on the resource, with the path that grants it (roles, user set, relationship tuples or default effect). Roles and user sets whose own permissions allow the action can be included.
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param request body dto.LookupUsersRequest true "Action and resource"
+// @Success 200 {object} dto.LookupUsersResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Resource or action not found"
+// @Failure 500 {object} map[string]string
+// @Router /api/lookup/users [post]
+func (h *PermissionHandler) LookupUsers(c echo.Context) error {
+	var req dto.LookupUsersRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	grants, err := h.permissionService.LookupUsers(c.Request().Context(), req.Resource, req.Action, req.Context, req.IncludeRoles, req.IncludeUserSets)
+	if err != nil {
+		switch err.Error() {
+		case "resource not found", "action not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// Convert domain models to response DTOs
+	subjects := make([]dto.AccessGrantResponse, len(grants))
+	for i, g := range grants {
+		subjects[i] = dto.ToAccessGrantResponse(g)
+	}
+
+	return c.JSON(http.StatusOK, dto.LookupUsersResponse{
+		Subjects: subjects,
+		Total:    len(subjects),
+	})
+}
+
+// ExplainPermission checks a permission and explains the decision
+// @Summary Explain a permission check
+// @Description Checks a permission like /api/check-permission and returns a trace of the roles, user sets, resource sets, permissions and relationships considered, the conditions that matched or failed with their actual and expected values, and the rule that decided the outcome
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param request body dto.PermissionCheckRequest true "Permission check request"
+// @Success 200 {object} dto.PermissionExplainResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/check-permission/explain [post]
+func (h *PermissionHandler) ExplainPermission(c echo.Context) error {
+	var req dto.PermissionCheckRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	granted, context, trace, err := h.permissionService.ExplainPermission(c.Request().Context(), req.User, req.Action, req.Resource, req.Context)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, dto.PermissionExplainResponse{
+		Grant:   granted,
+		Context: context,
+		Trace:   trace,
+	})
+}
+
+// GrantRolePermission grants a role the permission to perform an action on a resource
+// @Summary Grant a permission to a role
+// @Description Allow or deny a role to perform an action on a resource. The resource defaults to the resource the action belongs to. A resource set can be given instead of an action to cover every matching resource and action.
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Param permission body dto.GrantPermissionRequest true "Permission to grant"
+// @Success 201 {object} dto.PermissionResponse "Permission granted"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Role, action or resource not found"
+// @Failure 409 {object} map[string]string "Permission already granted"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/roles/{id}/permissions [post]
+func (h *PermissionHandler) GrantRolePermission(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing role ID"})
+	}
+
+	var req dto.GrantPermissionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	permission := req.ToPermissionDomain()
+	permission.RoleID = id
+	if err := h.permissionService.GrantRolePermission(c.Request().Context(), permission); err != nil {
+		return grantErrorResponse(c, err)
+	}
+
+	response := dto.ToPermissionResponse(permission)
+	return c.JSON(http.StatusCreated, response)
+}
+
+// ListRolePermissions retrieves the permissions granted directly to a role
+// @Summary List permissions of a role
+// @Description Get every permission granted directly to a role
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} dto.ListPermissionsResponse "Permissions of the role"
+// @Failure 404 {object} map[string]string "Role not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/roles/{id}/permissions [get]
+func (h *PermissionHandler) ListRolePermissions(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing role ID"})
+	}
+
+	permissions, err := h.permissionService.ListRolePermissions(c.Request().Context(), id)
+	if err != nil {
+		if err.Error() == "role not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Role not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// Convert domain models to response DTOs
+	permissionResponses := make([]dto.PermissionResponse, len(permissions))
+	for i, p := range permissions {
+		permissionResponses[i] = dto.ToPermissionResponse(p)
+	}
+
+	response := dto.ListPermissionsResponse{
+		Permissions: permissionResponses,
+		Total:       len(permissionResponses),
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// RevokeRolePermission removes a permission from a role
+// @Summary Revoke a permission from a role
+// @Description Remove a permission granted directly to a role
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Param permission body dto.RevokePermissionRequest true "Permission to revoke"
+// @Success 200 {object} dto.PermissionResponse "Permission revoked"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Permission not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/roles/{id}/permissions [delete]
+func (h *PermissionHandler) RevokeRolePermission(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing role ID"})
+	}
+
+	var req dto.RevokePermissionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	permission, err := h.permissionService.RevokeRolePermission(c.Request().Context(), id, req.PermissionID)
+	if err != nil {
+		if err.Error() == "permission not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Permission not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToPermissionResponse(permission)
+	return c.JSON(http.StatusOK, response)
+}
+
+// GrantUserSetPermission grants every member of a user set the permission to perform an action on a resource
+// @Summary Grant a permission to a user set
+// @Description Allow or deny the members of a user set to perform an action on a resource. The resource defaults to the resource the action belongs to. A resource set can be given instead of an action to cover every matching resource and action.
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param id path string true "User set ID"
+// @Param permission body dto.GrantPermissionRequest true "Permission to grant"
+// @Success 201 {object} dto.PermissionResponse "Permission granted"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "User set, action or resource not found"
+// @Failure 409 {object} map[string]string "Permission already granted"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/user-sets/{id}/permissions [post]
+func (h *PermissionHandler) GrantUserSetPermission(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing user set ID"})
+	}
+
+	var req dto.GrantPermissionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	permission := req.ToPermissionDomain()
+	permission.UserSetID = &id
+	if err := h.permissionService.GrantUserSetPermission(c.Request().Context(), permission); err != nil {
+		return grantErrorResponse(c, err)
+	}
+
+	response := dto.ToPermissionResponse(permission)
+	return c.JSON(http.StatusCreated, response)
+}
+
+// ListUserSetPermissions retrieves the permissions granted to a user set
+// @Summary List permissions of a user set
+// @Description Get every permission granted to a user set
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param id path string true "User set ID"
+// @Success 200 {object} dto.ListPermissionsResponse "Permissions of the user set"
+// @Failure 404 {object} map[string]string "User set not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/user-sets/{id}/permissions [get]
+func (h *PermissionHandler) ListUserSetPermissions(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing user set ID"})
+	}
+
+	permissions, err := h.permissionService.ListUserSetPermissions(c.Request().Context(), id)
+	if err != nil {
+		if err.Error() == "user set not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "User set not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// Convert domain models to response DTOs
+	permissionResponses := make([]dto.PermissionResponse, len(permissions))
+	for i, p := range permissions {
+		permissionResponses[i] = dto.ToPermissionResponse(p)
+	}
+
+	response := dto.ListPermissionsResponse{
+		Permissions: permissionResponses,
+		Total:       len(permissionResponses),
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// RevokeUserSetPermission removes a permission from a user set
+// @Summary Revoke a permission from a user set
+// @Description Remove a permission granted to a user set
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param id path string true "User set ID"
+// @Param permission body dto.RevokePermissionRequest true "Permission to revoke"
+// @Success 200 {object} dto.PermissionResponse "Permission revoked"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Permission not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/user-sets/{id}/permissions [delete]
+func (h *PermissionHandler) RevokeUserSetPermission(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing user set ID"})
+	}
+
+	var req dto.RevokePermissionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	permission, err := h.permissionService.RevokeUserSetPermission(c.Request().Context(), id, req.PermissionID)
+	if err != nil {
+		if err.Error() == "permission not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Permission not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToPermissionResponse(permission)
+	return c.JSON(http.StatusOK, response)
+}
+
+// grantErrorResponse maps errors returned while granting a permission to HTTP responses
+func grantErrorResponse(c echo.Context, err error) error {
+	switch err.Error() {
+	case "role not found", "user set not found", "action not found", "resource not found", "resource set not found":
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case "permission already granted":
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case "action does not belong to resource", "effect must be either allow or deny",
+		"resource set permissions cannot target a resource", "either an action ID or an action name can be given",
+		"an action name requires a resource set", "action ID is required":
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if strings.HasPrefix(err.Error(), "invalid conditions") {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/arifsetyawan/validra/src/internal/delivery/http/dto"
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// maxPolicySize is the maximum size of a policy document accepted by the API
+const maxPolicySize = 4 << 20
+
+// PolicyHandler handles HTTP requests for declarative policy documents
+type PolicyHandler struct {
+	policyService *service.PolicyService
+}
+
+// NewPolicyHandler creates a new PolicyHandler
+func NewPolicyHandler(policyService *service.PolicyService) *PolicyHandler {
+	return &PolicyHandler{
+		policyService: policyService,
+	}
+}
+
+// Register registers the routes to the given echo instance
+func (h *PolicyHandler) Register(e *echo.Echo) {
+	e.POST("/api/policy/plan", h.PlanPolicy)
+	e.POST("/api/policy/apply", h.ApplyPolicy)
+}
+
+// PlanPolicy computes the changes applying a policy document would make
+// @Summary Plan a policy document
+// @Description List the creates, updates and deletes applying a YAML or JSON policy document would make, without making them.
+// @Tags policy
+// @Accept json,application/yaml
+// @Produce json
+// @Param document body string true "Policy document"
+// @Param prune query bool false "Also delete resources, resource sets, user sets and roles the document does not declare"
+// @Success 200 {object} dto.PolicyPlanResponse "Planned changes"
+// @Failure 400 {object} map[string]string "Invalid policy"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/policy/plan [post]
+func (h *PolicyHandler) PlanPolicy(c echo.Context) error {
+	return h.handleDocument(c, h.policyService.PlanDocument)
+}
+
+// ApplyPolicy applies a policy document
+// @Summary Apply a policy document
+// @Description Reconcile the tenant with a YAML or JSON policy document atomically. Entities are matched by name; declared entities are created or updated, and the actions, role parents and permissions of declared entities the document does not list are deleted. With prune, undeclared resources, resource sets, user sets and roles are deleted as well.
+// @Tags policy
+// @Accept json,application/yaml
+// @Produce json
+// @Param document body string true "Policy document"
+// @Param prune query bool false "Also delete resources, resource sets, user sets and roles the document does not declare"
+// @Success 200 {object} dto.PolicyPlanResponse "Changes made"
+// @Failure 400 {object} map[string]string "Invalid policy"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/policy/apply [post]
+func (h *PolicyHandler) ApplyPolicy(c echo.Context) error {
+	return h.handleDocument(c, h.policyService.ApplyDocument)
+}
+
+// handleDocument reads the policy document of the request and passes it to the given service method
+func (h *PolicyHandler) handleDocument(c echo.Context, handle func(ctx context.Context, data []byte, prune bool) (*domain.PolicyPlan, error)) error {
+	prune := false
+	if value := c.QueryParam("prune"); value != "" {
+		var err error
+		prune, err = strconv.ParseBool(value)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid prune parameter"})
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request().Body, maxPolicySize))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	plan, err := handle(c.Request().Context(), data, prune)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid policy") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, dto.ToPolicyPlanResponse(plan))
+}
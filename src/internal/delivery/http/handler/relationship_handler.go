@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/arifsetyawan/validra/src/internal/delivery/http/dto"
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// RelationshipHandler handles HTTP requests for relationship tuples
+type RelationshipHandler struct {
+	relationshipService *service.RelationshipService
+}
+
+// NewRelationshipHandler creates a new RelationshipHandler
+func NewRelationshipHandler(relationshipService *service.RelationshipService) *RelationshipHandler {
+	return &RelationshipHandler{
+		relationshipService: relationshipService,
+	}
+}
+
+// Register registers the routes to the given echo instance
+func (h *RelationshipHandler) Register(e *echo.Echo) {
+	relationships := e.Group("/api/relationships")
+	relationships.POST("", h.CreateRelationship)
+	relationships.GET("", h.ListRelationships)
+	relationships.DELETE("", h.DeleteRelationship)
+	relationships.POST("/write", h.WriteRelationships)
+	relationships.POST("/check", h.CheckRelationship)
+}
+
+// CreateRelationship writes a single relationship tuple
+// @Summary Create a relationship
+// @Description Write a relationship tuple "object#relation@subject". The subject is either "type:id" or a userset "type:id#relation".
+// @Tags relationships
+// @Accept json
+// @Produce json
+// @Param relationship body dto.RelationshipRequest true "Relationship"
+// @Success 201 {object} dto.RelationshipResponse "Relationship created"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/relationships [post]
+func (h *RelationshipHandler) CreateRelationship(c echo.Context) error {
+	var req dto.RelationshipRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	tuple, err := req.ToRelationTupleDomain()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if err := h.relationshipService.CreateRelationship(c.Request().Context(), tuple); err != nil {
+		return relationshipErrorResponse(c, err)
+	}
+
+	response := dto.ToRelationshipResponse(tuple)
+	return c.JSON(http.StatusCreated, response)
+}
+
+// ListRelationships retrieves a paginated list of relationship tuples
+// @Summary List relationships
+// @Description Get a paginated list of relationship tuples, optionally filtered by object, relation and subject
+// @Tags relationships
+// @Accept json
+// @Produce json
+// @Param object query string false "Object (type:id)"
+// @Param object_type query string false "Object type"
+// @Param relation query string false "Relation"
+// @Param subject query string false "Subject (type:id or type:id#relation)"
+// @Param subject_type query string false "Subject type"
+// @Param limit query int false "Number of items to return (default: 10)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} dto.ListRelationshipsResponse "List of relationships"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/relationships [get]
+func (h *RelationshipHandler) ListRelationships(c echo.Context) error {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil || offset < 0 {
+		offset = 0 // Default offset
+	}
+
+	filter := domain.RelationTupleFilter{
+		ObjectType:  c.QueryParam("object_type"),
+		Relation:    c.QueryParam("relation"),
+		SubjectType: c.QueryParam("subject_type"),
+	}
+	if object := c.QueryParam("object"); object != "" {
+		filter.ObjectType, filter.ObjectID, err = domain.ParseObject(object)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+	if subject := c.QueryParam("subject"); subject != "" {
+		var subjectRelation string
+		filter.SubjectType, filter.SubjectID, subjectRelation, err = domain.ParseSubject(subject)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		filter.SubjectRelation = &subjectRelation
+	}
+
+	tuples, err := h.relationshipService.ListRelationships(c.Request().Context(), filter, limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// Convert domain models to response DTOs
+	relationshipResponses := make([]dto.RelationshipResponse, len(tuples))
+	for i, t := range tuples {
+		relationshipResponses[i] = dto.ToRelationshipResponse(t)
+	}
+
+	response := dto.ListRelationshipsResponse{
+		Relationships: relationshipResponses,
+		Total:         len(relationshipResponses),
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// DeleteRelationship deletes a single relationship tuple
+// @Summary Delete a relationship
+// @Description Delete a relationship tuple. Deleting a relationship that does not exist is a no-op.
+// @Tags relationships
+// @Accept json
+// @Produce json
+// @Param relationship body dto.RelationshipRequest true "Relationship"
+// @Success 204 "Relationship deleted"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/relationships [delete]
+func (h *RelationshipHandler) DeleteRelationship(c echo.Context) error {
+	var req dto.RelationshipRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	tuple, err := req.ToRelationTupleDomain()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if err := h.relationshipService.DeleteRelationship(c.Request().Context(), tuple); err != nil {
+		return relationshipErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// WriteRelationships atomically writes and deletes a batch of relationship tuples
+// @Summary Write relationships in batch
+// @Description Atomically write and delete a batch of relationship tuples. Either every change is applied or none is.
+// @Tags relationships
+// @Accept json
+// @Produce json
+// @Param batch body dto.WriteRelationshipsRequest true "Relationships to write and delete"
+// @Success 204 "Relationships written"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/relationships/write [post]
+func (h *RelationshipHandler) WriteRelationships(c echo.Context) error {
+	var req dto.WriteRelationshipsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	writes, err := dto.ToRelationTuplesDomain(req.Writes)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	deletes, err := dto.ToRelationTuplesDomain(req.Deletes)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if err := h.relationshipService.WriteRelationships(c.Request().Context(), writes, deletes); err != nil {
+		return relationshipErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// CheckRelationship checks whether a subject holds a relation on an object
+// @Summary Check a relationship
+// @Description Walk the relationship graph to decide whether the subject holds the relation on the object, following at most depth hops
+// @Tags relationships
+// @Accept json
+// @Produce json
+// @Param request body dto.CheckRelationshipRequest true "Relationship check"
+// @Success 200 {object} dto.CheckRelationshipResponse "Check result"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 422 {object} map[string]string "Maximum depth exceeded"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/relationships/check [post]
+func (h *RelationshipHandler) CheckRelationship(c echo.Context) error {
+	var req dto.CheckRelationshipRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	allowed, path, err := h.relationshipService.CheckRelationship(c.Request().Context(), req.Object, req.Relation, req.Subject, req.Depth)
+	if err != nil {
+		return relationshipErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.CheckRelationshipResponse{
+		Allowed: allowed,
+		Path:    dto.ToRelationshipPath(path),
+	})
+}
+
+// relationshipErrorResponse maps errors returned by the relationship service to HTTP responses
+func relationshipErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrCheckDepthExceeded):
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "invalid"),
+		strings.HasPrefix(err.Error(), "at least"),
+		strings.HasPrefix(err.Error(), "at most"),
+		err.Error() == "relation is required":
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/arifsetyawan/validra/src/internal/delivery/http/dto"
+	"github.com/arifsetyawan/validra/src/internal/domain"
 	"github.com/arifsetyawan/validra/src/internal/service"
 	"github.com/labstack/echo/v4"
 )
@@ -29,6 +30,9 @@ func (h *ResourceHandler) Register(e *echo.Echo) {
 	resources.GET("/:id", h.GetResource)
 	resources.PUT("/:id", h.UpdateResource)
 	resources.DELETE("/:id", h.DeleteResource)
+	resources.PUT("/:id/parent", h.MoveResource)
+	resources.GET("/:id/children", h.ListChildResources)
+	resources.GET("/:id/ancestors", h.ListResourceAncestors)
 }
 
 // CreateResource creates a new resource
@@ -54,6 +58,9 @@ func (h *ResourceHandler) CreateResource(c echo.Context) error {
 
 	resource := req.ToResourceDomain()
 	if err := h.resourceService.CreateResource(c.Request().Context(), resource); err != nil {
+		if err.Error() == "parent resource not found" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
@@ -202,3 +209,118 @@ func (h *ResourceHandler) DeleteResource(c echo.Context) error {
 	response := dto.ToResourceResponse(deletedResource)
 	return c.JSON(http.StatusOK, response)
 }
+
+// MoveResource moves a resource in the hierarchy
+// @Summary Move a resource
+// @Description Nest a resource under a new parent, or make it a root resource with a null parent_id. The resource then inherits the permissions granted on its new ancestors.
+// @Tags resources
+// @Accept json
+// @Produce json
+// @Param id path string true "Resource ID"
+// @Param request body dto.MoveResourceRequest true "New parent"
+// @Success 200 {object} dto.ResourceResponse "Resource moved"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Resource not found"
+// @Failure 409 {object} map[string]string "Move would create a cycle"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/resources/{id}/parent [put]
+func (h *ResourceHandler) MoveResource(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing resource ID"})
+	}
+
+	var req dto.MoveResourceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	resource, err := h.resourceService.MoveResource(c.Request().Context(), id, req.ParentID)
+	if err != nil {
+		switch err.Error() {
+		case "resource not found", "parent resource not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		case "resource cannot be moved under itself or its descendants":
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToResourceResponse(resource)
+	return c.JSON(http.StatusOK, response)
+}
+
+// ListChildResources retrieves the resources nested directly under a resource
+// @Summary List child resources
+// @Description Get a paginated list of the resources nested directly under a resource
+// @Tags resources
+// @Accept json
+// @Produce json
+// @Param id path string true "Resource ID"
+// @Param limit query int false "Number of items to return (default: 10)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} dto.ListResourcesResponse "List of child resources"
+// @Failure 404 {object} map[string]string "Resource not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/resources/{id}/children [get]
+func (h *ResourceHandler) ListChildResources(c echo.Context) error {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil || offset < 0 {
+		offset = 0 // Default offset
+	}
+
+	resources, err := h.resourceService.ListChildResources(c.Request().Context(), c.Param("id"), limit, offset)
+	if err != nil {
+		if err.Error() == "resource not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Resource not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, toListResourcesResponse(resources))
+}
+
+// ListResourceAncestors retrieves the ancestors of a resource
+// @Summary List resource ancestors
+// @Description Get the ancestors of a resource, from its parent up to the root
+// @Tags resources
+// @Accept json
+// @Produce json
+// @Param id path string true "Resource ID"
+// @Success 200 {object} dto.ListResourcesResponse "List of ancestors, nearest first"
+// @Failure 404 {object} map[string]string "Resource not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/resources/{id}/ancestors [get]
+func (h *ResourceHandler) ListResourceAncestors(c echo.Context) error {
+	resources, err := h.resourceService.ListResourceAncestors(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		if err.Error() == "resource not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Resource not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, toListResourcesResponse(resources))
+}
+
+// toListResourcesResponse converts domain models to a list response
+func toListResourcesResponse(resources []*domain.Resource) dto.ListResourcesResponse {
+	resourceResponses := make([]dto.ResourceResponse, len(resources))
+	for i, r := range resources {
+		resourceResponses[i] = dto.ToResourceResponse(r)
+	}
+
+	return dto.ListResourcesResponse{
+		Resources: resourceResponses,
+		Total:     len(resourceResponses),
+	}
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/arifsetyawan/validra/src/internal/delivery/http/dto"
+	"github.com/arifsetyawan/validra/src/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// ResourceSetHandler handles HTTP requests for resource sets
+type ResourceSetHandler struct {
+	resourceSetService *service.ResourceSetService
+}
+
+// NewResourceSetHandler creates a new ResourceSetHandler
+func NewResourceSetHandler(resourceSetService *service.ResourceSetService) *ResourceSetHandler {
+	return &ResourceSetHandler{
+		resourceSetService: resourceSetService,
+	}
+}
+
+// Register registers the routes to the given echo instance
+func (h *ResourceSetHandler) Register(e *echo.Echo) {
+	resourceSets := e.Group("/api/resource-sets")
+	resourceSets.POST("", h.CreateResourceSet)
+	resourceSets.GET("", h.ListResourceSets)
+	resourceSets.GET("/:id", h.GetResourceSet)
+	resourceSets.PUT("/:id", h.UpdateResourceSet)
+	resourceSets.DELETE("/:id", h.DeleteResourceSet)
+	resourceSets.GET("/:id/members/:resourceId", h.CheckMembership)
+}
+
+// CreateResourceSet creates a new resource set
+// @Summary Create a new resource set
+// @Description Create a new resource set whose members are the resources and actions matching its conditions
+// @Tags resource-sets
+// @Accept json
+// @Produce json
+// @Param resourceSet body dto.CreateResourceSetRequest true "Resource set information"
+// @Success 201 {object} dto.ResourceSetResponse "Resource set created"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/resource-sets [post]
+func (h *ResourceSetHandler) CreateResourceSet(c echo.Context) error {
+	var req dto.CreateResourceSetRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	resourceSet := req.ToResourceSetDomain()
+	if err := h.resourceSetService.CreateResourceSet(c.Request().Context(), resourceSet); err != nil {
+		if strings.HasPrefix(err.Error(), "invalid conditions") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToResourceSetResponse(resourceSet)
+	return c.JSON(http.StatusCreated, response)
+}
+
+// GetResourceSet retrieves a resource set by ID
+// @Summary Get a resource set by ID
+// @Description Retrieve a specific resource set by its unique identifier
+// @Tags resource-sets
+// @Accept json
+// @Produce json
+// @Param id path string true "Resource set ID"
+// @Success 200 {object} dto.ResourceSetResponse "Resource set found"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Resource set not found"
+// @Router /api/resource-sets/{id} [get]
+func (h *ResourceSetHandler) GetResourceSet(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing resource set ID"})
+	}
+
+	resourceSet, err := h.resourceSetService.GetResourceSetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Resource set not found"})
+	}
+
+	response := dto.ToResourceSetResponse(resourceSet)
+	return c.JSON(http.StatusOK, response)
+}
+
+// ListResourceSets retrieves a paginated list of resource sets
+// @Summary List resource sets
+// @Description Get a paginated list of all resource sets
+// @Tags resource-sets
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of items to return (default: 10)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} dto.ListResourceSetsResponse "List of resource sets"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/resource-sets [get]
+func (h *ResourceSetHandler) ListResourceSets(c echo.Context) error {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil || offset < 0 {
+		offset = 0 // Default offset
+	}
+
+	resourceSets, err := h.resourceSetService.ListResourceSets(c.Request().Context(), limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// Convert domain models to response DTOs
+	resourceSetResponses := make([]dto.ResourceSetResponse, len(resourceSets))
+	for i, rs := range resourceSets {
+		resourceSetResponses[i] = dto.ToResourceSetResponse(rs)
+	}
+
+	response := dto.ListResourceSetsResponse{
+		ResourceSets: resourceSetResponses,
+		Total:        len(resourceSetResponses), // In a real app, we'd get the total count from the service
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// UpdateResourceSet updates an existing resource set
+// @Summary Update a resource set
+// @Description Update an existing resource set by its ID
+// @Tags resource-sets
+// @Accept json
+// @Produce json
+// @Param id path string true "Resource set ID"
+// @Param resourceSet body dto.UpdateResourceSetRequest true "Updated resource set information"
+// @Success 200 {object} dto.ResourceSetResponse "Resource set updated"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Resource set not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/resource-sets/{id} [put]
+func (h *ResourceSetHandler) UpdateResourceSet(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing resource set ID"})
+	}
+
+	var req dto.UpdateResourceSetRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	// Get existing resource set
+	resourceSet, err := h.resourceSetService.GetResourceSetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Resource set not found"})
+	}
+
+	// Update the resource set with request data
+	req.UpdateResourceSetDomain(resourceSet)
+
+	if err := h.resourceSetService.UpdateResourceSet(c.Request().Context(), resourceSet); err != nil {
+		if strings.HasPrefix(err.Error(), "invalid conditions") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToResourceSetResponse(resourceSet)
+	return c.JSON(http.StatusOK, response)
+}
+
+// DeleteResourceSet deletes a resource set by ID
+// @Summary Delete a resource set
+// @Description Delete a resource set by its ID
+// @Tags resource-sets
+// @Accept json
+// @Produce json
+// @Param id path string true "Resource set ID"
+// @Success 200 {object} dto.ResourceSetResponse "Resource set soft deleted"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Resource set not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/resource-sets/{id} [delete]
+func (h *ResourceSetHandler) DeleteResourceSet(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing resource set ID"})
+	}
+
+	deletedResourceSet, err := h.resourceSetService.DeleteResourceSet(c.Request().Context(), id)
+	if err != nil {
+		if err.Error() == "resource set not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Resource set not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToResourceSetResponse(deletedResourceSet)
+	return c.JSON(http.StatusOK, response)
+}
+
+// CheckMembership evaluates whether a resource is a member of a resource set
+// @Summary Check resource set membership
+// @Description Evaluate the conditions of a resource set against the attributes of a resource and, optionally, one of its actions
+// @Tags resource-sets
+// @Accept json
+// @Produce json
+// @Param id path string true "Resource set ID"
+// @Param resourceId path string true "Resource ID"
+// @Param action_id query string false "Action ID"
+// @Success 200 {object} dto.ResourceSetMembershipResponse "Membership result"
+// @Failure 404 {object} map[string]string "Resource set, resource or action not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/resource-sets/{id}/members/{resourceId} [get]
+func (h *ResourceSetHandler) CheckMembership(c echo.Context) error {
+	id := c.Param("id")
+	resourceID := c.Param("resourceId")
+	if id == "" || resourceID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing resource set ID or resource ID"})
+	}
+	actionID := c.QueryParam("action_id")
+
+	member, err := h.resourceSetService.IsMember(c.Request().Context(), id, resourceID, actionID)
+	if err != nil {
+		switch err.Error() {
+		case "resource set not found", "resource not found", "action not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, dto.ResourceSetMembershipResponse{
+		ResourceSetID: id,
+		ResourceID:    resourceID,
+		ActionID:      actionID,
+		Member:        member,
+	})
+}
@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/delivery/http/dto"
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// watchKeepAliveInterval is how often a comment is sent to idle watch streams, so that proxies keep them open
+const watchKeepAliveInterval = 15 * time.Second
+
+// RevisionHandler handles HTTP requests for revisions of the model
+type RevisionHandler struct {
+	revisionService *service.RevisionService
+	bundleService   *service.BundleService
+}
+
+// NewRevisionHandler creates a new RevisionHandler
+func NewRevisionHandler(revisionService *service.RevisionService, bundleService *service.BundleService) *RevisionHandler {
+	return &RevisionHandler{
+		revisionService: revisionService,
+		bundleService:   bundleService,
+	}
+}
+
+// Register registers the routes to the given echo instance
+func (h *RevisionHandler) Register(e *echo.Echo) {
+	revisions := e.Group("/api/revisions")
+	revisions.GET("", h.ListRevisions)
+	revisions.GET("/:id", h.GetRevision)
+	revisions.GET("/:id/model", h.GetModel)
+	revisions.POST("/:id/rollback", h.Rollback)
+
+	e.GET("/api/watch", h.Watch)
+}
+
+// ListRevisions retrieves a paginated list of revisions
+// @Summary List revisions
+// @Description Get a paginated list of the revisions of the model, latest first. Every change to the authorization data produces a new revision.
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of items to return (default: 10)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} dto.ListRevisionsResponse "List of revisions"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/revisions [get]
+func (h *RevisionHandler) ListRevisions(c echo.Context) error {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil || offset < 0 {
+		offset = 0 // Default offset
+	}
+
+	revisions, err := h.revisionService.ListRevisions(c.Request().Context(), limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// Convert domain models to response DTOs
+	revisionResponses := make([]dto.RevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		revisionResponses[i] = dto.ToRevisionResponse(revision)
+	}
+
+	response := dto.ListRevisionsResponse{
+		Revisions: revisionResponses,
+		Total:     len(revisionResponses),
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetRevision retrieves a revision
+// @Summary Get a revision
+// @Description Get a revision with the state of every entity it changed before and after the change
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "Revision ID"
+// @Success 200 {object} dto.RevisionDetailResponse "Revision found"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Revision not found"
+// @Router /api/revisions/{id} [get]
+func (h *RevisionHandler) GetRevision(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid revision ID"})
+	}
+
+	revision, err := h.revisionService.GetRevision(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Revision not found"})
+	}
+
+	response := dto.ToRevisionDetailResponse(revision)
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetModel retrieves the model as of a revision
+// @Summary Get the model as of a revision
+// @Description Get the model of the tenant as it was right after a revision, in the bundle format used by exports
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "Revision ID"
+// @Success 200 {object} map[string]interface{} "Bundle"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Revision not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/revisions/{id}/model [get]
+func (h *RevisionHandler) GetModel(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid revision ID"})
+	}
+
+	model, err := h.bundleService.ExportAt(c.Request().Context(), id)
+	if err != nil {
+		if err.Error() == "revision not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Revision not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, model)
+}
+
+// Rollback restores the model as of a revision
+// @Summary Roll back to a revision
+// @Description Atomically restore the model of the tenant to its state right after a revision. The rollback is recorded as a new revision, which is returned; nothing is returned when nothing changed since.
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "Revision ID"
+// @Success 200 {object} dto.RevisionDetailResponse "Revision recording the rollback"
+// @Success 204 "Nothing changed since the revision"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Revision not found"
+// @Failure 409 {object} map[string]string "Entity cannot be restored"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/revisions/{id}/rollback [post]
+func (h *RevisionHandler) Rollback(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid revision ID"})
+	}
+
+	revision, err := h.bundleService.Rollback(c.Request().Context(), id)
+	if err != nil {
+		switch {
+		case err.Error() == "revision not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Revision not found"})
+		case strings.HasPrefix(err.Error(), "import conflict"):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if revision == nil {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	response := dto.ToRevisionDetailResponse(revision)
+	return c.JSON(http.StatusOK, response)
+}
+
+// Watch streams the changes made to the model as Server-Sent Events
+// @Summary Watch changes
+// @Description Stream every revision of the model committed after the given revision, or from now on, as Server-Sent Events in revision order. Each "revision" event carries the revision ID as its event ID and the changes to the watched entity types; revisions without any are skipped. Reconnecting clients resume with the Last-Event-ID header. Comments are sent periodically to keep the connection alive.
+// @Tags revisions
+// @Produce text/event-stream
+// @Param after query int false "Revision to stream the following revisions of (default: the latest)"
+// @Param types query string false "Comma-separated entity types to watch, such as user,role,resource,action,permission (default: all)"
+// @Param Last-Event-ID header string false "Revision to resume after, overriding after"
+// @Success 200 {object} dto.WatchEventResponse "Stream of revision events"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Revision not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/watch [get]
+func (h *RevisionHandler) Watch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var entityTypes []string
+	if types := c.QueryParam("types"); types != "" {
+		for _, entityType := range strings.Split(types, ",") {
+			entityType = strings.TrimSpace(entityType)
+			if !slices.Contains(domain.EntityTypes, entityType) {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "Unknown entity type " + strconv.Quote(entityType)})
+			}
+			entityTypes = append(entityTypes, entityType)
+		}
+	}
+
+	after := c.Request().Header.Get("Last-Event-ID")
+	if after == "" {
+		after = c.QueryParam("after")
+	}
+	var afterID int64
+	if after == "" {
+		latest, err := h.revisionService.LatestRevisionID(ctx)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		afterID = latest
+	} else {
+		id, err := strconv.ParseInt(after, 10, 64)
+		if err != nil || id < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid revision ID"})
+		}
+		if id > 0 {
+			if _, err := h.revisionService.GetRevision(ctx, id); err != nil {
+				return c.JSON(http.StatusNotFound, map[string]string{"error": "Revision not found"})
+			}
+		}
+		afterID = id
+	}
+
+	// The stream outlives the write timeout of the server
+	_ = http.NewResponseController(c.Response()).SetWriteDeadline(time.Time{})
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Events and keep-alive comments are written from different goroutines. Nothing is written once
+	// the stream is over, since the response is released when the handler returns.
+	var mu sync.Mutex
+	write := func(format string, args ...interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(res, format, args...); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	}
+
+	var keepAlive sync.WaitGroup
+	keepAlive.Add(1)
+	go func() {
+		defer keepAlive.Done()
+		ticker := time.NewTicker(watchKeepAliveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := write(": keep-alive\n\n"); err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err := h.revisionService.Watch(ctx, afterID, entityTypes, func(revision *domain.Revision) error {
+		data, err := json.Marshal(dto.ToWatchEventResponse(revision))
+		if err != nil {
+			return err
+		}
+		return write("id: %d\nevent: revision\ndata: %s\n\n", revision.ID, data)
+	})
+	if err != nil && ctx.Err() == nil {
+		// The status is sent already, so the error can only be reported as an event
+		data, _ := json.Marshal(map[string]string{"error": err.Error()})
+		_ = write("event: error\ndata: %s\n\n", data)
+	}
+
+	cancel()
+	keepAlive.Wait()
+	return nil
+}
@@ -29,6 +29,10 @@ func (h *RoleHandler) Register(e *echo.Echo) {
 	roles.GET("/:id", h.GetRole)
 	roles.PUT("/:id", h.UpdateRole)
 	roles.DELETE("/:id", h.DeleteRole)
+	roles.POST("/:id/parents", h.AddParentRole)
+	roles.DELETE("/:id/parents", h.RemoveParentRole)
+	roles.GET("/:id/parents", h.ListParentRoles)
+	roles.GET("/:id/effective", h.GetEffectiveRole)
 }
 
 // CreateRole creates a new role
@@ -202,3 +206,170 @@ func (h *RoleHandler) DeleteRole(c echo.Context) error {
 	response := dto.ToRoleResponse(deletedRole)
 	return c.JSON(http.StatusOK, response)
 }
+
+// AddParentRole makes a role inherit the grants of a parent role
+// @Summary Add a parent role
+// @Description Make a role inherit every grant of a parent role. Links that would create a cycle are rejected.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Param parent body dto.RoleParentRequest true "Parent role"
+// @Success 201 {object} dto.RoleParentResponse "Parent role added"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Role not found"
+// @Failure 409 {object} map[string]string "Parent role already assigned or cycle detected"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/roles/{id}/parents [post]
+func (h *RoleHandler) AddParentRole(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing role ID"})
+	}
+
+	var req dto.RoleParentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	roleParent, err := h.roleService.AddParentRole(c.Request().Context(), id, req.ParentID)
+	if err != nil {
+		switch err.Error() {
+		case "role not found", "parent role not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		case "parent role already assigned", "role hierarchy cycle detected":
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToRoleParentResponse(roleParent)
+	return c.JSON(http.StatusCreated, response)
+}
+
+// RemoveParentRole stops a role from inheriting the grants of a parent role
+// @Summary Remove a parent role
+// @Description Remove an inheritance link between a role and its parent
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Param parent body dto.RoleParentRequest true "Parent role"
+// @Success 200 {object} dto.RoleParentResponse "Parent role removed"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Parent role not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/roles/{id}/parents [delete]
+func (h *RoleHandler) RemoveParentRole(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing role ID"})
+	}
+
+	var req dto.RoleParentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	roleParent, err := h.roleService.RemoveParentRole(c.Request().Context(), id, req.ParentID)
+	if err != nil {
+		if err.Error() == "parent role not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Parent role not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToRoleParentResponse(roleParent)
+	return c.JSON(http.StatusOK, response)
+}
+
+// ListParentRoles retrieves the roles a role directly inherits from
+// @Summary List parent roles
+// @Description Get the roles a role directly inherits from
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} dto.ListRolesResponse "Parent roles"
+// @Failure 404 {object} map[string]string "Role not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/roles/{id}/parents [get]
+func (h *RoleHandler) ListParentRoles(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing role ID"})
+	}
+
+	parents, err := h.roleService.ListParentRoles(c.Request().Context(), id)
+	if err != nil {
+		if err.Error() == "role not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Role not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// Convert domain models to response DTOs
+	roleResponses := make([]dto.RoleResponse, len(parents))
+	for i, r := range parents {
+		roleResponses[i] = dto.ToRoleResponse(r)
+	}
+
+	response := dto.ListRolesResponse{
+		Roles: roleResponses,
+		Total: len(roleResponses),
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetEffectiveRole retrieves a role flattened with everything it inherits
+// @Summary Get the effective role
+// @Description Get a role together with every role it inherits from, directly or transitively, and the permissions granted through all of them
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} dto.EffectiveRoleResponse "Effective role"
+// @Failure 404 {object} map[string]string "Role not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/roles/{id}/effective [get]
+func (h *RoleHandler) GetEffectiveRole(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing role ID"})
+	}
+
+	role, inherited, permissions, err := h.roleService.GetEffectiveRole(c.Request().Context(), id)
+	if err != nil {
+		if err.Error() == "role not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Role not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// Convert domain models to response DTOs
+	inheritedResponses := make([]dto.RoleResponse, len(inherited))
+	for i, r := range inherited {
+		inheritedResponses[i] = dto.ToRoleResponse(r)
+	}
+	permissionResponses := make([]dto.PermissionResponse, len(permissions))
+	for i, p := range permissions {
+		permissionResponses[i] = dto.ToPermissionResponse(p)
+	}
+
+	response := dto.EffectiveRoleResponse{
+		Role:           dto.ToRoleResponse(role),
+		InheritedRoles: inheritedResponses,
+		Permissions:    permissionResponses,
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
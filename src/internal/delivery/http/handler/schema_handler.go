@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/arifsetyawan/validra/src/internal/delivery/http/dto"
+	"github.com/arifsetyawan/validra/src/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// SchemaHandler handles HTTP requests for relation schemas
+type SchemaHandler struct {
+	schemaService *service.SchemaService
+}
+
+// NewSchemaHandler creates a new SchemaHandler
+func NewSchemaHandler(schemaService *service.SchemaService) *SchemaHandler {
+	return &SchemaHandler{
+		schemaService: schemaService,
+	}
+}
+
+// Register registers the routes to the given echo instance
+func (h *SchemaHandler) Register(e *echo.Echo) {
+	schemas := e.Group("/api/schemas")
+	schemas.POST("", h.WriteSchema)
+	schemas.GET("", h.ListSchemas)
+	schemas.GET("/latest", h.GetLatestSchema)
+	schemas.GET("/:version", h.GetSchema)
+}
+
+// WriteSchema stores a new schema version
+// @Summary Write a schema
+// @Description Validate a schema declaring object types, their relations and rewrite rules, and store it as the next version. Checks always use the latest version.
+// @Tags schemas
+// @Accept json
+// @Produce json
+// @Param schema body dto.WriteSchemaRequest true "Schema definition"
+// @Success 201 {object} dto.SchemaResponse "Schema version created"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/schemas [post]
+func (h *SchemaHandler) WriteSchema(c echo.Context) error {
+	var req dto.WriteSchemaRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	relationSchema, err := h.schemaService.WriteSchema(c.Request().Context(), req.DefinitionBytes())
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid schema") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToSchemaResponse(relationSchema)
+	return c.JSON(http.StatusCreated, response)
+}
+
+// ListSchemas retrieves a paginated list of schema versions
+// @Summary List schema versions
+// @Description Get a paginated list of schema versions, latest first
+// @Tags schemas
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of items to return (default: 10)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} dto.ListSchemasResponse "List of schema versions"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/schemas [get]
+func (h *SchemaHandler) ListSchemas(c echo.Context) error {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil || offset < 0 {
+		offset = 0 // Default offset
+	}
+
+	schemas, err := h.schemaService.ListSchemas(c.Request().Context(), limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// Convert domain models to response DTOs
+	schemaResponses := make([]dto.SchemaResponse, len(schemas))
+	for i, s := range schemas {
+		schemaResponses[i] = dto.ToSchemaResponse(s)
+	}
+
+	response := dto.ListSchemasResponse{
+		Schemas: schemaResponses,
+		Total:   len(schemaResponses),
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetLatestSchema retrieves the schema version currently in use
+// @Summary Get the latest schema
+// @Description Get the schema version currently used by checks
+// @Tags schemas
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.SchemaResponse "Schema found"
+// @Failure 404 {object} map[string]string "No schema written"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/schemas/latest [get]
+func (h *SchemaHandler) GetLatestSchema(c echo.Context) error {
+	relationSchema, err := h.schemaService.GetLatestSchema(c.Request().Context())
+	if err != nil {
+		if err.Error() == "schema not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Schema not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToSchemaResponse(relationSchema)
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetSchema retrieves a schema version
+// @Summary Get a schema version
+// @Description Get a schema by version number
+// @Tags schemas
+// @Accept json
+// @Produce json
+// @Param version path int true "Schema version"
+// @Success 200 {object} dto.SchemaResponse "Schema found"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Schema not found"
+// @Router /api/schemas/{version} [get]
+func (h *SchemaHandler) GetSchema(c echo.Context) error {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid schema version"})
+	}
+
+	relationSchema, err := h.schemaService.GetSchema(c.Request().Context(), version)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Schema not found"})
+	}
+
+	response := dto.ToSchemaResponse(relationSchema)
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/arifsetyawan/validra/src/internal/delivery/http/dto"
+	"github.com/arifsetyawan/validra/src/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// SimulationHandler handles HTTP requests for what-if simulations
+type SimulationHandler struct {
+	simulationService *service.SimulationService
+}
+
+// NewSimulationHandler creates a new SimulationHandler
+func NewSimulationHandler(simulationService *service.SimulationService) *SimulationHandler {
+	return &SimulationHandler{
+		simulationService: simulationService,
+	}
+}
+
+// Register registers the routes to the given echo instance
+func (h *SimulationHandler) Register(e *echo.Echo) {
+	e.POST("/api/simulate", h.Simulate)
+}
+
+// Simulate decides checks before and after proposed changes
+// @Summary Simulate changes
+// @Description Decide a list of checks, make the proposed changes (granted or revoked permissions, role assignments, deleted roles, attribute edits) and decide the checks again, returning both decisions. Nothing is persisted.
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param request body dto.SimulateRequest true "Proposed changes and checks"
+// @Success 200 {object} dto.SimulateResponse "Decisions before and after the changes"
+// @Failure 400 {object} map[string]string "Invalid change"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/simulate [post]
+func (h *SimulationHandler) Simulate(c echo.Context) error {
+	var req dto.SimulateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	changes, checks := req.ToSimulationDomain()
+	results, err := h.simulationService.Simulate(c.Request().Context(), changes, checks)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid change") ||
+			strings.HasPrefix(err.Error(), "at least") ||
+			strings.HasPrefix(err.Error(), "at most") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, dto.ToSimulateResponse(results))
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/arifsetyawan/validra/src/internal/delivery/http/dto"
+	"github.com/arifsetyawan/validra/src/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// UserRoleHandler handles HTTP requests for user-to-role assignments
+type UserRoleHandler struct {
+	userRoleService *service.UserRoleService
+}
+
+// NewUserRoleHandler creates a new UserRoleHandler
+func NewUserRoleHandler(userRoleService *service.UserRoleService) *UserRoleHandler {
+	return &UserRoleHandler{
+		userRoleService: userRoleService,
+	}
+}
+
+// Register registers the routes to the given echo instance
+func (h *UserRoleHandler) Register(e *echo.Echo) {
+	e.POST("/api/users/:id/roles", h.AssignRole)
+	e.DELETE("/api/users/:id/roles", h.RevokeRole)
+	e.GET("/api/users/:id/roles", h.ListUserRoles)
+	e.GET("/api/roles/:id/users", h.ListRoleUsers)
+}
+
+// AssignRole assigns a role to a user
+// @Summary Assign a role to a user
+// @Description Assign an existing role to an existing user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param assignment body dto.UserRoleRequest true "Role to assign"
+// @Success 201 {object} dto.UserRoleResponse "Role assigned"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "User or role not found"
+// @Failure 409 {object} map[string]string "Role already assigned"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/users/{id}/roles [post]
+func (h *UserRoleHandler) AssignRole(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing user ID"})
+	}
+
+	var req dto.UserRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	userRole, err := h.userRoleService.AssignRole(c.Request().Context(), id, req.RoleID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found", "role not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		case "role already assigned":
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToUserRoleResponse(userRole)
+	return c.JSON(http.StatusCreated, response)
+}
+
+// RevokeRole removes a role from a user
+// @Summary Revoke a role from a user
+// @Description Remove a role assignment from a user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param assignment body dto.UserRoleRequest true "Role to revoke"
+// @Success 200 {object} dto.UserRoleResponse "Role revoked"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Role assignment not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/users/{id}/roles [delete]
+func (h *UserRoleHandler) RevokeRole(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing user ID"})
+	}
+
+	var req dto.UserRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	userRole, err := h.userRoleService.RevokeRole(c.Request().Context(), id, req.RoleID)
+	if err != nil {
+		if err.Error() == "role assignment not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Role assignment not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToUserRoleResponse(userRole)
+	return c.JSON(http.StatusOK, response)
+}
+
+// ListUserRoles retrieves the roles assigned to a user
+// @Summary List roles of a user
+// @Description Get every role assigned to a user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.ListRolesResponse "Roles assigned to the user"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/users/{id}/roles [get]
+func (h *UserRoleHandler) ListUserRoles(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing user ID"})
+	}
+
+	roles, err := h.userRoleService.ListUserRoles(c.Request().Context(), id)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// Convert domain models to response DTOs
+	roleResponses := make([]dto.RoleResponse, len(roles))
+	for i, r := range roles {
+		roleResponses[i] = dto.ToRoleResponse(r)
+	}
+
+	response := dto.ListRolesResponse{
+		Roles: roleResponses,
+		Total: len(roleResponses),
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ListRoleUsers retrieves the users a role is assigned to
+// @Summary List users of a role
+// @Description Get every user that has been assigned a role
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} dto.ListUsersResponse "Users assigned to the role"
+// @Failure 404 {object} map[string]string "Role not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/roles/{id}/users [get]
+func (h *UserRoleHandler) ListRoleUsers(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing role ID"})
+	}
+
+	users, err := h.userRoleService.ListRoleUsers(c.Request().Context(), id)
+	if err != nil {
+		if err.Error() == "role not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Role not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// Convert domain models to response DTOs
+	userResponses := make([]dto.UserResponse, len(users))
+	for i, u := range users {
+		userResponses[i] = dto.ToUserResponse(u)
+	}
+
+	response := dto.ListUsersResponse{
+		Users: userResponses,
+		Total: len(userResponses),
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
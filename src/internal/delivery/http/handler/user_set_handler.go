@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/arifsetyawan/validra/src/internal/delivery/http/dto"
+	"github.com/arifsetyawan/validra/src/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// UserSetHandler handles HTTP requests for user sets
+type UserSetHandler struct {
+	userSetService *service.UserSetService
+}
+
+// NewUserSetHandler creates a new UserSetHandler
+func NewUserSetHandler(userSetService *service.UserSetService) *UserSetHandler {
+	return &UserSetHandler{
+		userSetService: userSetService,
+	}
+}
+
+// Register registers the routes to the given echo instance
+func (h *UserSetHandler) Register(e *echo.Echo) {
+	userSets := e.Group("/api/user-sets")
+	userSets.POST("", h.CreateUserSet)
+	userSets.GET("", h.ListUserSets)
+	userSets.GET("/:id", h.GetUserSet)
+	userSets.PUT("/:id", h.UpdateUserSet)
+	userSets.DELETE("/:id", h.DeleteUserSet)
+	userSets.GET("/:id/members/:userId", h.CheckMembership)
+}
+
+// CreateUserSet creates a new user set
+// @Summary Create a new user set
+// @Description Create a new user set whose members are the users matching its conditions
+// @Tags user-sets
+// @Accept json
+// @Produce json
+// @Param userSet body dto.CreateUserSetRequest true "User set information"
+// @Success 201 {object} dto.UserSetResponse "User set created"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/user-sets [post]
+func (h *UserSetHandler) CreateUserSet(c echo.Context) error {
+	var req dto.CreateUserSetRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	userSet := req.ToUserSetDomain()
+	if err := h.userSetService.CreateUserSet(c.Request().Context(), userSet); err != nil {
+		if strings.HasPrefix(err.Error(), "invalid conditions") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToUserSetResponse(userSet)
+	return c.JSON(http.StatusCreated, response)
+}
+
+// GetUserSet retrieves a user set by ID
+// @Summary Get a user set by ID
+// @Description Retrieve a specific user set by its unique identifier
+// @Tags user-sets
+// @Accept json
+// @Produce json
+// @Param id path string true "User set ID"
+// @Success 200 {object} dto.UserSetResponse "User set found"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "User set not found"
+// @Router /api/user-sets/{id} [get]
+func (h *UserSetHandler) GetUserSet(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing user set ID"})
+	}
+
+	userSet, err := h.userSetService.GetUserSetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "User set not found"})
+	}
+
+	response := dto.ToUserSetResponse(userSet)
+	return c.JSON(http.StatusOK, response)
+}
+
+// ListUserSets retrieves a paginated list of user sets
+// @Summary List user sets
+// @Description Get a paginated list of all user sets
+// @Tags user-sets
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of items to return (default: 10)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} dto.ListUserSetsResponse "List of user sets"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/user-sets [get]
+func (h *UserSetHandler) ListUserSets(c echo.Context) error {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil || offset < 0 {
+		offset = 0 // Default offset
+	}
+
+	userSets, err := h.userSetService.ListUserSets(c.Request().Context(), limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// Convert domain models to response DTOs
+	userSetResponses := make([]dto.UserSetResponse, len(userSets))
+	for i, us := range userSets {
+		userSetResponses[i] = dto.ToUserSetResponse(us)
+	}
+
+	response := dto.ListUserSetsResponse{
+		UserSets: userSetResponses,
+		Total:    len(userSetResponses), // In a real app, we'd get the total count from the service
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// UpdateUserSet updates an existing user set
+// @Summary Update a user set
+// @Description Update an existing user set by its ID
+// @Tags user-sets
+// @Accept json
+// @Produce json
+// @Param id path string true "User set ID"
+// @Param userSet body dto.UpdateUserSetRequest true "Updated user set information"
+// @Success 200 {object} dto.UserSetResponse "User set updated"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "User set not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/user-sets/{id} [put]
+func (h *UserSetHandler) UpdateUserSet(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing user set ID"})
+	}
+
+	var req dto.UpdateUserSetRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	// Get existing user set
+	userSet, err := h.userSetService.GetUserSetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "User set not found"})
+	}
+
+	// Update the user set with request data
+	req.UpdateUserSetDomain(userSet)
+
+	if err := h.userSetService.UpdateUserSet(c.Request().Context(), userSet); err != nil {
+		if strings.HasPrefix(err.Error(), "invalid conditions") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToUserSetResponse(userSet)
+	return c.JSON(http.StatusOK, response)
+}
+
+// DeleteUserSet deletes a user set by ID
+// @Summary Delete a user set
+// @Description Delete a user set by its ID
+// @Tags user-sets
+// @Accept json
+// @Produce json
+// @Param id path string true "User set ID"
+// @Success 200 {object} dto.UserSetResponse "User set soft deleted"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "User set not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/user-sets/{id} [delete]
+func (h *UserSetHandler) DeleteUserSet(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing user set ID"})
+	}
+
+	deletedUserSet, err := h.userSetService.DeleteUserSet(c.Request().Context(), id)
+	if err != nil {
+		if err.Error() == "user set not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "User set not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := dto.ToUserSetResponse(deletedUserSet)
+	return c.JSON(http.StatusOK, response)
+}
+
+// CheckMembership evaluates whether a user is a member of a user set
+// @Summary Check user set membership
+// @Description Evaluate the conditions of a user set against the attributes of a user
+// @Tags user-sets
+// @Accept json
+// @Produce json
+// @Param id path string true "User set ID"
+// @Param userId path string true "User ID"
+// @Success 200 {object} dto.UserSetMembershipResponse "Membership result"
+// @Failure 404 {object} map[string]string "User set or user not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/user-sets/{id}/members/{userId} [get]
+func (h *UserSetHandler) CheckMembership(c echo.Context) error {
+	id := c.Param("id")
+	userID := c.Param("userId")
+	if id == "" || userID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing user set ID or user ID"})
+	}
+
+	member, err := h.userSetService.IsMember(c.Request().Context(), id, userID)
+	if err != nil {
+		switch err.Error() {
+		case "user set not found", "user not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, dto.UserSetMembershipResponse{
+		UserSetID: id,
+		UserID:    userID,
+		Member:    member,
+	})
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// RevisionTokenHeader is the response header carrying the revision token of a request that changed the model
+const RevisionTokenHeader = "X-Revision-Token"
+
+// SetupRevisionToken returns the token of the latest revision committed by a request in the
+// RevisionTokenHeader response header. Permission checks passing it as at_least_as_fresh are
+// decided from data reflecting the changes of the request. Requests changing nothing get no token.
+func SetupRevisionToken(e *echo.Echo) {
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, tracker := service.TrackRevisions(c.Request().Context())
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			// Headers must be set before the handler writes the status
+			c.Response().Before(func() {
+				if id := tracker.Latest(); id != 0 {
+					c.Response().Header().Set(RevisionTokenHeader, domain.RevisionToken(id))
+				}
+			})
+			return next(c)
+		}
+	})
+}
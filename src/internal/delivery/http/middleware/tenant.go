@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/arifsetyawan/validra/src/pkg/tenant"
+	"github.com/labstack/echo/v4"
+)
+
+// tenantPathPrefix is the path prefix naming the tenant of a request, as in /tenants/acme/api/resources
+const tenantPathPrefix = "/tenants/"
+
+// SetupTenant resolves the tenant of every API request and stores it in the request context,
+// where repositories pick it up to scope their queries. The tenant is taken from the
+// /tenants/:tenant path prefix, which is stripped before routing, then from the header,
+// then falls back to defaultTenant. When defaultTenant is empty, API requests must name a tenant.
+func SetupTenant(e *echo.Echo, header, defaultTenant string) {
+	e.Pre(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			id := ""
+			if rest, ok := strings.CutPrefix(req.URL.Path, tenantPathPrefix); ok {
+				id, rest, _ = strings.Cut(rest, "/")
+				req.URL.Path = "/" + rest
+				req.URL.RawPath = ""
+			}
+			if id == "" {
+				id = req.Header.Get(header)
+			}
+
+			if !strings.HasPrefix(req.URL.Path, "/api/") {
+				return next(c)
+			}
+			if id == "" {
+				id = defaultTenant
+			}
+			if id == "" {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "tenant is required"})
+			}
+			if err := tenant.Validate(id); err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+
+			c.SetRequest(req.WithContext(tenant.WithID(req.Context(), id)))
+			c.Response().Header().Set(header, id)
+			return next(c)
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package domain
+
+// Strategies applied when an entity of an imported bundle already exists
+const (
+	// ImportConflictSkip keeps the existing entity
+	ImportConflictSkip = "skip"
+	// ImportConflictOverwrite replaces the existing entity with the one of the bundle
+	ImportConflictOverwrite = "overwrite"
+	// ImportConflictFail aborts the import, leaving every entity untouched
+	ImportConflictFail = "fail"
+)
+
+// ImportCounts counts what happened to the entities of one section of an imported bundle
+type ImportCounts struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+}
+
+// ImportResult summarizes an import per bundle section
+type ImportResult struct {
+	Summary map[string]ImportCounts `json:"summary"`
+}
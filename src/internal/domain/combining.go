@@ -0,0 +1,56 @@
+package domain
+
+import "fmt"
+
+// Combining algorithms decide which of the permissions matching a check wins
+const (
+	// CombiningDenyOverrides denies when any matching permission denies
+	CombiningDenyOverrides = "deny-overrides"
+	// CombiningPermitOverrides allows when any matching permission allows
+	CombiningPermitOverrides = "permit-overrides"
+	// CombiningFirstApplicable applies the matching permission with the highest priority
+	CombiningFirstApplicable = "first-applicable"
+)
+
+// CombiningPolicy selects how matching permissions are combined and the effect applied when none matches
+type CombiningPolicy struct {
+	Algorithm     string `json:"algorithm"`
+	DefaultEffect string `json:"default_effect"`
+}
+
+// Validate checks that the algorithm and default effect are known
+func (p CombiningPolicy) Validate() error {
+	if err := ValidateCombiningAlgorithm(p.Algorithm); err != nil {
+		return err
+	}
+	return ValidateDefaultEffect(p.DefaultEffect)
+}
+
+// ForResource returns the policy with the overrides configured on the resource applied
+func (p CombiningPolicy) ForResource(resource *Resource) CombiningPolicy {
+	if resource.CombiningAlgorithm != "" {
+		p.Algorithm = resource.CombiningAlgorithm
+	}
+	if resource.DefaultEffect != "" {
+		p.DefaultEffect = resource.DefaultEffect
+	}
+	return p
+}
+
+// ValidateCombiningAlgorithm checks that algorithm is a known combining algorithm
+func ValidateCombiningAlgorithm(algorithm string) error {
+	switch algorithm {
+	case CombiningDenyOverrides, CombiningPermitOverrides, CombiningFirstApplicable:
+		return nil
+	}
+	return fmt.Errorf("combining algorithm must be one of %s, %s or %s",
+		CombiningDenyOverrides, CombiningPermitOverrides, CombiningFirstApplicable)
+}
+
+// ValidateDefaultEffect checks that effect is either allow or deny
+func ValidateDefaultEffect(effect string) error {
+	if effect != EffectAllow && effect != EffectDeny {
+		return fmt.Errorf("default effect must be either allow or deny")
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// DecisionCacheStats describes the use of the permission decision cache since the process started
+type DecisionCacheStats struct {
+	Enabled    bool          `json:"enabled"`
+	Entries    int           `json:"entries"`
+	MaxEntries int           `json:"max_entries"`
+	TTL        time.Duration `json:"ttl"`
+	Hits       uint64        `json:"hits"`
+	Misses     uint64        `json:"misses"`
+	// Evictions counts the decisions dropped to stay within MaxEntries
+	Evictions uint64 `json:"evictions"`
+	// Expirations counts the decisions dropped because they outlived the TTL
+	Expirations uint64 `json:"expirations"`
+	// Invalidations counts the decisions dropped because an entity they depend on changed
+	Invalidations uint64 `json:"invalidations"`
+}
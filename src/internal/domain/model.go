@@ -6,13 +6,17 @@ import (
 
 // Resource represents an entity that can be accessed
 type Resource struct {
-	ID          string     `json:"id"`
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	Attributes  []byte     `json:"attributes"` // JSON serialized attributes
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+	ID                 string     `json:"id"`
+	Name               string     `json:"name"`
+	Description        string     `json:"description"`
+	Attributes         []byte     `json:"attributes"`                    // JSON serialized attributes
+	CombiningAlgorithm string     `json:"combining_algorithm,omitempty"` // Overrides the global combining algorithm
+	DefaultEffect      string     `json:"default_effect,omitempty"`      // Overrides the global default effect
+	ParentID           *string    `json:"parent_id,omitempty"`           // Resource this resource is nested under
+	BlockInheritance   bool       `json:"block_inheritance"`             // Stops permissions granted on ancestors from applying
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	DeletedAt          *time.Time `json:"deletedAt,omitempty"`
 }
 
 // Action represents an operation that can be performed on a resource
@@ -37,6 +41,13 @@ type Role struct {
 	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
 }
 
+// RoleParent links a role to a parent role whose grants it inherits
+type RoleParent struct {
+	RoleID    string    `json:"role_id"`
+	ParentID  string    `json:"parent_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // User represents a user in the system
 type User struct {
 	ID         string     `json:"id"`
@@ -47,6 +58,14 @@ type User struct {
 	DeletedAt  *time.Time `json:"deletedAt,omitempty"`
 }
 
+// UserRole represents the assignment of a role to a user
+type UserRole struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	RoleID    string    `json:"role_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // UserSet represents a group of users defined by conditions
 type UserSet struct {
 	ID          string     `json:"id"`
@@ -77,9 +96,82 @@ type Permission struct {
 	UserSetID     *string    `json:"user_set_id,omitempty"`
 	ResourceID    *string    `json:"resource_id,omitempty"`
 	ResourceSetID *string    `json:"resource_set_id,omitempty"`
-	Effect        string     `json:"effect"`               // "allow" or "deny"
-	Conditions    []byte     `json:"conditions,omitempty"` // Additional conditions
+	ActionID      *string    `json:"action_id,omitempty"`
+	ActionName    *string    `json:"action_name,omitempty"` // Restricts a resource set grant to the actions of that name
+	Effect        string     `json:"effect"`                // "allow" or "deny"
+	Priority      int        `json:"priority"`              // Higher priorities are evaluated first
+	Conditions    []byte     `json:"conditions,omitempty"`  // Additional conditions
 	CreatedAt     time.Time  `json:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 	DeletedAt     *time.Time `json:"deletedAt,omitempty"`
 }
+
+// Permission effects
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// PermissionSubjects identifies the principals whose grants apply to a permission check
+type PermissionSubjects struct {
+	UserID     string
+	RoleIDs    []string
+	UserSetIDs []string
+}
+
+// PermissionTarget identifies the resources and actions a permission check is made against: the resource
+// and the ancestors it inherits from, the actions of that name on them and the resource sets they belong to
+type PermissionTarget struct {
+	ResourceIDs    []string
+	ActionIDs      []string
+	ActionName     string
+	ResourceSetIDs []string
+}
+
+// RelationTuple is a relationship "object#relation@subject" between two entities.
+// The subject is either a concrete entity such as "user:alice" or, when SubjectRelation is set,
+// every subject holding that relation on another object such as "team:eng#member".
+type RelationTuple struct {
+	ID              string    `json:"id"`
+	ObjectType      string    `json:"object_type"`
+	ObjectID        string    `json:"object_id"`
+	Relation        string    `json:"relation"`
+	SubjectType     string    `json:"subject_type"`
+	SubjectID       string    `json:"subject_id"`
+	SubjectRelation string    `json:"subject_relation,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// RelationTupleFilter selects relation tuples; empty fields match any value
+type RelationTupleFilter struct {
+	ObjectType      string
+	ObjectID        string
+	Relation        string
+	SubjectType     string
+	SubjectID       string
+	SubjectRelation *string
+}
+
+// RelationSchema is a version of the schema declaring object types, their relations and
+// how relations are derived from one another
+type RelationSchema struct {
+	ID         string    `json:"id"`
+	Version    int       `json:"version"`
+	Definition []byte    `json:"definition"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// PermissionCheck asks whether a user may perform an action on a resource
+type PermissionCheck struct {
+	User     string                 `json:"user"`
+	Action   string                 `json:"action"`
+	Resource string                 `json:"resource"`
+	Context  map[string]interface{} `json:"context,omitempty"` // Request-time attributes for permission conditions
+}
+
+// PermissionDecision is the outcome of a PermissionCheck
+type PermissionDecision struct {
+	Granted bool                   `json:"granted"`
+	Context map[string]interface{} `json:"context"`
+	Error   string                 `json:"error,omitempty"`
+}
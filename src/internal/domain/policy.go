@@ -0,0 +1,58 @@
+package domain
+
+// Entity types a policy document manages
+const (
+	PolicyEntityResource    = EntityResource
+	PolicyEntityAction      = EntityAction
+	PolicyEntityResourceSet = EntityResourceSet
+	PolicyEntityUserSet     = EntityUserSet
+	PolicyEntityRole        = EntityRole
+	PolicyEntityRoleParent  = EntityRoleParent
+	PolicyEntityPermission  = EntityPermission
+)
+
+// Operations applied to the entities of a policy document
+const (
+	PolicyOperationCreate = OperationCreate
+	PolicyOperationUpdate = OperationUpdate
+	PolicyOperationDelete = OperationDelete
+)
+
+// PolicyChange describes a change made to an entity to match a policy document
+type PolicyChange struct {
+	EntityType string `json:"entity_type"`
+	Name       string `json:"name"`
+	Operation  string `json:"operation"`
+}
+
+// PolicyChangeCounts counts the changes made to entities of one type
+type PolicyChangeCounts struct {
+	Create int `json:"create"`
+	Update int `json:"update"`
+	Delete int `json:"delete"`
+}
+
+// PolicyPlan lists the changes needed for a tenant to match a policy document, in the order they are
+// made, and summarizes them per entity type
+type PolicyPlan struct {
+	Changes []PolicyChange                `json:"changes"`
+	Summary map[string]PolicyChangeCounts `json:"summary"`
+}
+
+// NewPolicyPlan creates a plan from a list of changes
+func NewPolicyPlan(changes []PolicyChange) *PolicyPlan {
+	summary := make(map[string]PolicyChangeCounts)
+	for _, c := range changes {
+		counts := summary[c.EntityType]
+		switch c.Operation {
+		case PolicyOperationCreate:
+			counts.Create++
+		case PolicyOperationUpdate:
+			counts.Update++
+		case PolicyOperationDelete:
+			counts.Delete++
+		}
+		summary[c.EntityType] = counts
+	}
+	return &PolicyPlan{Changes: changes, Summary: summary}
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Well-known types that tie relationship tuples to the entities of the model.
+// A tuple "resource:<resource id>#<action name>@user:<user id>" allows the user to perform the action on the resource.
+const (
+	SubjectTypeUser    = "user"
+	ObjectTypeResource = "resource"
+)
+
+// ParseObject parses an object reference of the form "type:id"
+func ParseObject(s string) (objectType, objectID string, err error) {
+	objectType, objectID, ok := strings.Cut(s, ":")
+	if !ok || objectType == "" || objectID == "" || strings.ContainsAny(objectType, "#@") || strings.ContainsAny(objectID, "#@") {
+		return "", "", fmt.Errorf("invalid object %q: expected type:id", s)
+	}
+	return objectType, objectID, nil
+}
+
+// ParseSubject parses a subject reference of the form "type:id" or "type:id#relation"
+func ParseSubject(s string) (subjectType, subjectID, subjectRelation string, err error) {
+	object, relation, hasRelation := strings.Cut(s, "#")
+	if hasRelation && (relation == "" || strings.ContainsAny(relation, "#@:")) {
+		return "", "", "", fmt.Errorf("invalid subject %q: expected type:id or type:id#relation", s)
+	}
+	subjectType, subjectID, err = ParseObject(object)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid subject %q: expected type:id or type:id#relation", s)
+	}
+	return subjectType, subjectID, relation, nil
+}
+
+// ParseRelationTuple parses a tuple of the form "type:id#relation@subject"
+func ParseRelationTuple(s string) (*RelationTuple, error) {
+	objectAndRelation, subject, ok := strings.Cut(s, "@")
+	if !ok {
+		return nil, fmt.Errorf("invalid tuple %q: expected object#relation@subject", s)
+	}
+	object, relation, ok := strings.Cut(objectAndRelation, "#")
+	if !ok || relation == "" || strings.ContainsAny(relation, "#@:") {
+		return nil, fmt.Errorf("invalid tuple %q: expected object#relation@subject", s)
+	}
+
+	tuple := &RelationTuple{Relation: relation}
+	var err error
+	if tuple.ObjectType, tuple.ObjectID, err = ParseObject(object); err != nil {
+		return nil, err
+	}
+	if tuple.SubjectType, tuple.SubjectID, tuple.SubjectRelation, err = ParseSubject(subject); err != nil {
+		return nil, err
+	}
+	return tuple, nil
+}
+
+// Validate checks that every part of the tuple is well formed
+func (t *RelationTuple) Validate() error {
+	_, err := ParseRelationTuple(t.String())
+	return err
+}
+
+// Object returns the "type:id" reference of the tuple object
+func (t *RelationTuple) Object() string {
+	return t.ObjectType + ":" + t.ObjectID
+}
+
+// Subject returns the "type:id" or "type:id#relation" reference of the tuple subject
+func (t *RelationTuple) Subject() string {
+	if t.SubjectRelation != "" {
+		return t.SubjectType + ":" + t.SubjectID + "#" + t.SubjectRelation
+	}
+	return t.SubjectType + ":" + t.SubjectID
+}
+
+// String returns the tuple in its "object#relation@subject" form
+func (t *RelationTuple) String() string {
+	return t.Object() + "#" + t.Relation + "@" + t.Subject()
+}
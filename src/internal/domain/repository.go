@@ -1,12 +1,33 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// Errors wrapped by the errors repositories return
+var (
+	// ErrNotFound is wrapped when the entity looked up does not exist
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicateKey is wrapped when an entity cannot be written because its key is taken
+	ErrDuplicateKey = errors.New("duplicate key value violates unique constraint")
+)
+
+// Transactor runs work atomically across repositories
+type Transactor interface {
+	// InTransaction runs fn in a transaction joined by the repositories called with the context fn receives.
+	// The transaction is committed when fn returns nil and rolled back otherwise.
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
 
 // ResourceRepository defines the methods for Resource data access
 type ResourceRepository interface {
 	Create(ctx context.Context, resource *Resource) error
 	GetByID(ctx context.Context, id string) (*Resource, error)
+	GetByName(ctx context.Context, name string) (*Resource, error)
 	List(ctx context.Context, limit, offset int) ([]*Resource, error)
+	ListChildren(ctx context.Context, parentID string, limit, offset int) ([]*Resource, error)
+	ListByDefaultEffect(ctx context.Context, effect string, limit, offset int) ([]*Resource, error)
 	Update(ctx context.Context, resource *Resource) error
 	Delete(ctx context.Context, id string) (*Resource, error)
 }
@@ -28,6 +49,13 @@ type RoleRepository interface {
 	List(ctx context.Context, limit, offset int) ([]*Role, error)
 	Update(ctx context.Context, role *Role) error
 	Delete(ctx context.Context, id string) (*Role, error)
+	AddParent(ctx context.Context, roleParent *RoleParent) error
+	RemoveParent(ctx context.Context, roleID, parentID string) (*RoleParent, error)
+	ListParents(ctx context.Context, roleID string) ([]*Role, error)
+	ListChildren(ctx context.Context, roleID string) ([]*Role, error)
+	// LockHierarchy keeps other transactions from changing the role hierarchy of the tenant until the
+	// transaction of ctx ends
+	LockHierarchy(ctx context.Context) error
 }
 
 // UserRepository defines the methods for User data access
@@ -40,10 +68,21 @@ type UserRepository interface {
 	Delete(ctx context.Context, id string) (*User, error)
 }
 
+// UserRoleRepository defines the methods for user-to-role assignment data access
+type UserRoleRepository interface {
+	Create(ctx context.Context, userRole *UserRole) error
+	Get(ctx context.Context, userID, roleID string) (*UserRole, error)
+	Delete(ctx context.Context, userID, roleID string) (*UserRole, error)
+	ListRolesByUserID(ctx context.Context, userID string) ([]*Role, error)
+	ListUsersByRoleID(ctx context.Context, roleID string) ([]*User, error)
+	List(ctx context.Context, limit, offset int) ([]*UserRole, error)
+}
+
 // UserSetRepository defines the methods for UserSet data access
 type UserSetRepository interface {
 	Create(ctx context.Context, userSet *UserSet) error
 	GetByID(ctx context.Context, id string) (*UserSet, error)
+	GetByIDIncludingDeleted(ctx context.Context, id string) (*UserSet, error)
 	List(ctx context.Context, limit, offset int) ([]*UserSet, error)
 	Update(ctx context.Context, userSet *UserSet) error
 	Delete(ctx context.Context, id string) (*UserSet, error)
@@ -53,6 +92,7 @@ type UserSetRepository interface {
 type ResourceSetRepository interface {
 	Create(ctx context.Context, resourceSet *ResourceSet) error
 	GetByID(ctx context.Context, id string) (*ResourceSet, error)
+	GetByIDIncludingDeleted(ctx context.Context, id string) (*ResourceSet, error)
 	List(ctx context.Context, limit, offset int) ([]*ResourceSet, error)
 	Update(ctx context.Context, resourceSet *ResourceSet) error
 	Delete(ctx context.Context, id string) (*ResourceSet, error)
@@ -62,8 +102,33 @@ type ResourceSetRepository interface {
 type PermissionRepository interface {
 	Create(ctx context.Context, permission *Permission) error
 	GetByID(ctx context.Context, id string) (*Permission, error)
+	GetByIDIncludingDeleted(ctx context.Context, id string) (*Permission, error)
 	List(ctx context.Context, limit, offset int) ([]*Permission, error)
 	Update(ctx context.Context, permission *Permission) error
 	Delete(ctx context.Context, id string) (*Permission, error)
-	CheckPermission(ctx context.Context, userID, resourceID string) (bool, error)
+	ListBySubjects(ctx context.Context, subjects PermissionSubjects) ([]*Permission, error)
+	ListByTarget(ctx context.Context, target PermissionTarget) ([]*Permission, error)
+}
+
+// RelationTupleRepository defines the methods for relationship tuple data access
+type RelationTupleRepository interface {
+	Write(ctx context.Context, writes []*RelationTuple, deletes []*RelationTuple) error
+	List(ctx context.Context, filter RelationTupleFilter, limit, offset int) ([]*RelationTuple, error)
+}
+
+// RelationSchemaRepository defines the methods for relation schema data access
+type RelationSchemaRepository interface {
+	Create(ctx context.Context, schema *RelationSchema) error
+	GetByVersion(ctx context.Context, version int) (*RelationSchema, error)
+	GetLatest(ctx context.Context) (*RelationSchema, error)
+	List(ctx context.Context, limit, offset int) ([]*RelationSchema, error)
+}
+
+// RevisionRepository defines the methods for revision data access. Create must assign the revisions of
+// a tenant increasing IDs that become visible in that order, since ListAfter is used as a cursor.
+type RevisionRepository interface {
+	Create(ctx context.Context, revision *Revision) error
+	GetByID(ctx context.Context, id int64) (*Revision, error)
+	List(ctx context.Context, limit, offset int) ([]*Revision, error)
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]*Revision, error)
 }
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Types of the entities making up the authorization data
+const (
+	EntityResource     = "resource"
+	EntityAction       = "action"
+	EntityRole         = "role"
+	EntityRoleParent   = "role_parent"
+	EntityUser         = "user"
+	EntityUserRole     = "user_role"
+	EntityUserSet      = "user_set"
+	EntityResourceSet  = "resource_set"
+	EntityPermission   = "permission"
+	EntityRelationship = "relationship"
+	EntitySchema       = "schema"
+)
+
+// EntityTypes lists the types of the entities making up the authorization data
+var EntityTypes = []string{
+	EntityResource,
+	EntityAction,
+	EntityRole,
+	EntityRoleParent,
+	EntityUser,
+	EntityUserRole,
+	EntityUserSet,
+	EntityResourceSet,
+	EntityPermission,
+	EntityRelationship,
+	EntitySchema,
+}
+
+// Operations changing an entity
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// Revision is a numbered set of changes made to the authorization data of a tenant by a single operation.
+// Revision IDs increase monotonically, so the state of the model as of any revision can be rebuilt by
+// undoing the revisions that followed it.
+type Revision struct {
+	ID          int64            `json:"id"`
+	Description string           `json:"description"`
+	Changes     []RevisionChange `json:"changes"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// RevisionChange records the state of an entity before and after a change. States are encoded the way
+// bundles encode entities; Before is empty for creates and After is empty for deletes.
+type RevisionChange struct {
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Operation  string          `json:"operation"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+}
+
+// revisionTokenPrefix versions the format of revision tokens
+const revisionTokenPrefix = "r1:"
+
+// RevisionToken returns the opaque token standing for a revision, handed out to clients so that
+// they can ask for permission checks at least as fresh as the changes they made
+func RevisionToken(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(revisionTokenPrefix + strconv.FormatInt(id, 10)))
+}
+
+// ParseRevisionToken returns the ID of the revision a token stands for
+func ParseRevisionToken(token string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid revision token")
+	}
+	rest, ok := strings.CutPrefix(string(data), revisionTokenPrefix)
+	if !ok {
+		return 0, fmt.Errorf("invalid revision token")
+	}
+	id, err := strconv.ParseInt(rest, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid revision token")
+	}
+	return id, nil
+}
+
+// Consistency is the freshness a permission check requires of the data it is decided from.
+// The zero value accepts decisions cached before the latest changes were made.
+type Consistency struct {
+	// AtLeastAsFresh is the ID of a revision the decision must reflect, 0 for none
+	AtLeastAsFresh int64
+	// FullyConsistent requires the decision to be made from the latest data
+	FullyConsistent bool
+}
+
+// Merge returns the consistency satisfying both c and other
+func (c Consistency) Merge(other Consistency) Consistency {
+	if other.AtLeastAsFresh > c.AtLeastAsFresh {
+		c.AtLeastAsFresh = other.AtLeastAsFresh
+	}
+	c.FullyConsistent = c.FullyConsistent || other.FullyConsistent
+	return c
+}
@@ -0,0 +1,36 @@
+package domain
+
+// Types of the changes a simulation can propose
+const (
+	SimulationGrantPermission  = "grant_permission"
+	SimulationRevokePermission = "revoke_permission"
+	SimulationAssignRole       = "assign_role"
+	SimulationRevokeRole       = "revoke_role"
+	SimulationDeleteRole       = "delete_role"
+	SimulationSetAttributes    = "set_attributes"
+)
+
+// SimulationChange is a change proposed to a simulation. The fields used depend on the type:
+// grant_permission grants Permission to its role or user set, revoke_permission revokes PermissionID
+// from RoleID or UserSetID, assign_role and revoke_role assign or revoke RoleID for UserID,
+// delete_role deletes RoleID and set_attributes replaces the attributes of the user, resource or
+// action EntityID.
+type SimulationChange struct {
+	Type         string      `json:"type"`
+	Permission   *Permission `json:"permission,omitempty"`
+	PermissionID string      `json:"permission_id,omitempty"`
+	RoleID       string      `json:"role_id,omitempty"`
+	UserSetID    string      `json:"user_set_id,omitempty"`
+	UserID       string      `json:"user_id,omitempty"`
+	EntityType   string      `json:"entity_type,omitempty"`
+	EntityID     string      `json:"entity_id,omitempty"`
+	Attributes   []byte      `json:"attributes,omitempty"` // JSON serialized attributes
+}
+
+// SimulationResult is the decision of a check before and after the proposed changes
+type SimulationResult struct {
+	Check   PermissionCheck    `json:"check"`
+	Before  PermissionDecision `json:"before"`
+	After   PermissionDecision `json:"after"`
+	Changed bool               `json:"changed"`
+}
@@ -0,0 +1,84 @@
+package domain
+
+import "github.com/arifsetyawan/validra/src/pkg/condition"
+
+// Sources a permission decision can come from
+const (
+	DecidedByLookup       = "lookup"
+	DecidedByPermission   = "permission"
+	DecidedByRelationship = "relationship"
+	DecidedByDefault      = "default"
+)
+
+// DecisionTrace records everything a permission check considered and what decided its outcome
+type DecisionTrace struct {
+	Roles              []RoleTrace        `json:"roles"`
+	UserSets           []SetTrace         `json:"user_sets"`
+	ResourceSets       []SetTrace         `json:"resource_sets"`
+	InheritsFrom       []string           `json:"inherits_from"`
+	Permissions        []PermissionTrace  `json:"permissions"`
+	Relationship       *RelationshipTrace `json:"relationship,omitempty"`
+	CombiningAlgorithm string             `json:"combining_algorithm,omitempty"`
+	DefaultEffect      string             `json:"default_effect,omitempty"`
+	Decision           DecisionRule       `json:"decision"`
+}
+
+// RoleTrace describes a role held by the user, directly or through inheritance
+type RoleTrace struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Inherited bool   `json:"inherited"`
+}
+
+// SetTrace describes the evaluation of the conditions of a user set or resource set
+type SetTrace struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Member     bool             `json:"member"`
+	Conditions *condition.Trace `json:"conditions,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// PermissionTrace describes why a permission granted to the user applied to the check or not
+type PermissionTrace struct {
+	ID         string           `json:"id"`
+	Subject    string           `json:"subject"`
+	Effect     string           `json:"effect"`
+	Priority   int              `json:"priority"`
+	Applicable bool             `json:"applicable"`
+	Reason     string           `json:"reason"`
+	Conditions *condition.Trace `json:"conditions,omitempty"`
+}
+
+// RelationshipTrace describes the relationship check made when no permission applied
+type RelationshipTrace struct {
+	Tuple string   `json:"tuple"`
+	Found bool     `json:"found"`
+	Path  []string `json:"path"`
+	Error string   `json:"error,omitempty"`
+}
+
+// DecisionRule describes the rule that decided the outcome of a check
+type DecisionRule struct {
+	DecidedBy    string `json:"decided_by"`
+	Granted      bool   `json:"granted"`
+	PermissionID string `json:"permission_id,omitempty"`
+	Effect       string `json:"effect,omitempty"`
+	Reason       string `json:"reason"`
+}
+
+// Subject types an access grant can be about
+const (
+	GrantSubjectUser    = "user"
+	GrantSubjectRole    = "role"
+	GrantSubjectUserSet = "user_set"
+)
+
+// AccessGrant describes a subject that may perform an action on a resource and the path that grants it
+type AccessGrant struct {
+	SubjectType string   `json:"subject_type"`
+	SubjectID   string   `json:"subject_id"`
+	SubjectName string   `json:"subject_name"`
+	Reason      string   `json:"reason"`
+	Path        []string `json:"path"`
+}
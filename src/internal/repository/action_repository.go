@@ -25,6 +25,7 @@ func NewActionRepository(db *database.PostgresDB) domain.ActionRepository {
 // Action is the GORM model for actions
 type Action struct {
 	ID          string `gorm:"primaryKey"`
+	TenantID    string `gorm:"not null;index"`
 	ResourceID  string `gorm:"not null;index"`
 	Name        string `gorm:"not null"`
 	Description string
@@ -72,9 +73,9 @@ func (r *ActionRepository) Create(ctx context.Context, action *domain.Action) er
 	action.UpdatedAt = now
 
 	gormAction := actionFromDomain(action)
-	result := r.db.DB.WithContext(ctx).Create(gormAction)
+	result := r.db.WithContext(ctx).Create(gormAction)
 	if result.Error != nil {
-		return fmt.Errorf("failed to create action: %w", result.Error)
+		return fmt.Errorf("failed to create action: %w", duplicateKey(result.Error))
 	}
 
 	return nil
@@ -83,9 +84,9 @@ func (r *ActionRepository) Create(ctx context.Context, action *domain.Action) er
 // GetByID retrieves an action by ID
 func (r *ActionRepository) GetByID(ctx context.Context, id string) (*domain.Action, error) {
 	var action Action
-	result := r.db.DB.WithContext(ctx).First(&action, "id = ?", id)
+	result := r.db.WithContext(ctx).First(&action, "id = ?", id)
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to get action: %w", result.Error)
+		return nil, fmt.Errorf("failed to get action: %w", notFound(result.Error))
 	}
 
 	return action.toDomain(), nil
@@ -94,7 +95,7 @@ func (r *ActionRepository) GetByID(ctx context.Context, id string) (*domain.Acti
 // GetByResourceID retrieves actions by resource ID
 func (r *ActionRepository) GetByResourceID(ctx context.Context, resourceID string) ([]*domain.Action, error) {
 	var actions []Action
-	result := r.db.DB.WithContext(ctx).Where("resource_id = ?", resourceID).Find(&actions)
+	result := r.db.WithContext(ctx).Where("resource_id = ?", resourceID).Find(&actions)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to get actions: %w", result.Error)
 	}
@@ -110,7 +111,7 @@ func (r *ActionRepository) GetByResourceID(ctx context.Context, resourceID strin
 // List retrieves a paginated list of actions
 func (r *ActionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Action, error) {
 	var actions []Action
-	result := r.db.DB.WithContext(ctx).Limit(limit).Offset(offset).Find(&actions)
+	result := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&actions)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to list actions: %w", result.Error)
 	}
@@ -128,7 +129,7 @@ func (r *ActionRepository) Update(ctx context.Context, action *domain.Action) er
 	action.UpdatedAt = time.Now()
 
 	gormAction := actionFromDomain(action)
-	result := r.db.DB.WithContext(ctx).Save(gormAction)
+	result := r.db.WithContext(ctx).Save(gormAction)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update action: %w", result.Error)
 	}
@@ -144,14 +145,14 @@ func (r *ActionRepository) Update(ctx context.Context, action *domain.Action) er
 func (r *ActionRepository) Delete(ctx context.Context, id string) (*domain.Action, error) {
 	// First retrieve the action to return it after deletion
 	var action Action
-	getResult := r.db.DB.WithContext(ctx).First(&action, "id = ?", id)
+	getResult := r.db.WithContext(ctx).First(&action, "id = ?", id)
 	if getResult.Error != nil {
 		return nil, fmt.Errorf("failed to get resource: %w", getResult.Error)
 	}
 
 	// Perform soft delete
 	now := time.Now()
-	result := r.db.DB.WithContext(ctx).Model(&Action{}).Where("id = ?", id).Update("deleted_at", now)
+	result := r.db.WithContext(ctx).Model(&Action{}).Where("id = ?", id).Update("deleted_at", now)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to soft delete action: %w", result.Error)
 	}
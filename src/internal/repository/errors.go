@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"gorm.io/gorm"
+)
+
+// uniqueViolation is the Postgres error code of a statement breaking a unique constraint
+const uniqueViolation = "23505"
+
+// notFound replaces the error of a query finding no record with domain.ErrNotFound, so that services
+// can tell missing entities from failing queries
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrNotFound
+	}
+	return err
+}
+
+// duplicateKeyError is the error of a statement breaking a unique constraint. It reads like the error
+// of the database and matches domain.ErrDuplicateKey.
+type duplicateKeyError struct {
+	err error
+}
+
+func (e duplicateKeyError) Error() string {
+	return e.err.Error()
+}
+
+func (e duplicateKeyError) Unwrap() error {
+	return e.err
+}
+
+func (e duplicateKeyError) Is(target error) bool {
+	return target == domain.ErrDuplicateKey
+}
+
+// duplicateKey marks the error of a statement breaking a unique constraint as a domain.ErrDuplicateKey
+func duplicateKey(err error) error {
+	var sqlErr interface{ SQLState() string }
+	if errors.As(err, &sqlErr) && sqlErr.SQLState() == uniqueViolation {
+		return duplicateKeyError{err: err}
+	}
+	return err
+}
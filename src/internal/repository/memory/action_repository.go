@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ActionRepository implements domain.ActionRepository in memory
+type ActionRepository struct {
+	store *Store
+}
+
+// NewActionRepository creates a new in-memory repository for actions
+func NewActionRepository(store *Store) domain.ActionRepository {
+	return &ActionRepository{
+		store: store,
+	}
+}
+
+// Create stores a new action
+func (r *ActionRepository) Create(ctx context.Context, action *domain.Action) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if action.ID == "" {
+		action.ID = uuid.New().String()
+	}
+	now := time.Now()
+	action.CreatedAt = now
+	action.UpdatedAt = now
+
+	if find(r.store.tables.actions, func(e *domain.Action) bool { return e.ID == action.ID }) >= 0 {
+		return fmt.Errorf("failed to create action: %w", errDuplicateKey)
+	}
+	r.store.tables.actions = append(r.store.tables.actions, clone(action))
+	return nil
+}
+
+// GetByID retrieves an action by ID
+func (r *ActionRepository) GetByID(ctx context.Context, id string) (*domain.Action, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.actions, func(e *domain.Action) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get action: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.actions[i]), nil
+}
+
+// GetByResourceID retrieves the actions of a resource
+func (r *ActionRepository) GetByResourceID(ctx context.Context, resourceID string) ([]*domain.Action, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return filter(r.store.tables.actions, func(e *domain.Action) bool { return e.ResourceID == resourceID }), nil
+}
+
+// List retrieves a paginated list of actions
+func (r *ActionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Action, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return page(filter(r.store.tables.actions, all[domain.Action]), limit, offset), nil
+}
+
+// Update replaces a stored action
+func (r *ActionRepository) Update(ctx context.Context, action *domain.Action) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	action.UpdatedAt = time.Now()
+
+	i := find(r.store.tables.actions, func(e *domain.Action) bool { return e.ID == action.ID })
+	if i < 0 {
+		return fmt.Errorf("action not found")
+	}
+	r.store.tables.actions[i] = clone(action)
+	return nil
+}
+
+// Delete performs a soft delete on an action and returns the deleted action
+func (r *ActionRepository) Delete(ctx context.Context, id string) (*domain.Action, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.actions, func(e *domain.Action) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get resource: %w", errRecordNotFound)
+	}
+
+	now := time.Now()
+	action := clone(r.store.tables.actions[i])
+	action.DeletedAt = &now
+	r.store.tables.actions[i] = action
+	return clone(action), nil
+}
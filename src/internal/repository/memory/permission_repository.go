@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PermissionRepository implements domain.PermissionRepository in memory
+type PermissionRepository struct {
+	store *Store
+}
+
+// NewPermissionRepository creates a new in-memory repository for permissions
+func NewPermissionRepository(store *Store) domain.PermissionRepository {
+	return &PermissionRepository{
+		store: store,
+	}
+}
+
+// Create stores a new permission
+func (r *PermissionRepository) Create(ctx context.Context, permission *domain.Permission) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if permission.ID == "" {
+		permission.ID = uuid.New().String()
+	}
+	now := time.Now()
+	permission.CreatedAt = now
+	permission.UpdatedAt = now
+
+	if find(r.store.tables.permissions, func(e *domain.Permission) bool { return e.ID == permission.ID }) >= 0 {
+		return fmt.Errorf("failed to create permission: %w", errDuplicateKey)
+	}
+	r.store.tables.permissions = append(r.store.tables.permissions, clone(permission))
+	return nil
+}
+
+// GetByID retrieves an active permission by ID
+func (r *PermissionRepository) GetByID(ctx context.Context, id string) (*domain.Permission, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.permissions, func(e *domain.Permission) bool { return e.ID == id && e.DeletedAt == nil })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get permission: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.permissions[i]), nil
+}
+
+// GetByIDIncludingDeleted retrieves a permission by ID, whether it is deleted or not
+func (r *PermissionRepository) GetByIDIncludingDeleted(ctx context.Context, id string) (*domain.Permission, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.permissions, func(e *domain.Permission) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get permission: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.permissions[i]), nil
+}
+
+// List retrieves a paginated list of active permissions
+func (r *PermissionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Permission, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	permissions := filter(r.store.tables.permissions, func(e *domain.Permission) bool { return e.DeletedAt == nil })
+	return page(permissions, limit, offset), nil
+}
+
+// ListBySubjects retrieves every active permission granted directly to the user
+// or to any of the given roles or user sets
+func (r *PermissionRepository) ListBySubjects(ctx context.Context, subjects domain.PermissionSubjects) ([]*domain.Permission, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return filter(r.store.tables.permissions, func(e *domain.Permission) bool {
+		if e.DeletedAt != nil {
+			return false
+		}
+		if subjects.UserID != "" && e.UserID != nil && *e.UserID == subjects.UserID {
+			return true
+		}
+		if e.UserSetID != nil && contains(subjects.UserSetIDs, *e.UserSetID) {
+			return true
+		}
+		return contains(subjects.RoleIDs, e.RoleID)
+	}), nil
+}
+
+// ListByTarget retrieves every active permission that may apply to the target, whoever it is granted to,
+// leaving its conditions to be evaluated by the caller
+func (r *PermissionRepository) ListByTarget(ctx context.Context, target domain.PermissionTarget) ([]*domain.Permission, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return filter(r.store.tables.permissions, func(e *domain.Permission) bool {
+		return e.DeletedAt == nil &&
+			(e.ResourceSetID == nil || contains(target.ResourceSetIDs, *e.ResourceSetID)) &&
+			(e.ResourceID == nil || contains(target.ResourceIDs, *e.ResourceID)) &&
+			(e.ActionID == nil || contains(target.ActionIDs, *e.ActionID)) &&
+			(e.ActionName == nil || *e.ActionName == target.ActionName)
+	}), nil
+}
+
+// Update replaces a stored permission
+func (r *PermissionRepository) Update(ctx context.Context, permission *domain.Permission) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	permission.UpdatedAt = time.Now()
+
+	i := find(r.store.tables.permissions, func(e *domain.Permission) bool { return e.ID == permission.ID })
+	if i < 0 {
+		return fmt.Errorf("permission not found")
+	}
+	r.store.tables.permissions[i] = clone(permission)
+	return nil
+}
+
+// Delete performs a soft delete on a permission and returns the deleted permission
+func (r *PermissionRepository) Delete(ctx context.Context, id string) (*domain.Permission, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.permissions, func(e *domain.Permission) bool { return e.ID == id && e.DeletedAt == nil })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get permission: %w", errRecordNotFound)
+	}
+
+	now := time.Now()
+	permission := clone(r.store.tables.permissions[i])
+	permission.DeletedAt = &now
+	r.store.tables.permissions[i] = permission
+	return clone(permission), nil
+}
+
+// contains reports whether a value is in a list
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/google/uuid"
+)
+
+// RelationSchemaRepository implements domain.RelationSchemaRepository in memory
+type RelationSchemaRepository struct {
+	store *Store
+}
+
+// NewRelationSchemaRepository creates a new in-memory repository for relation schemas
+func NewRelationSchemaRepository(store *Store) domain.RelationSchemaRepository {
+	return &RelationSchemaRepository{
+		store: store,
+	}
+}
+
+// Create stores the schema as the version following the latest one
+func (r *RelationSchemaRepository) Create(ctx context.Context, schema *domain.RelationSchema) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if schema.ID == "" {
+		schema.ID = uuid.New().String()
+	}
+	schema.CreatedAt = time.Now()
+
+	// Schemas are stored in version order, so the latest one is the last
+	schema.Version = 1
+	if n := len(r.store.tables.schemas); n > 0 {
+		schema.Version = r.store.tables.schemas[n-1].Version + 1
+	}
+
+	r.store.tables.schemas = append(r.store.tables.schemas, clone(schema))
+	return nil
+}
+
+// GetByVersion retrieves a version of the schema
+func (r *RelationSchemaRepository) GetByVersion(ctx context.Context, version int) (*domain.RelationSchema, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.schemas, func(e *domain.RelationSchema) bool { return e.Version == version })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get schema: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.schemas[i]), nil
+}
+
+// GetLatest retrieves the latest version of the schema, nil when no schema was written
+func (r *RelationSchemaRepository) GetLatest(ctx context.Context) (*domain.RelationSchema, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	n := len(r.store.tables.schemas)
+	if n == 0 {
+		return nil, nil
+	}
+	return clone(r.store.tables.schemas[n-1]), nil
+}
+
+// List retrieves a paginated list of schema versions, latest first
+func (r *RelationSchemaRepository) List(ctx context.Context, limit, offset int) ([]*domain.RelationSchema, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	schemas := make([]*domain.RelationSchema, 0, len(r.store.tables.schemas))
+	for i := len(r.store.tables.schemas) - 1; i >= 0; i-- {
+		schemas = append(schemas, clone(r.store.tables.schemas[i]))
+	}
+	return page(schemas, limit, offset), nil
+}
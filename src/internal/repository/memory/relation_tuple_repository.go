@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/google/uuid"
+)
+
+// RelationTupleRepository implements domain.RelationTupleRepository in memory
+type RelationTupleRepository struct {
+	store *Store
+}
+
+// NewRelationTupleRepository creates a new in-memory repository for relationship tuples
+func NewRelationTupleRepository(store *Store) domain.RelationTupleRepository {
+	return &RelationTupleRepository{
+		store: store,
+	}
+}
+
+// Write inserts and deletes relationship tuples.
+// Writing a tuple that already exists and deleting a tuple that does not exist are no-ops.
+func (r *RelationTupleRepository) Write(ctx context.Context, writes []*domain.RelationTuple, deletes []*domain.RelationTuple) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, tuple := range deletes {
+		if i := find(r.store.tables.tuples, sameTuple(tuple)); i >= 0 {
+			r.store.tables.tuples = append(r.store.tables.tuples[:i:i], r.store.tables.tuples[i+1:]...)
+		}
+	}
+
+	now := time.Now()
+	for _, tuple := range writes {
+		if tuple.ID == "" {
+			tuple.ID = uuid.New().String()
+		}
+		tuple.CreatedAt = now
+
+		if find(r.store.tables.tuples, func(e *domain.RelationTuple) bool { return e.ID == tuple.ID || sameTuple(tuple)(e) }) >= 0 {
+			continue
+		}
+		r.store.tables.tuples = append(r.store.tables.tuples, clone(tuple))
+	}
+
+	return nil
+}
+
+// List retrieves a paginated list of relationship tuples matching the filter
+func (r *RelationTupleRepository) List(ctx context.Context, filter domain.RelationTupleFilter, limit, offset int) ([]*domain.RelationTuple, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	tuples := filterTuples(r.store.tables.tuples, filter)
+	sort.SliceStable(tuples, func(i, j int) bool {
+		a, b := tuples[i], tuples[j]
+		for _, k := range [][2]string{
+			{a.ObjectType, b.ObjectType},
+			{a.ObjectID, b.ObjectID},
+			{a.Relation, b.Relation},
+			{a.SubjectType, b.SubjectType},
+			{a.SubjectID, b.SubjectID},
+			{a.SubjectRelation, b.SubjectRelation},
+		} {
+			if k[0] != k[1] {
+				return k[0] < k[1]
+			}
+		}
+		return false
+	})
+	if limit > 0 {
+		tuples = page(tuples, limit, offset)
+	}
+	return tuples, nil
+}
+
+// filterTuples returns copies of the tuples matching a filter
+func filterTuples(tuples []*domain.RelationTuple, f domain.RelationTupleFilter) []*domain.RelationTuple {
+	return filter(tuples, func(e *domain.RelationTuple) bool {
+		return (f.ObjectType == "" || e.ObjectType == f.ObjectType) &&
+			(f.ObjectID == "" || e.ObjectID == f.ObjectID) &&
+			(f.Relation == "" || e.Relation == f.Relation) &&
+			(f.SubjectType == "" || e.SubjectType == f.SubjectType) &&
+			(f.SubjectID == "" || e.SubjectID == f.SubjectID) &&
+			(f.SubjectRelation == nil || e.SubjectRelation == *f.SubjectRelation)
+	})
+}
+
+// sameTuple matches the tuples relating the same object and subject as a tuple
+func sameTuple(tuple *domain.RelationTuple) func(*domain.RelationTuple) bool {
+	return func(e *domain.RelationTuple) bool {
+		return e.ObjectType == tuple.ObjectType && e.ObjectID == tuple.ObjectID && e.Relation == tuple.Relation &&
+			e.SubjectType == tuple.SubjectType && e.SubjectID == tuple.SubjectID && e.SubjectRelation == tuple.SubjectRelation
+	}
+}
@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ResourceRepository implements domain.ResourceRepository in memory
+type ResourceRepository struct {
+	store *Store
+}
+
+// NewResourceRepository creates a new in-memory repository for resources
+func NewResourceRepository(store *Store) domain.ResourceRepository {
+	return &ResourceRepository{
+		store: store,
+	}
+}
+
+// Create stores a new resource
+func (r *ResourceRepository) Create(ctx context.Context, resource *domain.Resource) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if resource.ID == "" {
+		resource.ID = uuid.New().String()
+	}
+	now := time.Now()
+	resource.CreatedAt = now
+	resource.UpdatedAt = now
+
+	if find(r.store.tables.resources, func(e *domain.Resource) bool { return e.ID == resource.ID }) >= 0 {
+		return fmt.Errorf("failed to create resource: %w", errDuplicateKey)
+	}
+	r.store.tables.resources = append(r.store.tables.resources, clone(resource))
+	return nil
+}
+
+// GetByID retrieves a resource by ID
+func (r *ResourceRepository) GetByID(ctx context.Context, id string) (*domain.Resource, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.resources, func(e *domain.Resource) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get resource: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.resources[i]), nil
+}
+
+// GetByName retrieves an active resource by name
+func (r *ResourceRepository) GetByName(ctx context.Context, name string) (*domain.Resource, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.resources, func(e *domain.Resource) bool { return e.Name == name && e.DeletedAt == nil })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get resource: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.resources[i]), nil
+}
+
+// List retrieves a paginated list of resources
+func (r *ResourceRepository) List(ctx context.Context, limit, offset int) ([]*domain.Resource, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return page(filter(r.store.tables.resources, all[domain.Resource]), limit, offset), nil
+}
+
+// ListChildren retrieves a paginated list of the active resources nested directly under a resource
+func (r *ResourceRepository) ListChildren(ctx context.Context, parentID string, limit, offset int) ([]*domain.Resource, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	children := filter(r.store.tables.resources, func(e *domain.Resource) bool {
+		return e.ParentID != nil && *e.ParentID == parentID && e.DeletedAt == nil
+	})
+	sortBy(children, func(e *domain.Resource) string { return e.Name })
+	return page(children, limit, offset), nil
+}
+
+// ListByDefaultEffect retrieves a paginated list of the active resources overriding the default effect with the given one
+func (r *ResourceRepository) ListByDefaultEffect(ctx context.Context, effect string, limit, offset int) ([]*domain.Resource, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	resources := filter(r.store.tables.resources, func(e *domain.Resource) bool {
+		return e.DefaultEffect == effect && e.DeletedAt == nil
+	})
+	sortBy(resources, func(e *domain.Resource) string { return e.ID })
+	return page(resources, limit, offset), nil
+}
+
+// Update replaces a stored resource
+func (r *ResourceRepository) Update(ctx context.Context, resource *domain.Resource) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	resource.UpdatedAt = time.Now()
+
+	i := find(r.store.tables.resources, func(e *domain.Resource) bool { return e.ID == resource.ID })
+	if i < 0 {
+		return fmt.Errorf("resource not found")
+	}
+	r.store.tables.resources[i] = clone(resource)
+	return nil
+}
+
+// Delete performs a soft delete on a resource and returns the deleted resource
+func (r *ResourceRepository) Delete(ctx context.Context, id string) (*domain.Resource, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.resources, func(e *domain.Resource) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get resource: %w", errRecordNotFound)
+	}
+
+	now := time.Now()
+	resource := clone(r.store.tables.resources[i])
+	resource.DeletedAt = &now
+	r.store.tables.resources[i] = resource
+	return clone(resource), nil
+}
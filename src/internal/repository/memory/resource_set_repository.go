@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ResourceSetRepository implements domain.ResourceSetRepository in memory
+type ResourceSetRepository struct {
+	store *Store
+}
+
+// NewResourceSetRepository creates a new in-memory repository for resource sets
+func NewResourceSetRepository(store *Store) domain.ResourceSetRepository {
+	return &ResourceSetRepository{
+		store: store,
+	}
+}
+
+// Create stores a new resource set
+func (r *ResourceSetRepository) Create(ctx context.Context, resourceSet *domain.ResourceSet) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if resourceSet.ID == "" {
+		resourceSet.ID = uuid.New().String()
+	}
+	now := time.Now()
+	resourceSet.CreatedAt = now
+	resourceSet.UpdatedAt = now
+
+	if find(r.store.tables.resourceSets, func(e *domain.ResourceSet) bool { return e.ID == resourceSet.ID }) >= 0 {
+		return fmt.Errorf("failed to create resource set: %w", errDuplicateKey)
+	}
+	r.store.tables.resourceSets = append(r.store.tables.resourceSets, clone(resourceSet))
+	return nil
+}
+
+// GetByID retrieves an active resource set by ID
+func (r *ResourceSetRepository) GetByID(ctx context.Context, id string) (*domain.ResourceSet, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.resourceSets, func(e *domain.ResourceSet) bool { return e.ID == id && e.DeletedAt == nil })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get resource set: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.resourceSets[i]), nil
+}
+
+// GetByIDIncludingDeleted retrieves a resource set by ID, whether it is deleted or not
+func (r *ResourceSetRepository) GetByIDIncludingDeleted(ctx context.Context, id string) (*domain.ResourceSet, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.resourceSets, func(e *domain.ResourceSet) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get resource set: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.resourceSets[i]), nil
+}
+
+// List retrieves a paginated list of active resource sets ordered by name
+func (r *ResourceSetRepository) List(ctx context.Context, limit, offset int) ([]*domain.ResourceSet, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	resourceSets := filter(r.store.tables.resourceSets, func(e *domain.ResourceSet) bool { return e.DeletedAt == nil })
+	sortBy(resourceSets, func(e *domain.ResourceSet) string { return e.Name })
+	return page(resourceSets, limit, offset), nil
+}
+
+// Update replaces a stored resource set
+func (r *ResourceSetRepository) Update(ctx context.Context, resourceSet *domain.ResourceSet) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	resourceSet.UpdatedAt = time.Now()
+
+	i := find(r.store.tables.resourceSets, func(e *domain.ResourceSet) bool { return e.ID == resourceSet.ID })
+	if i < 0 {
+		return fmt.Errorf("resource set not found")
+	}
+	r.store.tables.resourceSets[i] = clone(resourceSet)
+	return nil
+}
+
+// Delete performs a soft delete on a resource set and returns the deleted resource set
+func (r *ResourceSetRepository) Delete(ctx context.Context, id string) (*domain.ResourceSet, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.resourceSets, func(e *domain.ResourceSet) bool { return e.ID == id && e.DeletedAt == nil })
+	if i < 0 {
+		return nil, fmt.Errorf("resource set not found")
+	}
+
+	now := time.Now()
+	resourceSet := clone(r.store.tables.resourceSets[i])
+	resourceSet.DeletedAt = &now
+	r.store.tables.resourceSets[i] = resourceSet
+	return clone(resourceSet), nil
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// RevisionRepository implements domain.RevisionRepository in memory
+type RevisionRepository struct {
+	store *Store
+}
+
+// NewRevisionRepository creates a new in-memory repository for revisions
+func NewRevisionRepository(store *Store) domain.RevisionRepository {
+	return &RevisionRepository{
+		store: store,
+	}
+}
+
+// Create stores a new revision, numbering it after the last one
+func (r *RevisionRepository) Create(ctx context.Context, revision *domain.Revision) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.tables.lastRevision++
+	revision.ID = r.store.tables.lastRevision
+	revision.CreatedAt = time.Now()
+
+	r.store.tables.revisions = append(r.store.tables.revisions, clone(revision))
+	return nil
+}
+
+// GetByID retrieves a revision by ID
+func (r *RevisionRepository) GetByID(ctx context.Context, id int64) (*domain.Revision, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.revisions, func(e *domain.Revision) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get revision: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.revisions[i]), nil
+}
+
+// List retrieves a paginated list of revisions, latest first
+func (r *RevisionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Revision, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	revisions := make([]*domain.Revision, 0, len(r.store.tables.revisions))
+	for i := len(r.store.tables.revisions) - 1; i >= 0; i-- {
+		revisions = append(revisions, clone(r.store.tables.revisions[i]))
+	}
+	return page(revisions, limit, offset), nil
+}
+
+// ListAfter retrieves up to limit revisions following a revision, oldest first
+func (r *RevisionRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]*domain.Revision, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	revisions := filter(r.store.tables.revisions, func(e *domain.Revision) bool { return e.ID > afterID })
+	return page(revisions, limit, 0), nil
+}
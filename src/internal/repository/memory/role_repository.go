@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/google/uuid"
+)
+
+// RoleRepository implements domain.RoleRepository in memory
+type RoleRepository struct {
+	store *Store
+}
+
+// NewRoleRepository creates a new in-memory repository for roles
+func NewRoleRepository(store *Store) domain.RoleRepository {
+	return &RoleRepository{
+		store: store,
+	}
+}
+
+// Create stores a new role
+func (r *RoleRepository) Create(ctx context.Context, role *domain.Role) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if role.ID == "" {
+		role.ID = uuid.New().String()
+	}
+	now := time.Now()
+	role.CreatedAt = now
+	role.UpdatedAt = now
+
+	if find(r.store.tables.roles, func(e *domain.Role) bool { return e.ID == role.ID }) >= 0 {
+		return fmt.Errorf("failed to create role: %w", errDuplicateKey)
+	}
+	r.store.tables.roles = append(r.store.tables.roles, clone(role))
+	return nil
+}
+
+// GetByID retrieves a role by ID
+func (r *RoleRepository) GetByID(ctx context.Context, id string) (*domain.Role, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.roles, func(e *domain.Role) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get role: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.roles[i]), nil
+}
+
+// List retrieves a paginated list of roles
+func (r *RoleRepository) List(ctx context.Context, limit, offset int) ([]*domain.Role, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return page(filter(r.store.tables.roles, all[domain.Role]), limit, offset), nil
+}
+
+// Update replaces a stored role
+func (r *RoleRepository) Update(ctx context.Context, role *domain.Role) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	role.UpdatedAt = time.Now()
+
+	i := find(r.store.tables.roles, func(e *domain.Role) bool { return e.ID == role.ID })
+	if i < 0 {
+		return fmt.Errorf("role not found")
+	}
+	r.store.tables.roles[i] = clone(role)
+	return nil
+}
+
+// Delete performs a soft delete on a role and returns the deleted role
+func (r *RoleRepository) Delete(ctx context.Context, id string) (*domain.Role, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.roles, func(e *domain.Role) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get role: %w", errRecordNotFound)
+	}
+
+	now := time.Now()
+	role := clone(r.store.tables.roles[i])
+	role.DeletedAt = &now
+	r.store.tables.roles[i] = role
+	return clone(role), nil
+}
+
+// AddParent makes a role inherit the grants of a parent role
+func (r *RoleRepository) AddParent(ctx context.Context, roleParent *domain.RoleParent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	roleParent.CreatedAt = time.Now()
+
+	if find(r.store.tables.roleParents, matchRoleParent(roleParent.RoleID, roleParent.ParentID)) >= 0 {
+		return fmt.Errorf("failed to add parent role: %w", errDuplicateKey)
+	}
+	r.store.tables.roleParents = append(r.store.tables.roleParents, clone(roleParent))
+	return nil
+}
+
+// RemoveParent removes an inheritance link between a role and its parent
+func (r *RoleRepository) RemoveParent(ctx context.Context, roleID, parentID string) (*domain.RoleParent, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.roleParents, matchRoleParent(roleID, parentID))
+	if i < 0 {
+		return nil, fmt.Errorf("parent role not found")
+	}
+
+	roleParent := r.store.tables.roleParents[i]
+	r.store.tables.roleParents = append(r.store.tables.roleParents[:i:i], r.store.tables.roleParents[i+1:]...)
+	return clone(roleParent), nil
+}
+
+// ListParents retrieves the active roles a role directly inherits from
+func (r *RoleRepository) ListParents(ctx context.Context, roleID string) ([]*domain.Role, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	parents := filter(r.store.tables.roles, func(e *domain.Role) bool {
+		return e.DeletedAt == nil && find(r.store.tables.roleParents, matchRoleParent(roleID, e.ID)) >= 0
+	})
+	sortBy(parents, func(e *domain.Role) string { return e.Name })
+	return parents, nil
+}
+
+// ListChildren retrieves the active roles directly inheriting from a role
+func (r *RoleRepository) ListChildren(ctx context.Context, roleID string) ([]*domain.Role, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	children := filter(r.store.tables.roles, func(e *domain.Role) bool {
+		return e.DeletedAt == nil && find(r.store.tables.roleParents, matchRoleParent(e.ID, roleID)) >= 0
+	})
+	sortBy(children, func(e *domain.Role) string { return e.Name })
+	return children, nil
+}
+
+// LockHierarchy does nothing, since the store only keeps the data of a single caller
+func (r *RoleRepository) LockHierarchy(ctx context.Context) error {
+	return nil
+}
+
+// matchRoleParent matches the inheritance link between a role and a parent
+func matchRoleParent(roleID, parentID string) func(*domain.RoleParent) bool {
+	return func(e *domain.RoleParent) bool {
+		return e.RoleID == roleID && e.ParentID == parentID
+	}
+}
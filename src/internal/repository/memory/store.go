@@ -0,0 +1,133 @@
+// Package memory implements the repositories in memory, for running the engine without a database
+// such as when testing a policy. A store holds the entities of a single tenant.
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// Errors wrapped by the repositories, worded like those of the database
+var (
+	errRecordNotFound = domain.ErrNotFound
+	errDuplicateKey   = domain.ErrDuplicateKey
+)
+
+// Store holds the entities of the in-memory repositories. It implements domain.Transactor: a
+// transaction snapshots the store and restores the snapshot when it fails. Transactions are not
+// isolated from each other, so a store is meant to be used by one caller at a time.
+type Store struct {
+	mu     sync.Mutex
+	tables tables
+}
+
+// tables holds the stored entities in insertion order. Stored entities are never modified in place,
+// an update stores a new copy, so copying the slices is enough to snapshot the tables.
+type tables struct {
+	resources    []*domain.Resource
+	actions      []*domain.Action
+	roles        []*domain.Role
+	roleParents  []*domain.RoleParent
+	users        []*domain.User
+	userRoles    []*domain.UserRole
+	userSets     []*domain.UserSet
+	resourceSets []*domain.ResourceSet
+	permissions  []*domain.Permission
+	tuples       []*domain.RelationTuple
+	schemas      []*domain.RelationSchema
+	revisions    []*domain.Revision
+	lastRevision int64
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{}
+}
+
+// InTransaction runs fn and restores the store as it was before fn when fn fails
+func (s *Store) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	s.mu.Lock()
+	snapshot := s.tables.snapshot()
+	s.mu.Unlock()
+
+	if err := fn(ctx); err != nil {
+		s.mu.Lock()
+		s.tables = snapshot
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// snapshot copies the tables
+func (t tables) snapshot() tables {
+	return tables{
+		resources:    append([]*domain.Resource(nil), t.resources...),
+		actions:      append([]*domain.Action(nil), t.actions...),
+		roles:        append([]*domain.Role(nil), t.roles...),
+		roleParents:  append([]*domain.RoleParent(nil), t.roleParents...),
+		users:        append([]*domain.User(nil), t.users...),
+		userRoles:    append([]*domain.UserRole(nil), t.userRoles...),
+		userSets:     append([]*domain.UserSet(nil), t.userSets...),
+		resourceSets: append([]*domain.ResourceSet(nil), t.resourceSets...),
+		permissions:  append([]*domain.Permission(nil), t.permissions...),
+		tuples:       append([]*domain.RelationTuple(nil), t.tuples...),
+		schemas:      append([]*domain.RelationSchema(nil), t.schemas...),
+		revisions:    append([]*domain.Revision(nil), t.revisions...),
+		lastRevision: t.lastRevision,
+	}
+}
+
+// clone copies an entity, so that callers never hold an entity of the store
+func clone[T any](entity *T) *T {
+	c := *entity
+	return &c
+}
+
+// find returns the index of the first entity matching, -1 when there is none
+func find[T any](entities []*T, match func(*T) bool) int {
+	for i, e := range entities {
+		if match(e) {
+			return i
+		}
+	}
+	return -1
+}
+
+// filter returns copies of the entities matching
+func filter[T any](entities []*T, match func(*T) bool) []*T {
+	matching := []*T{}
+	for _, e := range entities {
+		if match(e) {
+			matching = append(matching, clone(e))
+		}
+	}
+	return matching
+}
+
+// sortBy sorts entities by a key
+func sortBy[T any](entities []*T, key func(*T) string) {
+	sort.SliceStable(entities, func(i, j int) bool {
+		return key(entities[i]) < key(entities[j])
+	})
+}
+
+// page returns a page of entities. A limit that is not positive returns every entity from offset.
+func page[T any](entities []*T, limit, offset int) []*T {
+	if offset >= len(entities) {
+		return []*T{}
+	}
+	entities = entities[offset:]
+	if limit > 0 && limit < len(entities) {
+		entities = entities[:limit]
+	}
+	return entities
+}
+
+// all matches every entity
+func all[T any](*T) bool {
+	return true
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/google/uuid"
+)
+
+// UserRepository implements domain.UserRepository in memory
+type UserRepository struct {
+	store *Store
+}
+
+// NewUserRepository creates a new in-memory repository for users
+func NewUserRepository(store *Store) domain.UserRepository {
+	return &UserRepository{
+		store: store,
+	}
+}
+
+// Create stores a new user. Usernames are unique, deleted users included.
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if user.ID == "" {
+		user.ID = uuid.New().String()
+	}
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	if find(r.store.tables.users, func(e *domain.User) bool { return e.ID == user.ID || e.Username == user.Username }) >= 0 {
+		return fmt.Errorf("failed to create user: %w", errDuplicateKey)
+	}
+	r.store.tables.users = append(r.store.tables.users, clone(user))
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.users, func(e *domain.User) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get user: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.users[i]), nil
+}
+
+// GetByUsername retrieves a user by username
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.users, func(e *domain.User) bool { return e.Username == username })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get user: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.users[i]), nil
+}
+
+// List retrieves a paginated list of users
+func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*domain.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return page(filter(r.store.tables.users, all[domain.User]), limit, offset), nil
+}
+
+// Update replaces a stored user
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user.UpdatedAt = time.Now()
+
+	i := find(r.store.tables.users, func(e *domain.User) bool { return e.ID == user.ID })
+	if i < 0 {
+		return fmt.Errorf("user not found")
+	}
+	if find(r.store.tables.users, func(e *domain.User) bool { return e.ID != user.ID && e.Username == user.Username }) >= 0 {
+		return fmt.Errorf("failed to update user: %w", errDuplicateKey)
+	}
+	r.store.tables.users[i] = clone(user)
+	return nil
+}
+
+// Delete performs a soft delete on a user and returns the deleted user
+func (r *UserRepository) Delete(ctx context.Context, id string) (*domain.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.users, func(e *domain.User) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get user: %w", errRecordNotFound)
+	}
+
+	now := time.Now()
+	user := clone(r.store.tables.users[i])
+	user.DeletedAt = &now
+	r.store.tables.users[i] = user
+	return clone(user), nil
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/google/uuid"
+)
+
+// UserRoleRepository implements domain.UserRoleRepository in memory
+type UserRoleRepository struct {
+	store *Store
+}
+
+// NewUserRoleRepository creates a new in-memory repository for user-to-role assignments
+func NewUserRoleRepository(store *Store) domain.UserRoleRepository {
+	return &UserRoleRepository{
+		store: store,
+	}
+}
+
+// Create stores a new role assignment. A role is assigned to a user at most once.
+func (r *UserRoleRepository) Create(ctx context.Context, userRole *domain.UserRole) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if userRole.ID == "" {
+		userRole.ID = uuid.New().String()
+	}
+	userRole.CreatedAt = time.Now()
+
+	if find(r.store.tables.userRoles, func(e *domain.UserRole) bool {
+		return e.ID == userRole.ID || (e.UserID == userRole.UserID && e.RoleID == userRole.RoleID)
+	}) >= 0 {
+		return fmt.Errorf("failed to assign role: %w", errDuplicateKey)
+	}
+	r.store.tables.userRoles = append(r.store.tables.userRoles, clone(userRole))
+	return nil
+}
+
+// Get retrieves the assignment of a role to a user
+func (r *UserRoleRepository) Get(ctx context.Context, userID, roleID string) (*domain.UserRole, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.userRoles, matchUserRole(userID, roleID))
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get role assignment: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.userRoles[i]), nil
+}
+
+// Delete removes the assignment of a role to a user and returns the removed assignment
+func (r *UserRoleRepository) Delete(ctx context.Context, userID, roleID string) (*domain.UserRole, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.userRoles, matchUserRole(userID, roleID))
+	if i < 0 {
+		return nil, fmt.Errorf("role assignment not found")
+	}
+
+	userRole := r.store.tables.userRoles[i]
+	r.store.tables.userRoles = append(r.store.tables.userRoles[:i:i], r.store.tables.userRoles[i+1:]...)
+	return clone(userRole), nil
+}
+
+// ListRolesByUserID retrieves the active roles assigned to a user
+func (r *UserRoleRepository) ListRolesByUserID(ctx context.Context, userID string) ([]*domain.Role, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	roles := filter(r.store.tables.roles, func(e *domain.Role) bool {
+		return e.DeletedAt == nil && find(r.store.tables.userRoles, matchUserRole(userID, e.ID)) >= 0
+	})
+	sortBy(roles, func(e *domain.Role) string { return e.Name })
+	return roles, nil
+}
+
+// ListUsersByRoleID retrieves the active users a role is assigned to
+func (r *UserRoleRepository) ListUsersByRoleID(ctx context.Context, roleID string) ([]*domain.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	users := filter(r.store.tables.users, func(e *domain.User) bool {
+		return e.DeletedAt == nil && find(r.store.tables.userRoles, matchUserRole(e.ID, roleID)) >= 0
+	})
+	sortBy(users, func(e *domain.User) string { return e.Username })
+	return users, nil
+}
+
+// List retrieves a paginated list of role assignments in the order they were made
+func (r *UserRoleRepository) List(ctx context.Context, limit, offset int) ([]*domain.UserRole, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return page(filter(r.store.tables.userRoles, all[domain.UserRole]), limit, offset), nil
+}
+
+// matchUserRole matches the assignment of a role to a user
+func matchUserRole(userID, roleID string) func(*domain.UserRole) bool {
+	return func(e *domain.UserRole) bool {
+		return e.UserID == userID && e.RoleID == roleID
+	}
+}
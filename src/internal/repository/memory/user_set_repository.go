@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/google/uuid"
+)
+
+// UserSetRepository implements domain.UserSetRepository in memory
+type UserSetRepository struct {
+	store *Store
+}
+
+// NewUserSetRepository creates a new in-memory repository for user sets
+func NewUserSetRepository(store *Store) domain.UserSetRepository {
+	return &UserSetRepository{
+		store: store,
+	}
+}
+
+// Create stores a new user set
+func (r *UserSetRepository) Create(ctx context.Context, userSet *domain.UserSet) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if userSet.ID == "" {
+		userSet.ID = uuid.New().String()
+	}
+	now := time.Now()
+	userSet.CreatedAt = now
+	userSet.UpdatedAt = now
+
+	if find(r.store.tables.userSets, func(e *domain.UserSet) bool { return e.ID == userSet.ID }) >= 0 {
+		return fmt.Errorf("failed to create user set: %w", errDuplicateKey)
+	}
+	r.store.tables.userSets = append(r.store.tables.userSets, clone(userSet))
+	return nil
+}
+
+// GetByID retrieves an active user set by ID
+func (r *UserSetRepository) GetByID(ctx context.Context, id string) (*domain.UserSet, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.userSets, func(e *domain.UserSet) bool { return e.ID == id && e.DeletedAt == nil })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get user set: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.userSets[i]), nil
+}
+
+// GetByIDIncludingDeleted retrieves a user set by ID, whether it is deleted or not
+func (r *UserSetRepository) GetByIDIncludingDeleted(ctx context.Context, id string) (*domain.UserSet, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.userSets, func(e *domain.UserSet) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("failed to get user set: %w", errRecordNotFound)
+	}
+	return clone(r.store.tables.userSets[i]), nil
+}
+
+// List retrieves a paginated list of active user sets ordered by name
+func (r *UserSetRepository) List(ctx context.Context, limit, offset int) ([]*domain.UserSet, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	userSets := filter(r.store.tables.userSets, func(e *domain.UserSet) bool { return e.DeletedAt == nil })
+	sortBy(userSets, func(e *domain.UserSet) string { return e.Name })
+	return page(userSets, limit, offset), nil
+}
+
+// Update replaces a stored user set
+func (r *UserSetRepository) Update(ctx context.Context, userSet *domain.UserSet) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	userSet.UpdatedAt = time.Now()
+
+	i := find(r.store.tables.userSets, func(e *domain.UserSet) bool { return e.ID == userSet.ID })
+	if i < 0 {
+		return fmt.Errorf("user set not found")
+	}
+	r.store.tables.userSets[i] = clone(userSet)
+	return nil
+}
+
+// Delete performs a soft delete on a user set and returns the deleted user set
+func (r *UserSetRepository) Delete(ctx context.Context, id string) (*domain.UserSet, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	i := find(r.store.tables.userSets, func(e *domain.UserSet) bool { return e.ID == id && e.DeletedAt == nil })
+	if i < 0 {
+		return nil, fmt.Errorf("user set not found")
+	}
+
+	now := time.Now()
+	userSet := clone(r.store.tables.userSets[i])
+	userSet.DeletedAt = &now
+	r.store.tables.userSets[i] = userSet
+	return clone(userSet), nil
+}
@@ -0,0 +1,176 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/internal/repository"
+	"github.com/arifsetyawan/validra/src/internal/service"
+	"github.com/arifsetyawan/validra/src/pkg/database"
+	"github.com/arifsetyawan/validra/src/pkg/tenant"
+	"github.com/google/uuid"
+)
+
+// migratedDB connects to the PostgreSQL database configured through the DB_* environment variables and
+// migrates it. The test is skipped when DB_HOST is not set.
+func migratedDB(t *testing.T) *database.PostgresDB {
+	t.Helper()
+
+	host := os.Getenv("DB_HOST")
+	if host == "" {
+		t.Skip("DB_HOST is not set, skipping PostgreSQL test")
+	}
+	port, err := strconv.Atoi(getEnv("DB_PORT", "5432"))
+	if err != nil {
+		t.Fatalf("invalid DB_PORT: %v", err)
+	}
+
+	db, err := database.NewPostgresDB(
+		host,
+		getEnv("DB_USER", "postgres"),
+		getEnv("DB_PASSWORD", "postgres"),
+		getEnv("DB_NAME", "validra"),
+		port,
+		getEnv("DB_SSL_MODE", "disable"),
+	)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return db
+}
+
+// getEnv retrieves an environment variable or returns a default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// TestMigratedSchema runs the queries made by permission checks, lookups and deletes against a freshly
+// migrated schema, so that every column they read must have been created by Migrate
+func TestMigratedSchema(t *testing.T) {
+	db := migratedDB(t)
+	ctx := tenant.WithID(context.Background(), "migration-"+uuid.New().String())
+
+	resourceRepo := repository.NewResourceRepository(db)
+	actionRepo := repository.NewActionRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	userRoleRepo := repository.NewUserRoleRepository(db)
+	userSetRepo := repository.NewUserSetRepository(db)
+	resourceSetRepo := repository.NewResourceSetRepository(db)
+	permissionRepo := repository.NewPermissionRepository(db)
+	tupleRepo := repository.NewRelationTupleRepository(db)
+	schemaRepo := repository.NewRelationSchemaRepository(db)
+	revisionService := service.NewRevisionService(db, repository.NewRevisionRepository(db))
+
+	parent := &domain.Resource{Name: "documents"}
+	if err := resourceRepo.Create(ctx, parent); err != nil {
+		t.Fatalf("create resource: %v", err)
+	}
+	child := &domain.Resource{Name: "report", ParentID: &parent.ID, DefaultEffect: domain.EffectDeny}
+	if err := resourceRepo.Create(ctx, child); err != nil {
+		t.Fatalf("create resource: %v", err)
+	}
+	action := &domain.Action{ResourceID: parent.ID, Name: "read"}
+	if err := actionRepo.Create(ctx, action); err != nil {
+		t.Fatalf("create action: %v", err)
+	}
+	parentRole := &domain.Role{Name: "reader"}
+	if err := roleRepo.Create(ctx, parentRole); err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+	role := &domain.Role{Name: "editor"}
+	if err := roleRepo.Create(ctx, role); err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+	if err := roleRepo.AddParent(ctx, &domain.RoleParent{RoleID: role.ID, ParentID: parentRole.ID}); err != nil {
+		t.Fatalf("add parent role: %v", err)
+	}
+	user := &domain.User{Username: "alice"}
+	if err := userRepo.Create(ctx, user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := userRoleRepo.Create(ctx, &domain.UserRole{UserID: user.ID, RoleID: role.ID}); err != nil {
+		t.Fatalf("assign role: %v", err)
+	}
+	permission := &domain.Permission{RoleID: parentRole.ID, ResourceID: &parent.ID, ActionID: &action.ID, Effect: domain.EffectAllow}
+	if err := permissionRepo.Create(ctx, permission); err != nil {
+		t.Fatalf("create permission: %v", err)
+	}
+
+	if _, err := resourceRepo.GetByName(ctx, "report"); err != nil {
+		t.Errorf("get resource by name: %v", err)
+	}
+	if children, err := resourceRepo.ListChildren(ctx, parent.ID, 10, 0); err != nil || len(children) != 1 {
+		t.Errorf("list child resources: %d, %v", len(children), err)
+	}
+	if resources, err := resourceRepo.ListByDefaultEffect(ctx, domain.EffectDeny, 10, 0); err != nil || len(resources) != 1 {
+		t.Errorf("list resources by default effect: %d, %v", len(resources), err)
+	}
+	if parents, err := roleRepo.ListParents(ctx, role.ID); err != nil || len(parents) != 1 {
+		t.Errorf("list parent roles: %d, %v", len(parents), err)
+	}
+	if children, err := roleRepo.ListChildren(ctx, parentRole.ID); err != nil || len(children) != 1 {
+		t.Errorf("list child roles: %d, %v", len(children), err)
+	}
+	if roles, err := userRoleRepo.ListRolesByUserID(ctx, user.ID); err != nil || len(roles) != 1 {
+		t.Errorf("list roles of user: %d, %v", len(roles), err)
+	}
+	if users, err := userRoleRepo.ListUsersByRoleID(ctx, role.ID); err != nil || len(users) != 1 {
+		t.Errorf("list users of role: %d, %v", len(users), err)
+	}
+	target := domain.PermissionTarget{ResourceIDs: []string{parent.ID, child.ID}, ActionIDs: []string{action.ID}, ActionName: action.Name}
+	if permissions, err := permissionRepo.ListByTarget(ctx, target); err != nil || len(permissions) != 1 {
+		t.Errorf("list permissions by target: %d, %v", len(permissions), err)
+	}
+
+	permissionService := service.NewPermissionService(
+		userRepo,
+		actionRepo,
+		resourceRepo,
+		roleRepo,
+		permissionRepo,
+		userRoleRepo,
+		userSetRepo,
+		resourceSetRepo,
+		tupleRepo,
+		schemaRepo,
+		domain.CombiningPolicy{Algorithm: domain.CombiningDenyOverrides, DefaultEffect: domain.EffectDeny},
+		revisionService,
+		nil,
+	)
+	granted, _, err := permissionService.CheckPermission(ctx, "alice", "read", "documents", nil)
+	if err != nil || !granted {
+		t.Errorf("check permission: granted %v, %v", granted, err)
+	}
+
+	if _, err := permissionRepo.Delete(ctx, permission.ID); err != nil {
+		t.Errorf("delete permission: %v", err)
+	}
+	if _, err := userRepo.Delete(ctx, user.ID); err != nil {
+		t.Errorf("delete user: %v", err)
+	}
+	if _, err := roleRepo.Delete(ctx, role.ID); err != nil {
+		t.Errorf("delete role: %v", err)
+	}
+	if _, err := actionRepo.Delete(ctx, action.ID); err != nil {
+		t.Errorf("delete action: %v", err)
+	}
+	if _, err := resourceRepo.Delete(ctx, child.ID); err != nil {
+		t.Errorf("delete resource: %v", err)
+	}
+	if _, err := resourceRepo.GetByName(ctx, "report"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("get deleted resource by name: %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/database"
+	"github.com/google/uuid"
+)
+
+// PermissionRepository implements domain.PermissionRepository using GORM with PostgreSQL
+type PermissionRepository struct {
+	db *database.PostgresDB
+}
+
+// NewPermissionRepository creates a new GORM repository for permissions
+func NewPermissionRepository(db *database.PostgresDB) domain.PermissionRepository {
+	return &PermissionRepository{
+		db: db,
+	}
+}
+
+// Permission is the GORM model for permissions
+type Permission struct {
+	ID            string  `gorm:"primaryKey"`
+	TenantID      string  `gorm:"not null;index"`
+	RoleID        string  `gorm:"index"`
+	UserID        *string `gorm:"index"`
+	UserSetID     *string `gorm:"index"`
+	ResourceID    *string `gorm:"index"`
+	ResourceSetID *string `gorm:"index"`
+	ActionID      *string `gorm:"index"`
+	ActionName    *string
+	Effect        string `gorm:"not null"`
+	Priority      int    `gorm:"not null;default:0"`
+	Conditions    []byte
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     *time.Time `gorm:"index"`
+}
+
+// toDomain converts a GORM model to a domain model
+func (p *Permission) toDomain() *domain.Permission {
+	return &domain.Permission{
+		ID:            p.ID,
+		RoleID:        p.RoleID,
+		UserID:        p.UserID,
+		UserSetID:     p.UserSetID,
+		ResourceID:    p.ResourceID,
+		ResourceSetID: p.ResourceSetID,
+		ActionID:      p.ActionID,
+		ActionName:    p.ActionName,
+		Effect:        p.Effect,
+		Priority:      p.Priority,
+		Conditions:    p.Conditions,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+		DeletedAt:     p.DeletedAt,
+	}
+}
+
+// fromDomain converts a domain model to a GORM model
+func permissionFromDomain(p *domain.Permission) *Permission {
+	return &Permission{
+		ID:            p.ID,
+		RoleID:        p.RoleID,
+		UserID:        p.UserID,
+		UserSetID:     p.UserSetID,
+		ResourceID:    p.ResourceID,
+		ResourceSetID: p.ResourceSetID,
+		ActionID:      p.ActionID,
+		ActionName:    p.ActionName,
+		Effect:        p.Effect,
+		Priority:      p.Priority,
+		Conditions:    p.Conditions,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+		DeletedAt:     p.DeletedAt,
+	}
+}
+
+// Create inserts a new permission into the database
+func (r *PermissionRepository) Create(ctx context.Context, permission *domain.Permission) error {
+	// Generate a new UUID if not provided
+	if permission.ID == "" {
+		permission.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	permission.CreatedAt = now
+	permission.UpdatedAt = now
+
+	gormPermission := permissionFromDomain(permission)
+	result := r.db.WithContext(ctx).Create(gormPermission)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create permission: %w", duplicateKey(result.Error))
+	}
+
+	return nil
+}
+
+// GetByID retrieves a permission by ID
+func (r *PermissionRepository) GetByID(ctx context.Context, id string) (*domain.Permission, error) {
+	var permission Permission
+	result := r.db.WithContext(ctx).First(&permission, "id = ? AND deleted_at IS NULL", id)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get permission: %w", notFound(result.Error))
+	}
+
+	return permission.toDomain(), nil
+}
+
+// GetByIDIncludingDeleted retrieves a permission by ID, whether it is deleted or not
+func (r *PermissionRepository) GetByIDIncludingDeleted(ctx context.Context, id string) (*domain.Permission, error) {
+	var permission Permission
+	result := r.db.WithContext(ctx).First(&permission, "id = ?", id)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get permission: %w", notFound(result.Error))
+	}
+
+	return permission.toDomain(), nil
+}
+
+// List retrieves a paginated list of permissions
+func (r *PermissionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Permission, error) {
+	var permissions []Permission
+	result := r.db.WithContext(ctx).Where("deleted_at IS NULL").Limit(limit).Offset(offset).Find(&permissions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list permissions: %w", result.Error)
+	}
+
+	return permissionsToDomain(permissions), nil
+}
+
+// ListBySubjects retrieves every active permission granted directly to the user
+// or to any of the given roles or user sets
+func (r *PermissionRepository) ListBySubjects(ctx context.Context, subjects domain.PermissionSubjects) ([]*domain.Permission, error) {
+	var clauses []string
+	var args []interface{}
+	if subjects.UserID != "" {
+		clauses = append(clauses, "user_id = ?")
+		args = append(args, subjects.UserID)
+	}
+	if len(subjects.RoleIDs) > 0 {
+		clauses = append(clauses, "role_id IN ?")
+		args = append(args, subjects.RoleIDs)
+	}
+	if len(subjects.UserSetIDs) > 0 {
+		clauses = append(clauses, "user_set_id IN ?")
+		args = append(args, subjects.UserSetIDs)
+	}
+	if len(clauses) == 0 {
+		return []*domain.Permission{}, nil
+	}
+
+	var permissions []Permission
+	result := r.db.WithContext(ctx).
+		Where("deleted_at IS NULL").
+		Where("("+strings.Join(clauses, " OR ")+")", args...).
+		Find(&permissions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list permissions: %w", result.Error)
+	}
+
+	return permissionsToDomain(permissions), nil
+}
+
+// ListByTarget retrieves every active permission that may apply to the target, whoever it is granted to,
+// leaving its conditions to be evaluated by the caller
+func (r *PermissionRepository) ListByTarget(ctx context.Context, target domain.PermissionTarget) ([]*domain.Permission, error) {
+	var permissions []Permission
+	result := r.db.WithContext(ctx).
+		Where("deleted_at IS NULL").
+		Where("(resource_set_id IS NULL OR resource_set_id IN ?)", target.ResourceSetIDs).
+		Where("(resource_id IS NULL OR resource_id IN ?)", target.ResourceIDs).
+		Where("(action_id IS NULL OR action_id IN ?)", target.ActionIDs).
+		Where("(action_name IS NULL OR action_name = ?)", target.ActionName).
+		Find(&permissions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list permissions: %w", result.Error)
+	}
+
+	return permissionsToDomain(permissions), nil
+}
+
+// Update updates a permission in the database
+func (r *PermissionRepository) Update(ctx context.Context, permission *domain.Permission) error {
+	permission.UpdatedAt = time.Now()
+
+	gormPermission := permissionFromDomain(permission)
+	result := r.db.WithContext(ctx).Save(gormPermission)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update permission: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("permission not found")
+	}
+
+	return nil
+}
+
+// Delete performs a soft delete on a permission and returns the deleted permission
+func (r *PermissionRepository) Delete(ctx context.Context, id string) (*domain.Permission, error) {
+	// First retrieve the permission to return it after deletion
+	var permission Permission
+	getResult := r.db.WithContext(ctx).First(&permission, "id = ? AND deleted_at IS NULL", id)
+	if getResult.Error != nil {
+		return nil, fmt.Errorf("failed to get permission: %w", getResult.Error)
+	}
+
+	// Perform soft delete
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&Permission{}).Where("id = ?", id).Update("deleted_at", now)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to soft delete permission: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("permission not found")
+	}
+
+	// Update the retrieved permission with deletion time
+	permission.DeletedAt = &now
+
+	return permission.toDomain(), nil
+}
+
+// permissionsToDomain converts a slice of GORM models to domain models
+func permissionsToDomain(permissions []Permission) []*domain.Permission {
+	domainPermissions := make([]*domain.Permission, len(permissions))
+	for i, permission := range permissions {
+		domainPermissions[i] = permission.toDomain()
+	}
+	return domainPermissions
+}
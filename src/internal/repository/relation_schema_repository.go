@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RelationSchemaRepository implements domain.RelationSchemaRepository using GORM with PostgreSQL
+type RelationSchemaRepository struct {
+	db *database.PostgresDB
+}
+
+// NewRelationSchemaRepository creates a new GORM repository for relation schemas
+func NewRelationSchemaRepository(db *database.PostgresDB) domain.RelationSchemaRepository {
+	return &RelationSchemaRepository{
+		db: db,
+	}
+}
+
+// RelationSchema is the GORM model for relation schemas
+type RelationSchema struct {
+	ID         string `gorm:"primaryKey"`
+	TenantID   string `gorm:"not null;index"`
+	Version    int    `gorm:"not null;uniqueIndex"`
+	Definition []byte `gorm:"not null"`
+	CreatedAt  time.Time
+}
+
+// toDomain converts a GORM model to a domain model
+func (s *RelationSchema) toDomain() *domain.RelationSchema {
+	return &domain.RelationSchema{
+		ID:         s.ID,
+		Version:    s.Version,
+		Definition: s.Definition,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+// Create stores the schema as the version following the latest one
+func (r *RelationSchemaRepository) Create(ctx context.Context, schema *domain.RelationSchema) error {
+	// Generate a new UUID if not provided
+	if schema.ID == "" {
+		schema.ID = uuid.New().String()
+	}
+	schema.CreatedAt = time.Now()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&RelationSchema{}).Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return fmt.Errorf("failed to get latest schema version: %w", err)
+		}
+		schema.Version = latest + 1
+
+		result := tx.Create(&RelationSchema{
+			ID:         schema.ID,
+			Version:    schema.Version,
+			Definition: schema.Definition,
+			CreatedAt:  schema.CreatedAt,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to create schema: %w", duplicateKey(result.Error))
+		}
+
+		return nil
+	})
+}
+
+// GetByVersion retrieves a schema by version
+func (r *RelationSchemaRepository) GetByVersion(ctx context.Context, version int) (*domain.RelationSchema, error) {
+	var schema RelationSchema
+	result := r.db.WithContext(ctx).First(&schema, "version = ?", version)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get schema: %w", result.Error)
+	}
+
+	return schema.toDomain(), nil
+}
+
+// GetLatest retrieves the latest schema version. It returns nil when no schema has been written.
+func (r *RelationSchemaRepository) GetLatest(ctx context.Context) (*domain.RelationSchema, error) {
+	var schemas []RelationSchema
+	result := r.db.WithContext(ctx).Order("version DESC").Limit(1).Find(&schemas)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get latest schema: %w", result.Error)
+	}
+	if len(schemas) == 0 {
+		return nil, nil
+	}
+
+	return schemas[0].toDomain(), nil
+}
+
+// List retrieves a paginated list of schema versions, latest first
+func (r *RelationSchemaRepository) List(ctx context.Context, limit, offset int) ([]*domain.RelationSchema, error) {
+	var schemas []RelationSchema
+	result := r.db.WithContext(ctx).Order("version DESC").Limit(limit).Offset(offset).Find(&schemas)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", result.Error)
+	}
+
+	domainSchemas := make([]*domain.RelationSchema, len(schemas))
+	for i, schema := range schemas {
+		domainSchemas[i] = schema.toDomain()
+	}
+
+	return domainSchemas, nil
+}
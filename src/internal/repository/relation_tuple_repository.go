@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RelationTupleRepository implements domain.RelationTupleRepository using GORM with PostgreSQL
+type RelationTupleRepository struct {
+	db *database.PostgresDB
+}
+
+// NewRelationTupleRepository creates a new GORM repository for relationship tuples
+func NewRelationTupleRepository(db *database.PostgresDB) domain.RelationTupleRepository {
+	return &RelationTupleRepository{
+		db: db,
+	}
+}
+
+// RelationTuple is the GORM model for relationship tuples
+type RelationTuple struct {
+	ID              string `gorm:"primaryKey"`
+	TenantID        string `gorm:"not null;index"`
+	ObjectType      string `gorm:"not null;uniqueIndex:idx_relation_tuples_tuple;index:idx_relation_tuples_object"`
+	ObjectID        string `gorm:"not null;uniqueIndex:idx_relation_tuples_tuple;index:idx_relation_tuples_object"`
+	Relation        string `gorm:"not null;uniqueIndex:idx_relation_tuples_tuple;index:idx_relation_tuples_object"`
+	SubjectType     string `gorm:"not null;uniqueIndex:idx_relation_tuples_tuple;index:idx_relation_tuples_subject"`
+	SubjectID       string `gorm:"not null;uniqueIndex:idx_relation_tuples_tuple;index:idx_relation_tuples_subject"`
+	SubjectRelation string `gorm:"not null;default:'';uniqueIndex:idx_relation_tuples_tuple"`
+	CreatedAt       time.Time
+}
+
+// toDomain converts a GORM model to a domain model
+func (t *RelationTuple) toDomain() *domain.RelationTuple {
+	return &domain.RelationTuple{
+		ID:              t.ID,
+		ObjectType:      t.ObjectType,
+		ObjectID:        t.ObjectID,
+		Relation:        t.Relation,
+		SubjectType:     t.SubjectType,
+		SubjectID:       t.SubjectID,
+		SubjectRelation: t.SubjectRelation,
+		CreatedAt:       t.CreatedAt,
+	}
+}
+
+// Write atomically inserts and deletes relationship tuples.
+// Writing a tuple that already exists and deleting a tuple that does not exist are no-ops.
+func (r *RelationTupleRepository) Write(ctx context.Context, writes []*domain.RelationTuple, deletes []*domain.RelationTuple) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, tuple := range deletes {
+			result := tx.Where(
+				"object_type = ? AND object_id = ? AND relation = ? AND subject_type = ? AND subject_id = ? AND subject_relation = ?",
+				tuple.ObjectType, tuple.ObjectID, tuple.Relation, tuple.SubjectType, tuple.SubjectID, tuple.SubjectRelation,
+			).Delete(&RelationTuple{})
+			if result.Error != nil {
+				return fmt.Errorf("failed to delete relationship %s: %w", tuple, result.Error)
+			}
+		}
+
+		now := time.Now()
+		for _, tuple := range writes {
+			// Generate a new UUID if not provided
+			if tuple.ID == "" {
+				tuple.ID = uuid.New().String()
+			}
+			tuple.CreatedAt = now
+
+			gormTuple := &RelationTuple{
+				ID:              tuple.ID,
+				ObjectType:      tuple.ObjectType,
+				ObjectID:        tuple.ObjectID,
+				Relation:        tuple.Relation,
+				SubjectType:     tuple.SubjectType,
+				SubjectID:       tuple.SubjectID,
+				SubjectRelation: tuple.SubjectRelation,
+				CreatedAt:       tuple.CreatedAt,
+			}
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(gormTuple)
+			if result.Error != nil {
+				return fmt.Errorf("failed to write relationship %s: %w", tuple, result.Error)
+			}
+		}
+
+		return nil
+	})
+}
+
+// List retrieves a paginated list of relationship tuples matching the filter
+func (r *RelationTupleRepository) List(ctx context.Context, filter domain.RelationTupleFilter, limit, offset int) ([]*domain.RelationTuple, error) {
+	query := r.db.WithContext(ctx)
+	if filter.ObjectType != "" {
+		query = query.Where("object_type = ?", filter.ObjectType)
+	}
+	if filter.ObjectID != "" {
+		query = query.Where("object_id = ?", filter.ObjectID)
+	}
+	if filter.Relation != "" {
+		query = query.Where("relation = ?", filter.Relation)
+	}
+	if filter.SubjectType != "" {
+		query = query.Where("subject_type = ?", filter.SubjectType)
+	}
+	if filter.SubjectID != "" {
+		query = query.Where("subject_id = ?", filter.SubjectID)
+	}
+	if filter.SubjectRelation != nil {
+		query = query.Where("subject_relation = ?", *filter.SubjectRelation)
+	}
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+
+	var tuples []RelationTuple
+	result := query.Order("object_type, object_id, relation, subject_type, subject_id, subject_relation").Find(&tuples)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list relationships: %w", result.Error)
+	}
+
+	domainTuples := make([]*domain.RelationTuple, len(tuples))
+	for i, tuple := range tuples {
+		domainTuples[i] = tuple.toDomain()
+	}
+
+	return domainTuples, nil
+}
@@ -19,7 +19,10 @@ type ResourceRepository struct {
 type ResourceRepositoryInterface interface {
 	Create(ctx context.Context, resource *domain.Resource) error
 	GetByID(ctx context.Context, id string) (*domain.Resource, error)
+	GetByName(ctx context.Context, name string) (*domain.Resource, error)
 	List(ctx context.Context, limit, offset int) ([]*domain.Resource, error)
+	ListChildren(ctx context.Context, parentID string, limit, offset int) ([]*domain.Resource, error)
+	ListByDefaultEffect(ctx context.Context, effect string, limit, offset int) ([]*domain.Resource, error)
 	Update(ctx context.Context, resource *domain.Resource) error
 	Delete(ctx context.Context, id string) (*domain.Resource, error)
 }
@@ -33,38 +36,51 @@ func NewResourceRepository(db *database.PostgresDB) ResourceRepositoryInterface
 
 // Resource is the GORM model for resources
 type Resource struct {
-	ID          string `json:"id" gorm:"primaryKey"`
-	Name        string `json:"name" gorm:"not null"`
-	Description string
-	Attributes  []byte
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	DeletedAt   *time.Time `gorm:"index"`
+	ID                 string `json:"id" gorm:"primaryKey"`
+	TenantID           string `gorm:"not null;index"`
+	Name               string `json:"name" gorm:"not null"`
+	Description        string
+	Attributes         []byte
+	CombiningAlgorithm string
+	DefaultEffect      string
+	ParentID           *string `gorm:"index"`
+	BlockInheritance   bool    `gorm:"not null;default:false"`
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	DeletedAt          *time.Time `gorm:"index"`
 }
 
 // toDomain converts a GORM model to a domain model
 func (r *Resource) toDomain() *domain.Resource {
 	return &domain.Resource{
-		ID:          r.ID,
-		Name:        r.Name,
-		Description: r.Description,
-		Attributes:  r.Attributes,
-		CreatedAt:   r.CreatedAt,
-		UpdatedAt:   r.UpdatedAt,
-		DeletedAt:   r.DeletedAt,
+		ID:                 r.ID,
+		Name:               r.Name,
+		Description:        r.Description,
+		Attributes:         r.Attributes,
+		CombiningAlgorithm: r.CombiningAlgorithm,
+		DefaultEffect:      r.DefaultEffect,
+		ParentID:           r.ParentID,
+		BlockInheritance:   r.BlockInheritance,
+		CreatedAt:          r.CreatedAt,
+		UpdatedAt:          r.UpdatedAt,
+		DeletedAt:          r.DeletedAt,
 	}
 }
 
 // fromDomain converts a domain model to a GORM model
 func fromDomain(r *domain.Resource) *Resource {
 	return &Resource{
-		ID:          r.ID,
-		Name:        r.Name,
-		Description: r.Description,
-		Attributes:  r.Attributes,
-		CreatedAt:   r.CreatedAt,
-		UpdatedAt:   r.UpdatedAt,
-		DeletedAt:   r.DeletedAt,
+		ID:                 r.ID,
+		Name:               r.Name,
+		Description:        r.Description,
+		Attributes:         r.Attributes,
+		CombiningAlgorithm: r.CombiningAlgorithm,
+		DefaultEffect:      r.DefaultEffect,
+		ParentID:           r.ParentID,
+		BlockInheritance:   r.BlockInheritance,
+		CreatedAt:          r.CreatedAt,
+		UpdatedAt:          r.UpdatedAt,
+		DeletedAt:          r.DeletedAt,
 	}
 }
 
@@ -80,9 +96,9 @@ func (r *ResourceRepository) Create(ctx context.Context, resource *domain.Resour
 	resource.UpdatedAt = now
 
 	gormResource := fromDomain(resource)
-	result := r.db.DB.WithContext(ctx).Create(gormResource)
+	result := r.db.WithContext(ctx).Create(gormResource)
 	if result.Error != nil {
-		return fmt.Errorf("failed to create resource: %w", result.Error)
+		return fmt.Errorf("failed to create resource: %w", duplicateKey(result.Error))
 	}
 
 	return nil
@@ -91,9 +107,20 @@ func (r *ResourceRepository) Create(ctx context.Context, resource *domain.Resour
 // GetByID retrieves a resource by ID
 func (r *ResourceRepository) GetByID(ctx context.Context, id string) (*domain.Resource, error) {
 	var resource Resource
-	result := r.db.DB.WithContext(ctx).First(&resource, "id = ?", id)
+	result := r.db.WithContext(ctx).First(&resource, "id = ?", id)
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to get resource: %w", result.Error)
+		return nil, fmt.Errorf("failed to get resource: %w", notFound(result.Error))
+	}
+
+	return resource.toDomain(), nil
+}
+
+// GetByName retrieves an active resource by name
+func (r *ResourceRepository) GetByName(ctx context.Context, name string) (*domain.Resource, error) {
+	var resource Resource
+	result := r.db.WithContext(ctx).Where("name = ? AND deleted_at IS NULL", name).First(&resource)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get resource: %w", notFound(result.Error))
 	}
 
 	return resource.toDomain(), nil
@@ -102,7 +129,49 @@ func (r *ResourceRepository) GetByID(ctx context.Context, id string) (*domain.Re
 // List retrieves a paginated list of resources
 func (r *ResourceRepository) List(ctx context.Context, limit, offset int) ([]*domain.Resource, error) {
 	var resources []Resource
-	result := r.db.DB.WithContext(ctx).Limit(limit).Offset(offset).Find(&resources)
+	result := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&resources)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list resources: %w", result.Error)
+	}
+
+	domainResources := make([]*domain.Resource, len(resources))
+	for i, resource := range resources {
+		domainResources[i] = resource.toDomain()
+	}
+
+	return domainResources, nil
+}
+
+// ListChildren retrieves a paginated list of the resources nested directly under a resource
+func (r *ResourceRepository) ListChildren(ctx context.Context, parentID string, limit, offset int) ([]*domain.Resource, error) {
+	var resources []Resource
+	result := r.db.WithContext(ctx).
+		Where("parent_id = ? AND deleted_at IS NULL", parentID).
+		Order("name").
+		Limit(limit).
+		Offset(offset).
+		Find(&resources)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list child resources: %w", result.Error)
+	}
+
+	domainResources := make([]*domain.Resource, len(resources))
+	for i, resource := range resources {
+		domainResources[i] = resource.toDomain()
+	}
+
+	return domainResources, nil
+}
+
+// ListByDefaultEffect retrieves a paginated list of the resources overriding the default effect with the given one
+func (r *ResourceRepository) ListByDefaultEffect(ctx context.Context, effect string, limit, offset int) ([]*domain.Resource, error) {
+	var resources []Resource
+	result := r.db.WithContext(ctx).
+		Where("default_effect = ? AND deleted_at IS NULL", effect).
+		Order("id").
+		Limit(limit).
+		Offset(offset).
+		Find(&resources)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to list resources: %w", result.Error)
 	}
@@ -120,7 +189,7 @@ func (r *ResourceRepository) Update(ctx context.Context, resource *domain.Resour
 	resource.UpdatedAt = time.Now()
 
 	gormResource := fromDomain(resource)
-	result := r.db.DB.WithContext(ctx).Save(gormResource)
+	result := r.db.WithContext(ctx).Save(gormResource)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update resource: %w", result.Error)
 	}
@@ -136,14 +205,14 @@ func (r *ResourceRepository) Update(ctx context.Context, resource *domain.Resour
 func (r *ResourceRepository) Delete(ctx context.Context, id string) (*domain.Resource, error) {
 	// First retrieve the resource to return it after deletion
 	var resource Resource
-	getResult := r.db.DB.WithContext(ctx).First(&resource, "id = ?", id)
+	getResult := r.db.WithContext(ctx).First(&resource, "id = ?", id)
 	if getResult.Error != nil {
 		return nil, fmt.Errorf("failed to get resource: %w", getResult.Error)
 	}
 
 	// Perform soft delete
 	now := time.Now()
-	result := r.db.DB.WithContext(ctx).Model(&Resource{}).Where("id = ?", id).Update("deleted_at", now)
+	result := r.db.WithContext(ctx).Model(&Resource{}).Where("id = ?", id).Update("deleted_at", now)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to soft delete resource: %w", result.Error)
 	}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/database"
+	"github.com/google/uuid"
+)
+
+// ResourceSetRepository implements domain.ResourceSetRepository using GORM with PostgreSQL
+type ResourceSetRepository struct {
+	db *database.PostgresDB
+}
+
+// NewResourceSetRepository creates a new GORM repository for resource sets
+func NewResourceSetRepository(db *database.PostgresDB) domain.ResourceSetRepository {
+	return &ResourceSetRepository{
+		db: db,
+	}
+}
+
+// ResourceSet is the GORM model for resource sets
+type ResourceSet struct {
+	ID          string `gorm:"primaryKey"`
+	TenantID    string `gorm:"not null;index"`
+	Name        string `gorm:"not null"`
+	Description string
+	Conditions  []byte
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time `gorm:"index"`
+}
+
+// toDomain converts a GORM model to a domain model
+func (rs *ResourceSet) toDomain() *domain.ResourceSet {
+	return &domain.ResourceSet{
+		ID:          rs.ID,
+		Name:        rs.Name,
+		Description: rs.Description,
+		Conditions:  rs.Conditions,
+		CreatedAt:   rs.CreatedAt,
+		UpdatedAt:   rs.UpdatedAt,
+		DeletedAt:   rs.DeletedAt,
+	}
+}
+
+// fromDomain converts a domain model to a GORM model
+func resourceSetFromDomain(rs *domain.ResourceSet) *ResourceSet {
+	return &ResourceSet{
+		ID:          rs.ID,
+		Name:        rs.Name,
+		Description: rs.Description,
+		Conditions:  rs.Conditions,
+		CreatedAt:   rs.CreatedAt,
+		UpdatedAt:   rs.UpdatedAt,
+		DeletedAt:   rs.DeletedAt,
+	}
+}
+
+// Create inserts a new resource set into the database
+func (r *ResourceSetRepository) Create(ctx context.Context, resourceSet *domain.ResourceSet) error {
+	// Generate a new UUID if not provided
+	if resourceSet.ID == "" {
+		resourceSet.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	resourceSet.CreatedAt = now
+	resourceSet.UpdatedAt = now
+
+	gormResourceSet := resourceSetFromDomain(resourceSet)
+	result := r.db.WithContext(ctx).Create(gormResourceSet)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create resource set: %w", duplicateKey(result.Error))
+	}
+
+	return nil
+}
+
+// GetByID retrieves a resource set by ID
+func (r *ResourceSetRepository) GetByID(ctx context.Context, id string) (*domain.ResourceSet, error) {
+	var resourceSet ResourceSet
+	result := r.db.WithContext(ctx).First(&resourceSet, "id = ? AND deleted_at IS NULL", id)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get resource set: %w", notFound(result.Error))
+	}
+
+	return resourceSet.toDomain(), nil
+}
+
+// GetByIDIncludingDeleted retrieves a resource set by ID, whether it is deleted or not
+func (r *ResourceSetRepository) GetByIDIncludingDeleted(ctx context.Context, id string) (*domain.ResourceSet, error) {
+	var resourceSet ResourceSet
+	result := r.db.WithContext(ctx).First(&resourceSet, "id = ?", id)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get resource set: %w", notFound(result.Error))
+	}
+
+	return resourceSet.toDomain(), nil
+}
+
+// List retrieves a paginated list of resource sets
+func (r *ResourceSetRepository) List(ctx context.Context, limit, offset int) ([]*domain.ResourceSet, error) {
+	var resourceSets []ResourceSet
+	result := r.db.WithContext(ctx).Where("deleted_at IS NULL").Order("name").Limit(limit).Offset(offset).Find(&resourceSets)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list resource sets: %w", result.Error)
+	}
+
+	domainResourceSets := make([]*domain.ResourceSet, len(resourceSets))
+	for i, resourceSet := range resourceSets {
+		domainResourceSets[i] = resourceSet.toDomain()
+	}
+
+	return domainResourceSets, nil
+}
+
+// Update updates a resource set in the database
+func (r *ResourceSetRepository) Update(ctx context.Context, resourceSet *domain.ResourceSet) error {
+	resourceSet.UpdatedAt = time.Now()
+
+	gormResourceSet := resourceSetFromDomain(resourceSet)
+	result := r.db.WithContext(ctx).Save(gormResourceSet)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update resource set: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("resource set not found")
+	}
+
+	return nil
+}
+
+// Delete performs a soft delete on a resource set and returns the deleted resource set
+func (r *ResourceSetRepository) Delete(ctx context.Context, id string) (*domain.ResourceSet, error) {
+	// First retrieve the resource set to return it after deletion
+	var resourceSet ResourceSet
+	getResult := r.db.WithContext(ctx).First(&resourceSet, "id = ? AND deleted_at IS NULL", id)
+	if getResult.Error != nil {
+		return nil, fmt.Errorf("resource set not found")
+	}
+
+	// Perform soft delete
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&ResourceSet{}).Where("id = ?", id).Update("deleted_at", now)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to soft delete resource set: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("resource set not found")
+	}
+
+	// Update the retrieved resource set with deletion time
+	resourceSet.DeletedAt = &now
+
+	return resourceSet.toDomain(), nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/database"
+	"github.com/arifsetyawan/validra/src/pkg/tenant"
+)
+
+// RevisionRepository implements domain.RevisionRepository using GORM with PostgreSQL
+type RevisionRepository struct {
+	db *database.PostgresDB
+}
+
+// NewRevisionRepository creates a new GORM repository for revisions
+func NewRevisionRepository(db *database.PostgresDB) domain.RevisionRepository {
+	return &RevisionRepository{
+		db: db,
+	}
+}
+
+// Revision is the GORM model for revisions
+type Revision struct {
+	ID          int64  `gorm:"primaryKey;autoIncrement"`
+	TenantID    string `gorm:"not null;index"`
+	Description string `gorm:"not null"`
+	Changes     []byte `gorm:"not null"` // JSON serialized changes
+	CreatedAt   time.Time
+}
+
+// toDomain converts a GORM model to a domain model
+func (r *Revision) toDomain() (*domain.Revision, error) {
+	var changes []domain.RevisionChange
+	if err := json.Unmarshal(r.Changes, &changes); err != nil {
+		return nil, fmt.Errorf("failed to decode changes of revision %d: %w", r.ID, err)
+	}
+
+	return &domain.Revision{
+		ID:          r.ID,
+		Description: r.Description,
+		Changes:     changes,
+		CreatedAt:   r.CreatedAt,
+	}, nil
+}
+
+// Create stores a revision, assigning it the next revision ID
+func (r *RevisionRepository) Create(ctx context.Context, revision *domain.Revision) error {
+	changes, err := json.Marshal(revision.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode revision changes: %w", err)
+	}
+	revision.CreatedAt = time.Now()
+
+	gormRevision := &Revision{
+		Description: revision.Description,
+		Changes:     changes,
+		CreatedAt:   revision.CreatedAt,
+	}
+	// Revision IDs must become visible in order, since watchers resume after the last ID they saw.
+	// The lock is held until the enclosing transaction ends, so a revision of the tenant is only
+	// allocated once every transaction holding an earlier one has committed or rolled back.
+	err = r.db.InTransaction(ctx, func(ctx context.Context) error {
+		db := r.db.WithContext(ctx)
+		if err := db.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "validra:revisions:"+tenant.FromContext(ctx)).Error; err != nil {
+			return fmt.Errorf("failed to lock revisions: %w", err)
+		}
+		if err := db.Create(gormRevision).Error; err != nil {
+			return fmt.Errorf("failed to create revision: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	revision.ID = gormRevision.ID
+
+	return nil
+}
+
+// GetByID retrieves a revision by ID
+func (r *RevisionRepository) GetByID(ctx context.Context, id int64) (*domain.Revision, error) {
+	var revision Revision
+	result := r.db.WithContext(ctx).First(&revision, "id = ?", id)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get revision: %w", result.Error)
+	}
+
+	return revision.toDomain()
+}
+
+// List retrieves a paginated list of revisions, latest first
+func (r *RevisionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Revision, error) {
+	var revisions []Revision
+	result := r.db.WithContext(ctx).Order("id DESC").Limit(limit).Offset(offset).Find(&revisions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", result.Error)
+	}
+
+	return revisionsToDomain(revisions)
+}
+
+// ListAfter retrieves at most limit revisions following a revision, oldest first
+func (r *RevisionRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]*domain.Revision, error) {
+	var revisions []Revision
+	result := r.db.WithContext(ctx).Where("id > ?", afterID).Order("id").Limit(limit).Find(&revisions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", result.Error)
+	}
+
+	return revisionsToDomain(revisions)
+}
+
+// revisionsToDomain converts GORM models to domain models
+func revisionsToDomain(revisions []Revision) ([]*domain.Revision, error) {
+	domainRevisions := make([]*domain.Revision, len(revisions))
+	for i := range revisions {
+		revision, err := revisions[i].toDomain()
+		if err != nil {
+			return nil, err
+		}
+		domainRevisions[i] = revision
+	}
+
+	return domainRevisions, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/arifsetyawan/validra/src/internal/domain"
 	"github.com/arifsetyawan/validra/src/pkg/database"
+	"github.com/arifsetyawan/validra/src/pkg/tenant"
 	"github.com/google/uuid"
 )
 
@@ -25,6 +26,7 @@ func NewRoleRepository(db *database.PostgresDB) domain.RoleRepository {
 // Role is the GORM model for roles
 type Role struct {
 	ID          string `gorm:"primaryKey"`
+	TenantID    string `gorm:"not null;index"`
 	Name        string `gorm:"not null"`
 	Description string
 	CreatedAt   time.Time
@@ -69,9 +71,9 @@ func (r *RoleRepository) Create(ctx context.Context, role *domain.Role) error {
 	role.UpdatedAt = now
 
 	gormRole := roleFromDomain(role)
-	result := r.db.DB.WithContext(ctx).Create(gormRole)
+	result := r.db.WithContext(ctx).Create(gormRole)
 	if result.Error != nil {
-		return fmt.Errorf("failed to create role: %w", result.Error)
+		return fmt.Errorf("failed to create role: %w", duplicateKey(result.Error))
 	}
 
 	return nil
@@ -80,9 +82,9 @@ func (r *RoleRepository) Create(ctx context.Context, role *domain.Role) error {
 // GetByID retrieves a role by ID
 func (r *RoleRepository) GetByID(ctx context.Context, id string) (*domain.Role, error) {
 	var role Role
-	result := r.db.DB.WithContext(ctx).First(&role, "id = ?", id)
+	result := r.db.WithContext(ctx).First(&role, "id = ?", id)
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to get role: %w", result.Error)
+		return nil, fmt.Errorf("failed to get role: %w", notFound(result.Error))
 	}
 
 	return role.toDomain(), nil
@@ -91,7 +93,7 @@ func (r *RoleRepository) GetByID(ctx context.Context, id string) (*domain.Role,
 // List retrieves a paginated list of roles
 func (r *RoleRepository) List(ctx context.Context, limit, offset int) ([]*domain.Role, error) {
 	var roles []Role
-	result := r.db.DB.WithContext(ctx).Limit(limit).Offset(offset).Find(&roles)
+	result := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&roles)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to list roles: %w", result.Error)
 	}
@@ -110,7 +112,7 @@ func (r *RoleRepository) Update(ctx context.Context, role *domain.Role) error {
 	role.UpdatedAt = time.Now()
 
 	gormRole := roleFromDomain(role)
-	result := r.db.DB.WithContext(ctx).Save(gormRole)
+	result := r.db.WithContext(ctx).Save(gormRole)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update role: %w", result.Error)
 	}
@@ -126,14 +128,14 @@ func (r *RoleRepository) Update(ctx context.Context, role *domain.Role) error {
 func (r *RoleRepository) Delete(ctx context.Context, id string) (*domain.Role, error) {
 	// First retrieve the role to return it after deletion
 	var role Role
-	getResult := r.db.DB.WithContext(ctx).First(&role, "id = ?", id)
+	getResult := r.db.WithContext(ctx).First(&role, "id = ?", id)
 	if getResult.Error != nil {
 		return nil, fmt.Errorf("failed to get role: %w", getResult.Error)
 	}
 
 	// Perform soft delete
 	now := time.Now()
-	result := r.db.DB.WithContext(ctx).Model(&Role{}).Where("id = ?", id).Update("deleted_at", now)
+	result := r.db.WithContext(ctx).Model(&Role{}).Where("id = ?", id).Update("deleted_at", now)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to soft delete role: %w", result.Error)
 	}
@@ -147,3 +149,97 @@ func (r *RoleRepository) Delete(ctx context.Context, id string) (*domain.Role, e
 
 	return role.toDomain(), nil
 }
+
+// RoleParent is the GORM model for role inheritance links
+type RoleParent struct {
+	RoleID    string `gorm:"primaryKey"`
+	ParentID  string `gorm:"primaryKey;index"`
+	TenantID  string `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+// AddParent makes a role inherit the grants of a parent role
+func (r *RoleRepository) AddParent(ctx context.Context, roleParent *domain.RoleParent) error {
+	roleParent.CreatedAt = time.Now()
+
+	gormRoleParent := &RoleParent{
+		RoleID:    roleParent.RoleID,
+		ParentID:  roleParent.ParentID,
+		CreatedAt: roleParent.CreatedAt,
+	}
+	result := r.db.WithContext(ctx).Create(gormRoleParent)
+	if result.Error != nil {
+		return fmt.Errorf("failed to add parent role: %w", result.Error)
+	}
+
+	return nil
+}
+
+// RemoveParent removes an inheritance link between a role and its parent
+func (r *RoleRepository) RemoveParent(ctx context.Context, roleID, parentID string) (*domain.RoleParent, error) {
+	var roleParent RoleParent
+	getResult := r.db.WithContext(ctx).First(&roleParent, "role_id = ? AND parent_id = ?", roleID, parentID)
+	if getResult.Error != nil {
+		return nil, fmt.Errorf("parent role not found")
+	}
+
+	result := r.db.WithContext(ctx).Delete(&RoleParent{}, "role_id = ? AND parent_id = ?", roleID, parentID)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to remove parent role: %w", result.Error)
+	}
+
+	return &domain.RoleParent{
+		RoleID:    roleParent.RoleID,
+		ParentID:  roleParent.ParentID,
+		CreatedAt: roleParent.CreatedAt,
+	}, nil
+}
+
+// ListParents retrieves the active roles a role directly inherits from
+func (r *RoleRepository) ListParents(ctx context.Context, roleID string) ([]*domain.Role, error) {
+	var roles []Role
+	result := r.db.WithContext(ctx).
+		Joins("JOIN role_parents ON role_parents.parent_id = roles.id").
+		Where("role_parents.role_id = ? AND roles.deleted_at IS NULL", roleID).
+		Order("roles.name").
+		Find(&roles)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list parent roles: %w", result.Error)
+	}
+
+	domainRoles := make([]*domain.Role, len(roles))
+	for i, role := range roles {
+		domainRoles[i] = role.toDomain()
+	}
+
+	return domainRoles, nil
+}
+
+// ListChildren retrieves the active roles directly inheriting from a role
+func (r *RoleRepository) ListChildren(ctx context.Context, roleID string) ([]*domain.Role, error) {
+	var roles []Role
+	result := r.db.WithContext(ctx).
+		Joins("JOIN role_parents ON role_parents.role_id = roles.id").
+		Where("role_parents.parent_id = ? AND roles.deleted_at IS NULL", roleID).
+		Order("roles.name").
+		Find(&roles)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list child roles: %w", result.Error)
+	}
+
+	domainRoles := make([]*domain.Role, len(roles))
+	for i, role := range roles {
+		domainRoles[i] = role.toDomain()
+	}
+
+	return domainRoles, nil
+}
+
+// LockHierarchy takes a lock on the role hierarchy of the tenant, released when the transaction of ctx ends
+func (r *RoleRepository) LockHierarchy(ctx context.Context) error {
+	result := r.db.WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "validra:role_parents:"+tenant.FromContext(ctx))
+	if result.Error != nil {
+		return fmt.Errorf("failed to lock role hierarchy: %w", result.Error)
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ func NewUserRepository(db *database.PostgresDB) domain.UserRepository {
 // User is the GORM model for users
 type User struct {
 	ID         string `gorm:"primaryKey"`
+	TenantID   string `gorm:"not null;index"`
 	Username   string `gorm:"not null;unique"`
 	Attributes []byte
 	CreatedAt  time.Time
@@ -68,9 +69,9 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	user.UpdatedAt = now
 
 	gormUser := userFromDomain(user)
-	result := r.db.DB.WithContext(ctx).Create(gormUser)
+	result := r.db.WithContext(ctx).Create(gormUser)
 	if result.Error != nil {
-		return fmt.Errorf("failed to create user: %w", result.Error)
+		return fmt.Errorf("failed to create user: %w", duplicateKey(result.Error))
 	}
 
 	return nil
@@ -79,9 +80,9 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 // GetByID retrieves a user by ID
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	var user User
-	result := r.db.DB.WithContext(ctx).First(&user, "id = ?", id)
+	result := r.db.WithContext(ctx).First(&user, "id = ?", id)
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to get user: %w", result.Error)
+		return nil, fmt.Errorf("failed to get user: %w", notFound(result.Error))
 	}
 
 	return user.toDomain(), nil
@@ -90,9 +91,9 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User,
 // GetByUsername retrieves a user by username
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	var user User
-	result := r.db.DB.WithContext(ctx).First(&user, "username = ?", username)
+	result := r.db.WithContext(ctx).First(&user, "username = ?", username)
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to get user: %w", result.Error)
+		return nil, fmt.Errorf("failed to get user: %w", notFound(result.Error))
 	}
 
 	return user.toDomain(), nil
@@ -101,7 +102,7 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*d
 // List retrieves a paginated list of users
 func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*domain.User, error) {
 	var users []User
-	result := r.db.DB.WithContext(ctx).Limit(limit).Offset(offset).Find(&users)
+	result := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&users)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to list users: %w", result.Error)
 	}
@@ -119,7 +120,7 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	user.UpdatedAt = time.Now()
 
 	gormUser := userFromDomain(user)
-	result := r.db.DB.WithContext(ctx).Save(gormUser)
+	result := r.db.WithContext(ctx).Save(gormUser)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update user: %w", result.Error)
 	}
@@ -135,14 +136,14 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 func (r *UserRepository) Delete(ctx context.Context, id string) (*domain.User, error) {
 	// First retrieve the user to return it after deletion
 	var user User
-	getResult := r.db.DB.WithContext(ctx).First(&user, "id = ?", id)
+	getResult := r.db.WithContext(ctx).First(&user, "id = ?", id)
 	if getResult.Error != nil {
 		return nil, fmt.Errorf("failed to get user: %w", getResult.Error)
 	}
 
 	// Perform soft delete
 	now := time.Now()
-	result := r.db.DB.WithContext(ctx).Model(&User{}).Where("id = ?", id).Update("deleted_at", now)
+	result := r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Update("deleted_at", now)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to soft delete user: %w", result.Error)
 	}
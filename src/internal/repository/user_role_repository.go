@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/database"
+	"github.com/google/uuid"
+)
+
+// UserRoleRepository implements domain.UserRoleRepository using GORM with PostgreSQL
+type UserRoleRepository struct {
+	db *database.PostgresDB
+}
+
+// NewUserRoleRepository creates a new GORM repository for user-to-role assignments
+func NewUserRoleRepository(db *database.PostgresDB) domain.UserRoleRepository {
+	return &UserRoleRepository{
+		db: db,
+	}
+}
+
+// UserRole is the GORM model for user-to-role assignments
+type UserRole struct {
+	ID        string `gorm:"primaryKey"`
+	TenantID  string `gorm:"not null;index"`
+	UserID    string `gorm:"not null;uniqueIndex:idx_user_roles_user_role"`
+	RoleID    string `gorm:"not null;uniqueIndex:idx_user_roles_user_role;index"`
+	CreatedAt time.Time
+}
+
+// toDomain converts a GORM model to a domain model
+func (ur *UserRole) toDomain() *domain.UserRole {
+	return &domain.UserRole{
+		ID:        ur.ID,
+		UserID:    ur.UserID,
+		RoleID:    ur.RoleID,
+		CreatedAt: ur.CreatedAt,
+	}
+}
+
+// Create assigns a role to a user
+func (r *UserRoleRepository) Create(ctx context.Context, userRole *domain.UserRole) error {
+	// Generate a new UUID if not provided
+	if userRole.ID == "" {
+		userRole.ID = uuid.New().String()
+	}
+	userRole.CreatedAt = time.Now()
+
+	gormUserRole := &UserRole{
+		ID:        userRole.ID,
+		UserID:    userRole.UserID,
+		RoleID:    userRole.RoleID,
+		CreatedAt: userRole.CreatedAt,
+	}
+	result := r.db.WithContext(ctx).Create(gormUserRole)
+	if result.Error != nil {
+		return fmt.Errorf("failed to assign role: %w", duplicateKey(result.Error))
+	}
+
+	return nil
+}
+
+// Get retrieves the assignment of a role to a user
+func (r *UserRoleRepository) Get(ctx context.Context, userID, roleID string) (*domain.UserRole, error) {
+	var userRole UserRole
+	result := r.db.WithContext(ctx).First(&userRole, "user_id = ? AND role_id = ?", userID, roleID)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get role assignment: %w", notFound(result.Error))
+	}
+
+	return userRole.toDomain(), nil
+}
+
+// Delete removes the assignment of a role to a user and returns the removed assignment
+func (r *UserRoleRepository) Delete(ctx context.Context, userID, roleID string) (*domain.UserRole, error) {
+	// First retrieve the assignment to return it after deletion
+	var userRole UserRole
+	getResult := r.db.WithContext(ctx).First(&userRole, "user_id = ? AND role_id = ?", userID, roleID)
+	if getResult.Error != nil {
+		return nil, fmt.Errorf("role assignment not found")
+	}
+
+	result := r.db.WithContext(ctx).Delete(&UserRole{}, "id = ?", userRole.ID)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to revoke role: %w", result.Error)
+	}
+
+	return userRole.toDomain(), nil
+}
+
+// ListRolesByUserID retrieves every active role assigned to a user
+func (r *UserRoleRepository) ListRolesByUserID(ctx context.Context, userID string) ([]*domain.Role, error) {
+	var roles []Role
+	result := r.db.WithContext(ctx).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ? AND roles.deleted_at IS NULL", userID).
+		Order("roles.name").
+		Find(&roles)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list roles for user: %w", result.Error)
+	}
+
+	domainRoles := make([]*domain.Role, len(roles))
+	for i, role := range roles {
+		domainRoles[i] = role.toDomain()
+	}
+
+	return domainRoles, nil
+}
+
+// ListUsersByRoleID retrieves every active user that has been assigned a role
+func (r *UserRoleRepository) ListUsersByRoleID(ctx context.Context, roleID string) ([]*domain.User, error) {
+	var users []User
+	result := r.db.WithContext(ctx).
+		Joins("JOIN user_roles ON user_roles.user_id = users.id").
+		Where("user_roles.role_id = ? AND users.deleted_at IS NULL", roleID).
+		Order("users.username").
+		Find(&users)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list users for role: %w", result.Error)
+	}
+
+	domainUsers := make([]*domain.User, len(users))
+	for i, user := range users {
+		domainUsers[i] = user.toDomain()
+	}
+
+	return domainUsers, nil
+}
+
+// List retrieves a paginated list of role assignments
+func (r *UserRoleRepository) List(ctx context.Context, limit, offset int) ([]*domain.UserRole, error) {
+	var userRoles []UserRole
+	result := r.db.WithContext(ctx).Order("created_at, id").Limit(limit).Offset(offset).Find(&userRoles)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list role assignments: %w", result.Error)
+	}
+
+	domainUserRoles := make([]*domain.UserRole, len(userRoles))
+	for i, userRole := range userRoles {
+		domainUserRoles[i] = userRole.toDomain()
+	}
+
+	return domainUserRoles, nil
+}
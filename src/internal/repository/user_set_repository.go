@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/database"
+	"github.com/google/uuid"
+)
+
+// UserSetRepository implements domain.UserSetRepository using GORM with PostgreSQL
+type UserSetRepository struct {
+	db *database.PostgresDB
+}
+
+// NewUserSetRepository creates a new GORM repository for user sets
+func NewUserSetRepository(db *database.PostgresDB) domain.UserSetRepository {
+	return &UserSetRepository{
+		db: db,
+	}
+}
+
+// UserSet is the GORM model for user sets
+type UserSet struct {
+	ID          string `gorm:"primaryKey"`
+	TenantID    string `gorm:"not null;index"`
+	Name        string `gorm:"not null"`
+	Description string
+	Conditions  []byte
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time `gorm:"index"`
+}
+
+// toDomain converts a GORM model to a domain model
+func (us *UserSet) toDomain() *domain.UserSet {
+	return &domain.UserSet{
+		ID:          us.ID,
+		Name:        us.Name,
+		Description: us.Description,
+		Conditions:  us.Conditions,
+		CreatedAt:   us.CreatedAt,
+		UpdatedAt:   us.UpdatedAt,
+		DeletedAt:   us.DeletedAt,
+	}
+}
+
+// fromDomain converts a domain model to a GORM model
+func userSetFromDomain(us *domain.UserSet) *UserSet {
+	return &UserSet{
+		ID:          us.ID,
+		Name:        us.Name,
+		Description: us.Description,
+		Conditions:  us.Conditions,
+		CreatedAt:   us.CreatedAt,
+		UpdatedAt:   us.UpdatedAt,
+		DeletedAt:   us.DeletedAt,
+	}
+}
+
+// Create inserts a new user set into the database
+func (r *UserSetRepository) Create(ctx context.Context, userSet *domain.UserSet) error {
+	// Generate a new UUID if not provided
+	if userSet.ID == "" {
+		userSet.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	userSet.CreatedAt = now
+	userSet.UpdatedAt = now
+
+	gormUserSet := userSetFromDomain(userSet)
+	result := r.db.WithContext(ctx).Create(gormUserSet)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create user set: %w", duplicateKey(result.Error))
+	}
+
+	return nil
+}
+
+// GetByID retrieves a user set by ID
+func (r *UserSetRepository) GetByID(ctx context.Context, id string) (*domain.UserSet, error) {
+	var userSet UserSet
+	result := r.db.WithContext(ctx).First(&userSet, "id = ? AND deleted_at IS NULL", id)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get user set: %w", notFound(result.Error))
+	}
+
+	return userSet.toDomain(), nil
+}
+
+// GetByIDIncludingDeleted retrieves a user set by ID, whether it is deleted or not
+func (r *UserSetRepository) GetByIDIncludingDeleted(ctx context.Context, id string) (*domain.UserSet, error) {
+	var userSet UserSet
+	result := r.db.WithContext(ctx).First(&userSet, "id = ?", id)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get user set: %w", notFound(result.Error))
+	}
+
+	return userSet.toDomain(), nil
+}
+
+// List retrieves a paginated list of user sets
+func (r *UserSetRepository) List(ctx context.Context, limit, offset int) ([]*domain.UserSet, error) {
+	var userSets []UserSet
+	result := r.db.WithContext(ctx).Where("deleted_at IS NULL").Order("name").Limit(limit).Offset(offset).Find(&userSets)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list user sets: %w", result.Error)
+	}
+
+	domainUserSets := make([]*domain.UserSet, len(userSets))
+	for i, userSet := range userSets {
+		domainUserSets[i] = userSet.toDomain()
+	}
+
+	return domainUserSets, nil
+}
+
+// Update updates a user set in the database
+func (r *UserSetRepository) Update(ctx context.Context, userSet *domain.UserSet) error {
+	userSet.UpdatedAt = time.Now()
+
+	gormUserSet := userSetFromDomain(userSet)
+	result := r.db.WithContext(ctx).Save(gormUserSet)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update user set: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user set not found")
+	}
+
+	return nil
+}
+
+// Delete performs a soft delete on a user set and returns the deleted user set
+func (r *UserSetRepository) Delete(ctx context.Context, id string) (*domain.UserSet, error) {
+	// First retrieve the user set to return it after deletion
+	var userSet UserSet
+	getResult := r.db.WithContext(ctx).First(&userSet, "id = ? AND deleted_at IS NULL", id)
+	if getResult.Error != nil {
+		return nil, fmt.Errorf("user set not found")
+	}
+
+	// Perform soft delete
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&UserSet{}).Where("id = ?", id).Update("deleted_at", now)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to soft delete user set: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("user set not found")
+	}
+
+	// Update the retrieved user set with deletion time
+	userSet.DeletedAt = &now
+
+	return userSet.toDomain(), nil
+}
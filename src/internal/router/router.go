@@ -10,18 +10,30 @@ import (
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
-// Register registers all routes and handlers to the echo instance
-func Register(e *echo.Echo, resourceService *service.ResourceService, userService *service.UserService, roleService *service.RoleService, actionService *service.ActionService) {
-	// Create permission service
-	permissionService := service.NewPermissionService(
-		userService.UserRepository(),
-		actionService.ActionRepository(),
-		resourceService.ResourceRepository(),
-		roleService.RoleRepository(),
-	)
+// Services groups the services exposed through the HTTP API
+type Services struct {
+	Resource     *service.ResourceService
+	User         *service.UserService
+	Role         *service.RoleService
+	Action       *service.ActionService
+	UserRole     *service.UserRoleService
+	UserSet      *service.UserSetService
+	ResourceSet  *service.ResourceSetService
+	Relationship *service.RelationshipService
+	Schema       *service.SchemaService
+	Permission   *service.PermissionService
+	Policy       *service.PolicyService
+	Bundle       *service.BundleService
+	Revision     *service.RevisionService
+	Simulation   *service.SimulationService
+	// Cache is nil when permission decisions are not cached
+	Cache *service.DecisionCache
+}
 
+// Register registers all routes and handlers to the echo instance
+func Register(e *echo.Echo, services *Services) {
 	// API routes
-	registerAPIRoutes(e, resourceService, userService, roleService, actionService, permissionService)
+	registerAPIRoutes(e, services)
 
 	// Health check endpoint
 	e.GET("/health", func(c echo.Context) error {
@@ -36,20 +48,40 @@ func Register(e *echo.Echo, resourceService *service.ResourceService, userServic
 }
 
 // registerAPIRoutes sets up all API-related routes
-func registerAPIRoutes(e *echo.Echo, resourceService *service.ResourceService, userService *service.UserService, roleService *service.RoleService, actionService *service.ActionService, permissionService *service.PermissionService) {
+func registerAPIRoutes(e *echo.Echo, services *Services) {
 	// Initialize handlers
-	resourceHandler := handler.NewResourceHandler(resourceService)
-	userHandler := handler.NewUserHandler(userService)
-	roleHandler := handler.NewRoleHandler(roleService)
-	actionHandler := handler.NewActionHandler(actionService)
-	permissionHandler := handler.NewPermissionHandler(permissionService)
+	resourceHandler := handler.NewResourceHandler(services.Resource)
+	userHandler := handler.NewUserHandler(services.User)
+	roleHandler := handler.NewRoleHandler(services.Role)
+	actionHandler := handler.NewActionHandler(services.Action)
+	userRoleHandler := handler.NewUserRoleHandler(services.UserRole)
+	userSetHandler := handler.NewUserSetHandler(services.UserSet)
+	resourceSetHandler := handler.NewResourceSetHandler(services.ResourceSet)
+	relationshipHandler := handler.NewRelationshipHandler(services.Relationship)
+	schemaHandler := handler.NewSchemaHandler(services.Schema)
+	permissionHandler := handler.NewPermissionHandler(services.Permission)
+	policyHandler := handler.NewPolicyHandler(services.Policy)
+	bundleHandler := handler.NewBundleHandler(services.Bundle)
+	revisionHandler := handler.NewRevisionHandler(services.Revision, services.Bundle)
+	simulationHandler := handler.NewSimulationHandler(services.Simulation)
+	decisionCacheHandler := handler.NewDecisionCacheHandler(services.Cache)
 
 	// Register routes for each handler
 	resourceHandler.Register(e)
 	userHandler.Register(e)
 	roleHandler.Register(e)
 	actionHandler.Register(e)
+	userRoleHandler.Register(e)
+	userSetHandler.Register(e)
+	resourceSetHandler.Register(e)
+	relationshipHandler.Register(e)
+	schemaHandler.Register(e)
 	permissionHandler.Register(e)
+	policyHandler.Register(e)
+	bundleHandler.Register(e)
+	revisionHandler.Register(e)
+	simulationHandler.Register(e)
+	decisionCacheHandler.Register(e)
 }
 
 // registerSwaggerRoutes sets up Swagger documentation routes
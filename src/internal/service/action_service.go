@@ -11,13 +11,15 @@ import (
 type ActionService struct {
 	actionRepo   domain.ActionRepository
 	resourceRepo domain.ResourceRepository
+	revisions    *RevisionService
 }
 
 // NewActionService creates a new ActionService
-func NewActionService(actionRepo domain.ActionRepository, resourceRepo domain.ResourceRepository) *ActionService {
+func NewActionService(actionRepo domain.ActionRepository, resourceRepo domain.ResourceRepository, revisions *RevisionService) *ActionService {
 	return &ActionService{
 		actionRepo:   actionRepo,
 		resourceRepo: resourceRepo,
+		revisions:    revisions,
 	}
 }
 
@@ -37,7 +39,12 @@ func (s *ActionService) CreateAction(ctx context.Context, action *domain.Action)
 		return fmt.Errorf("invalid resource ID: %w", err)
 	}
 
-	return s.actionRepo.Create(ctx, action)
+	return s.revisions.Commit(ctx, "create action", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		if err := s.actionRepo.Create(ctx, action); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityAction, action.ID, nil, bundleAction(action)), nil
+	})
 }
 
 // GetActionByID retrieves an action by ID
@@ -76,12 +83,30 @@ func (s *ActionService) UpdateAction(ctx context.Context, action *domain.Action)
 		return fmt.Errorf("invalid resource ID: %w", err)
 	}
 
-	return s.actionRepo.Update(ctx, action)
+	return s.revisions.Commit(ctx, "update action", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		before, _ := s.actionRepo.GetByID(ctx, action.ID)
+		if err := s.actionRepo.Update(ctx, action); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityAction, action.ID, entityOf(before, bundleAction), bundleAction(action)), nil
+	})
 }
 
 // DeleteAction deletes an action by ID
 func (s *ActionService) DeleteAction(ctx context.Context, id string) (*domain.Action, error) {
-	return s.actionRepo.Delete(ctx, id)
+	var action *domain.Action
+	err := s.revisions.Commit(ctx, "delete action", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		var err error
+		action, err = s.actionRepo.Delete(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityAction, action.ID, bundleAction(action), nil), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return action, nil
 }
 
 // ActionRepository returns the action repository
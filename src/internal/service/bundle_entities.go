@@ -0,0 +1,103 @@
+package service
+
+import (
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/bundle"
+)
+
+// entitySections maps entity types to the bundle sections holding them
+var entitySections = map[string]string{
+	domain.EntityResource:     bundle.SectionResources,
+	domain.EntityAction:       bundle.SectionActions,
+	domain.EntityRole:         bundle.SectionRoles,
+	domain.EntityRoleParent:   bundle.SectionRoleParents,
+	domain.EntityUser:         bundle.SectionUsers,
+	domain.EntityUserRole:     bundle.SectionUserRoles,
+	domain.EntityUserSet:      bundle.SectionUserSets,
+	domain.EntityResourceSet:  bundle.SectionResourceSets,
+	domain.EntityPermission:   bundle.SectionPermissions,
+	domain.EntityRelationship: bundle.SectionRelationships,
+	domain.EntitySchema:       bundle.SectionSchema,
+}
+
+// bundleResource converts a resource to its bundle form
+func bundleResource(r *domain.Resource) bundle.Resource {
+	return bundle.Resource{
+		ID:                 r.ID,
+		Name:               r.Name,
+		Description:        r.Description,
+		Attributes:         normalizeJSON(r.Attributes),
+		CombiningAlgorithm: r.CombiningAlgorithm,
+		DefaultEffect:      r.DefaultEffect,
+		ParentID:           r.ParentID,
+		BlockInheritance:   r.BlockInheritance,
+	}
+}
+
+// bundleAction converts an action to its bundle form
+func bundleAction(a *domain.Action) bundle.Action {
+	return bundle.Action{
+		ID:          a.ID,
+		ResourceID:  a.ResourceID,
+		Name:        a.Name,
+		Description: a.Description,
+		Attributes:  normalizeJSON(a.Attributes),
+	}
+}
+
+// bundleRole converts a role to its bundle form
+func bundleRole(r *domain.Role) bundle.Role {
+	return bundle.Role{ID: r.ID, Name: r.Name, Description: r.Description}
+}
+
+// bundleRoleParent converts a role parent link to its bundle form
+func bundleRoleParent(rp *domain.RoleParent) bundle.RoleParent {
+	return bundle.RoleParent{RoleID: rp.RoleID, ParentID: rp.ParentID}
+}
+
+// bundleUser converts a user to its bundle form
+func bundleUser(u *domain.User) bundle.User {
+	return bundle.User{ID: u.ID, Username: u.Username, Attributes: normalizeJSON(u.Attributes)}
+}
+
+// bundleUserRole converts a role assignment to its bundle form
+func bundleUserRole(ur *domain.UserRole) bundle.UserRole {
+	return bundle.UserRole{ID: ur.ID, UserID: ur.UserID, RoleID: ur.RoleID}
+}
+
+// bundleUserSet converts a user set to its bundle form
+func bundleUserSet(us *domain.UserSet) bundle.UserSet {
+	return bundle.UserSet{ID: us.ID, Name: us.Name, Description: us.Description, Conditions: normalizeJSON(us.Conditions)}
+}
+
+// bundleResourceSet converts a resource set to its bundle form
+func bundleResourceSet(rs *domain.ResourceSet) bundle.ResourceSet {
+	return bundle.ResourceSet{ID: rs.ID, Name: rs.Name, Description: rs.Description, Conditions: normalizeJSON(rs.Conditions)}
+}
+
+// bundlePermission converts a permission to its bundle form
+func bundlePermission(p *domain.Permission) bundle.Permission {
+	return bundle.Permission{
+		ID:            p.ID,
+		RoleID:        p.RoleID,
+		UserID:        p.UserID,
+		UserSetID:     p.UserSetID,
+		ResourceID:    p.ResourceID,
+		ResourceSetID: p.ResourceSetID,
+		ActionID:      p.ActionID,
+		ActionName:    p.ActionName,
+		Effect:        p.Effect,
+		Priority:      p.Priority,
+		Conditions:    normalizeJSON(p.Conditions),
+	}
+}
+
+// bundleRelationship converts a relationship tuple to its bundle form
+func bundleRelationship(t *domain.RelationTuple) bundle.Relationship {
+	return bundle.Relationship{ID: t.ID, Tuple: t.String()}
+}
+
+// bundleSchema converts a relation schema version to its bundle form
+func bundleSchema(s *domain.RelationSchema) bundle.Schema {
+	return bundle.Schema{ID: s.ID, Version: s.Version, Definition: normalizeJSON(s.Definition)}
+}
@@ -0,0 +1,1105 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/bundle"
+	"github.com/arifsetyawan/validra/src/pkg/schema"
+	"github.com/arifsetyawan/validra/src/pkg/tenant"
+)
+
+// bundlePageSize is the page size used to read the entities of an exported model
+const bundlePageSize = 100
+
+// BundleService exports the model of a tenant as a bundle and restores bundles
+type BundleService struct {
+	transactor      domain.Transactor
+	resourceRepo    domain.ResourceRepository
+	actionRepo      domain.ActionRepository
+	roleRepo        domain.RoleRepository
+	userRepo        domain.UserRepository
+	userRoleRepo    domain.UserRoleRepository
+	userSetRepo     domain.UserSetRepository
+	resourceSetRepo domain.ResourceSetRepository
+	permissionRepo  domain.PermissionRepository
+	tupleRepo       domain.RelationTupleRepository
+	schemaRepo      domain.RelationSchemaRepository
+	revisions       *RevisionService
+}
+
+// NewBundleService creates a new BundleService
+func NewBundleService(
+	transactor domain.Transactor,
+	resourceRepo domain.ResourceRepository,
+	actionRepo domain.ActionRepository,
+	roleRepo domain.RoleRepository,
+	userRepo domain.UserRepository,
+	userRoleRepo domain.UserRoleRepository,
+	userSetRepo domain.UserSetRepository,
+	resourceSetRepo domain.ResourceSetRepository,
+	permissionRepo domain.PermissionRepository,
+	tupleRepo domain.RelationTupleRepository,
+	schemaRepo domain.RelationSchemaRepository,
+	revisions *RevisionService,
+) *BundleService {
+	return &BundleService{
+		transactor:      transactor,
+		resourceRepo:    resourceRepo,
+		actionRepo:      actionRepo,
+		roleRepo:        roleRepo,
+		userRepo:        userRepo,
+		userRoleRepo:    userRoleRepo,
+		userSetRepo:     userSetRepo,
+		resourceSetRepo: resourceSetRepo,
+		permissionRepo:  permissionRepo,
+		tupleRepo:       tupleRepo,
+		schemaRepo:      schemaRepo,
+		revisions:       revisions,
+	}
+}
+
+// Export writes the model of the tenant as a bundle, one page of entities at a time.
+// Deleted entities, and the actions, links and permissions referring to them, are left out.
+func (s *BundleService) Export(ctx context.Context, w io.Writer) error {
+	bw := bundle.NewWriter(w, tenant.FromContext(ctx), time.Now().UTC())
+
+	resources := make(map[string]bool)
+	bw.Section(bundle.SectionResources)
+	if err := s.exportResources(ctx, bw, resources); err != nil {
+		return err
+	}
+
+	actions := make(map[string]bool)
+	bw.Section(bundle.SectionActions)
+	for offset := 0; ; offset += bundlePageSize {
+		page, err := s.actionRepo.List(ctx, bundlePageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, a := range page {
+			if a.DeletedAt != nil || !resources[a.ResourceID] {
+				continue
+			}
+			actions[a.ID] = true
+			bw.Add(bundleAction(a))
+		}
+		if len(page) < bundlePageSize {
+			break
+		}
+	}
+
+	roles := make(map[string]bool)
+	var roleIDs []string
+	bw.Section(bundle.SectionRoles)
+	for offset := 0; ; offset += bundlePageSize {
+		page, err := s.roleRepo.List(ctx, bundlePageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, r := range page {
+			if r.DeletedAt != nil {
+				continue
+			}
+			roles[r.ID] = true
+			roleIDs = append(roleIDs, r.ID)
+			bw.Add(bundleRole(r))
+		}
+		if len(page) < bundlePageSize {
+			break
+		}
+	}
+
+	bw.Section(bundle.SectionRoleParents)
+	for _, roleID := range roleIDs {
+		parents, err := s.roleRepo.ListParents(ctx, roleID)
+		if err != nil {
+			return err
+		}
+		for _, parent := range parents {
+			bw.Add(bundleRoleParent(&domain.RoleParent{RoleID: roleID, ParentID: parent.ID}))
+		}
+	}
+
+	users := make(map[string]bool)
+	bw.Section(bundle.SectionUsers)
+	for offset := 0; ; offset += bundlePageSize {
+		page, err := s.userRepo.List(ctx, bundlePageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, u := range page {
+			if u.DeletedAt != nil {
+				continue
+			}
+			users[u.ID] = true
+			bw.Add(bundleUser(u))
+		}
+		if len(page) < bundlePageSize {
+			break
+		}
+	}
+
+	bw.Section(bundle.SectionUserRoles)
+	for offset := 0; ; offset += bundlePageSize {
+		page, err := s.userRoleRepo.List(ctx, bundlePageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, ur := range page {
+			if users[ur.UserID] && roles[ur.RoleID] {
+				bw.Add(bundleUserRole(ur))
+			}
+		}
+		if len(page) < bundlePageSize {
+			break
+		}
+	}
+
+	userSets := make(map[string]bool)
+	bw.Section(bundle.SectionUserSets)
+	for offset := 0; ; offset += bundlePageSize {
+		page, err := s.userSetRepo.List(ctx, bundlePageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, us := range page {
+			if us.DeletedAt != nil {
+				continue
+			}
+			userSets[us.ID] = true
+			bw.Add(bundleUserSet(us))
+		}
+		if len(page) < bundlePageSize {
+			break
+		}
+	}
+
+	resourceSets := make(map[string]bool)
+	bw.Section(bundle.SectionResourceSets)
+	for offset := 0; ; offset += bundlePageSize {
+		page, err := s.resourceSetRepo.List(ctx, bundlePageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, rs := range page {
+			if rs.DeletedAt != nil {
+				continue
+			}
+			resourceSets[rs.ID] = true
+			bw.Add(bundleResourceSet(rs))
+		}
+		if len(page) < bundlePageSize {
+			break
+		}
+	}
+
+	// Permissions referring to an entity left out of the bundle could not be restored
+	exported := func(ids map[string]bool, id *string) bool {
+		return id == nil || ids[*id]
+	}
+	bw.Section(bundle.SectionPermissions)
+	for offset := 0; ; offset += bundlePageSize {
+		page, err := s.permissionRepo.List(ctx, bundlePageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, p := range page {
+			if p.DeletedAt != nil ||
+				(p.RoleID != "" && !roles[p.RoleID]) ||
+				!exported(users, p.UserID) ||
+				!exported(userSets, p.UserSetID) ||
+				!exported(resources, p.ResourceID) ||
+				!exported(resourceSets, p.ResourceSetID) ||
+				!exported(actions, p.ActionID) {
+				continue
+			}
+			bw.Add(bundlePermission(p))
+		}
+		if len(page) < bundlePageSize {
+			break
+		}
+	}
+
+	bw.Section(bundle.SectionRelationships)
+	for offset := 0; ; offset += bundlePageSize {
+		page, err := s.tupleRepo.List(ctx, domain.RelationTupleFilter{}, bundlePageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, t := range page {
+			bw.Add(bundleRelationship(t))
+		}
+		if len(page) < bundlePageSize {
+			break
+		}
+	}
+
+	latest, err := s.schemaRepo.GetLatest(ctx)
+	if err != nil {
+		return err
+	}
+	if latest != nil {
+		current := bundleSchema(latest)
+		bw.Schema(&current)
+	} else {
+		bw.Schema(nil)
+	}
+
+	return bw.Close()
+}
+
+// exportResources writes the resources of the tenant. A parent that is deleted is left out, so that
+// the bundle only refers to the resources it holds.
+func (s *BundleService) exportResources(ctx context.Context, bw *bundle.Writer, exported map[string]bool) error {
+	var resources []*domain.Resource
+	for offset := 0; ; offset += bundlePageSize {
+		page, err := s.resourceRepo.List(ctx, bundlePageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, r := range page {
+			if r.DeletedAt == nil {
+				exported[r.ID] = true
+				resources = append(resources, r)
+			}
+		}
+		if len(page) < bundlePageSize {
+			break
+		}
+	}
+
+	for _, r := range resources {
+		entity := bundleResource(r)
+		if entity.ParentID != nil && !exported[*entity.ParentID] {
+			entity.ParentID = nil
+		}
+		bw.Add(entity)
+	}
+	return nil
+}
+
+// bundleImport restores the entities of a bundle and counts what happened to them
+type bundleImport struct {
+	*BundleService
+	strategy string
+	counts   map[string]*domain.ImportCounts
+	changes  []domain.RevisionChange
+}
+
+// newBundleImport creates an import resolving conflicts with a strategy
+func (s *BundleService) newBundleImport(strategy string) *bundleImport {
+	im := &bundleImport{BundleService: s, strategy: strategy, counts: make(map[string]*domain.ImportCounts)}
+	for _, section := range []string{
+		bundle.SectionResources, bundle.SectionActions, bundle.SectionRoles, bundle.SectionRoleParents,
+		bundle.SectionUsers, bundle.SectionUserRoles, bundle.SectionUserSets, bundle.SectionResourceSets,
+		bundle.SectionPermissions, bundle.SectionRelationships, bundle.SectionSchema,
+	} {
+		im.counts[section] = &domain.ImportCounts{}
+	}
+	return im
+}
+
+// track notes the states of an entity before and after a change, given in bundle form
+func (im *bundleImport) track(entityType, id string, before, after interface{}) {
+	im.changes = append(im.changes, entityChanges(entityType, id, before, after)...)
+}
+
+// Import restores a bundle into the tenant of the context, keeping the IDs of its entities.
+// Entities that already exist are handled according to the conflict strategy and deleted ones are
+// restored in place; the import is atomic, so a failure leaves the tenant untouched. Role parents, role assignments and relationships have
+// nothing to overwrite, so existing ones are kept unless the strategy is fail.
+func (s *BundleService) Import(ctx context.Context, r io.Reader, strategy string) (*domain.ImportResult, error) {
+	if strategy == "" {
+		strategy = domain.ImportConflictFail
+	}
+	if strategy != domain.ImportConflictSkip && strategy != domain.ImportConflictOverwrite && strategy != domain.ImportConflictFail {
+		return nil, fmt.Errorf("invalid conflict strategy: must be skip, overwrite or fail")
+	}
+
+	b, err := bundle.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	if b.Schema != nil {
+		if _, err := schema.Parse(b.Schema.Definition); err != nil {
+			return nil, fmt.Errorf("invalid bundle: schema: %w", err)
+		}
+	}
+	tuples, err := bundleTuples(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+
+	im := s.newBundleImport(strategy)
+	err = s.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if err := im.restore(ctx, b, tuples); err != nil {
+			return err
+		}
+		_, err := s.revisions.Record(ctx, "import bundle", im.changes...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ImportResult{Summary: make(map[string]domain.ImportCounts, len(im.counts))}
+	for section, counts := range im.counts {
+		result.Summary[section] = *counts
+	}
+	return result, nil
+}
+
+// bundleTuples parses the relationships of a bundle
+func bundleTuples(b *bundle.Bundle) ([]*domain.RelationTuple, error) {
+	tuples := make([]*domain.RelationTuple, len(b.Relationships))
+	for i, relationship := range b.Relationships {
+		tuple, err := domain.ParseRelationTuple(relationship.Tuple)
+		if err != nil {
+			return nil, fmt.Errorf("relationship %s: %w", relationship.ID, err)
+		}
+		tuple.ID = relationship.ID
+		tuples[i] = tuple
+	}
+	return tuples, nil
+}
+
+// restore imports the entities of a bundle section by section, so that references are restored after
+// the entities they refer to
+func (im *bundleImport) restore(ctx context.Context, b *bundle.Bundle, tuples []*domain.RelationTuple) error {
+	steps := []func(ctx context.Context, b *bundle.Bundle) error{
+		im.importResources,
+		im.importActions,
+		im.importRoles,
+		im.importRoleParents,
+		im.importUsers,
+		im.importUserRoles,
+		im.importUserSets,
+		im.importResourceSets,
+		im.importPermissions,
+	}
+	for _, step := range steps {
+		if err := step(ctx, b); err != nil {
+			return err
+		}
+	}
+	if err := im.importRelationships(ctx, tuples); err != nil {
+		return err
+	}
+	return im.importSchema(ctx, b.Schema)
+}
+
+// conflict decides the fate of an entity of the bundle that already exists. It returns true when
+// the entity is to be overwritten and an error when the strategy is fail.
+func (im *bundleImport) conflict(section, id string) (bool, error) {
+	switch im.strategy {
+	case domain.ImportConflictOverwrite:
+		im.counts[section].Updated++
+		return true, nil
+	case domain.ImportConflictSkip:
+		im.counts[section].Skipped++
+		return false, nil
+	}
+	return false, fmt.Errorf("import conflict: %s %s already exists", section, id)
+}
+
+// takenID explains the failure to create an entity of the bundle that does not exist in the tenant.
+// IDs are unique across tenants, so a duplicate key means that another tenant holds the ID.
+func takenID(section, id string, err error) error {
+	if errors.Is(err, domain.ErrDuplicateKey) {
+		return fmt.Errorf("import conflict: %s %s belongs to another tenant", section, id)
+	}
+	return err
+}
+
+// keep handles an existing entity that has nothing to overwrite
+func (im *bundleImport) keep(section, id string) error {
+	if im.strategy == domain.ImportConflictFail {
+		return fmt.Errorf("import conflict: %s %s already exists", section, id)
+	}
+	im.counts[section].Skipped++
+	return nil
+}
+
+// importResources restores the resources of the bundle
+func (im *bundleImport) importResources(ctx context.Context, b *bundle.Bundle) error {
+	for _, r := range b.Resources {
+		resource := &domain.Resource{
+			ID:                 r.ID,
+			Name:               r.Name,
+			Description:        r.Description,
+			Attributes:         normalizeJSON(r.Attributes),
+			CombiningAlgorithm: r.CombiningAlgorithm,
+			DefaultEffect:      r.DefaultEffect,
+			ParentID:           r.ParentID,
+			BlockInheritance:   r.BlockInheritance,
+		}
+
+		existing, err := im.resourceRepo.GetByID(ctx, r.ID)
+		if errors.Is(err, domain.ErrNotFound) {
+			if err := im.resourceRepo.Create(ctx, resource); err != nil {
+				return takenID(bundle.SectionResources, resource.ID, err)
+			}
+			im.track(domain.EntityResource, resource.ID, nil, bundleResource(resource))
+			im.counts[bundle.SectionResources].Created++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.DeletedAt != nil {
+			// A deleted resource keeps its row, so it is restored in place
+			resource.CreatedAt = existing.CreatedAt
+			if err := im.resourceRepo.Update(ctx, resource); err != nil {
+				return err
+			}
+			im.track(domain.EntityResource, resource.ID, nil, bundleResource(resource))
+			im.counts[bundle.SectionResources].Created++
+			continue
+		}
+		overwrite, err := im.conflict(bundle.SectionResources, r.ID)
+		if err != nil {
+			return err
+		}
+		if overwrite {
+			resource.CreatedAt = existing.CreatedAt
+			if err := im.resourceRepo.Update(ctx, resource); err != nil {
+				return err
+			}
+			im.track(domain.EntityResource, resource.ID, bundleResource(existing), bundleResource(resource))
+		}
+	}
+	return nil
+}
+
+// importActions restores the actions of the bundle
+func (im *bundleImport) importActions(ctx context.Context, b *bundle.Bundle) error {
+	for _, a := range b.Actions {
+		action := &domain.Action{
+			ID:          a.ID,
+			ResourceID:  a.ResourceID,
+			Name:        a.Name,
+			Description: a.Description,
+			Attributes:  normalizeJSON(a.Attributes),
+		}
+
+		existing, err := im.actionRepo.GetByID(ctx, a.ID)
+		if errors.Is(err, domain.ErrNotFound) {
+			if err := im.actionRepo.Create(ctx, action); err != nil {
+				return takenID(bundle.SectionActions, action.ID, err)
+			}
+			im.track(domain.EntityAction, action.ID, nil, bundleAction(action))
+			im.counts[bundle.SectionActions].Created++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.DeletedAt != nil {
+			// A deleted action keeps its row, so it is restored in place
+			action.CreatedAt = existing.CreatedAt
+			if err := im.actionRepo.Update(ctx, action); err != nil {
+				return err
+			}
+			im.track(domain.EntityAction, action.ID, nil, bundleAction(action))
+			im.counts[bundle.SectionActions].Created++
+			continue
+		}
+		overwrite, err := im.conflict(bundle.SectionActions, a.ID)
+		if err != nil {
+			return err
+		}
+		if overwrite {
+			action.CreatedAt = existing.CreatedAt
+			if err := im.actionRepo.Update(ctx, action); err != nil {
+				return err
+			}
+			im.track(domain.EntityAction, action.ID, bundleAction(existing), bundleAction(action))
+		}
+	}
+	return nil
+}
+
+// importRoles restores the roles of the bundle
+func (im *bundleImport) importRoles(ctx context.Context, b *bundle.Bundle) error {
+	for _, r := range b.Roles {
+		role := &domain.Role{ID: r.ID, Name: r.Name, Description: r.Description}
+
+		existing, err := im.roleRepo.GetByID(ctx, r.ID)
+		if errors.Is(err, domain.ErrNotFound) {
+			if err := im.roleRepo.Create(ctx, role); err != nil {
+				return takenID(bundle.SectionRoles, role.ID, err)
+			}
+			im.track(domain.EntityRole, role.ID, nil, bundleRole(role))
+			im.counts[bundle.SectionRoles].Created++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.DeletedAt != nil {
+			// A deleted role keeps its row, so it is restored in place
+			role.CreatedAt = existing.CreatedAt
+			if err := im.roleRepo.Update(ctx, role); err != nil {
+				return err
+			}
+			im.track(domain.EntityRole, role.ID, nil, bundleRole(role))
+			im.counts[bundle.SectionRoles].Created++
+			continue
+		}
+		overwrite, err := im.conflict(bundle.SectionRoles, r.ID)
+		if err != nil {
+			return err
+		}
+		if overwrite {
+			role.CreatedAt = existing.CreatedAt
+			if err := im.roleRepo.Update(ctx, role); err != nil {
+				return err
+			}
+			im.track(domain.EntityRole, role.ID, bundleRole(existing), bundleRole(role))
+		}
+	}
+	return nil
+}
+
+// importRoleParents restores the role inheritance links of the bundle, rejecting links that would
+// make a role inherit from itself once merged with the existing ones
+func (im *bundleImport) importRoleParents(ctx context.Context, b *bundle.Bundle) error {
+	if err := im.roleRepo.LockHierarchy(ctx); err != nil {
+		return err
+	}
+	for _, rp := range b.RoleParents {
+		name := rp.RoleID + " > " + rp.ParentID
+
+		parents, err := im.roleRepo.ListParents(ctx, rp.RoleID)
+		if err != nil {
+			return err
+		}
+		linked := false
+		for _, parent := range parents {
+			linked = linked || parent.ID == rp.ParentID
+		}
+		if linked {
+			if err := im.keep(bundle.SectionRoleParents, name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		parent, err := im.roleRepo.GetByID(ctx, rp.ParentID)
+		if err != nil {
+			return err
+		}
+		ancestors, err := expandRoles(ctx, im.roleRepo, []*domain.Role{parent})
+		if err != nil {
+			return err
+		}
+		for _, ancestor := range ancestors {
+			if ancestor.ID == rp.RoleID {
+				return fmt.Errorf("import conflict: role parent %s would create a cycle", name)
+			}
+		}
+
+		roleParent := &domain.RoleParent{RoleID: rp.RoleID, ParentID: rp.ParentID}
+		if err := im.roleRepo.AddParent(ctx, roleParent); err != nil {
+			return err
+		}
+		im.track(domain.EntityRoleParent, bundle.RoleParentKey(rp.RoleID, rp.ParentID), nil, bundleRoleParent(roleParent))
+		im.counts[bundle.SectionRoleParents].Created++
+	}
+	return nil
+}
+
+// importUsers restores the users of the bundle. A user whose username is taken by another user
+// cannot be restored, so it is skipped or fails the import.
+func (im *bundleImport) importUsers(ctx context.Context, b *bundle.Bundle) error {
+	for _, u := range b.Users {
+		user := &domain.User{ID: u.ID, Username: u.Username, Attributes: normalizeJSON(u.Attributes)}
+
+		other, err := im.userRepo.GetByUsername(ctx, u.Username)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		if err == nil && other.ID != u.ID {
+			if im.strategy != domain.ImportConflictSkip {
+				return fmt.Errorf("import conflict: username %s belongs to user %s", u.Username, other.ID)
+			}
+			im.counts[bundle.SectionUsers].Skipped++
+			continue
+		}
+
+		existing, err := im.userRepo.GetByID(ctx, u.ID)
+		if errors.Is(err, domain.ErrNotFound) {
+			if err := im.userRepo.Create(ctx, user); err != nil {
+				return takenID(bundle.SectionUsers, user.ID, err)
+			}
+			im.track(domain.EntityUser, user.ID, nil, bundleUser(user))
+			im.counts[bundle.SectionUsers].Created++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.DeletedAt != nil {
+			// A deleted user keeps its row, so it is restored in place
+			user.CreatedAt = existing.CreatedAt
+			if err := im.userRepo.Update(ctx, user); err != nil {
+				return err
+			}
+			im.track(domain.EntityUser, user.ID, nil, bundleUser(user))
+			im.counts[bundle.SectionUsers].Created++
+			continue
+		}
+		overwrite, err := im.conflict(bundle.SectionUsers, u.ID)
+		if err != nil {
+			return err
+		}
+		if overwrite {
+			user.CreatedAt = existing.CreatedAt
+			if err := im.userRepo.Update(ctx, user); err != nil {
+				return err
+			}
+			im.track(domain.EntityUser, user.ID, bundleUser(existing), bundleUser(user))
+		}
+	}
+	return nil
+}
+
+// importUserRoles restores the role assignments of the bundle
+func (im *bundleImport) importUserRoles(ctx context.Context, b *bundle.Bundle) error {
+	for _, ur := range b.UserRoles {
+		_, err := im.userRoleRepo.Get(ctx, ur.UserID, ur.RoleID)
+		if err == nil {
+			if err := im.keep(bundle.SectionUserRoles, ur.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+
+		userRole := &domain.UserRole{ID: ur.ID, UserID: ur.UserID, RoleID: ur.RoleID}
+		if err := im.userRoleRepo.Create(ctx, userRole); err != nil {
+			return takenID(bundle.SectionUserRoles, userRole.ID, err)
+		}
+		im.track(domain.EntityUserRole, userRole.ID, nil, bundleUserRole(userRole))
+		im.counts[bundle.SectionUserRoles].Created++
+	}
+	return nil
+}
+
+// importUserSets restores the user sets of the bundle
+func (im *bundleImport) importUserSets(ctx context.Context, b *bundle.Bundle) error {
+	for _, us := range b.UserSets {
+		userSet := &domain.UserSet{ID: us.ID, Name: us.Name, Description: us.Description, Conditions: normalizeJSON(us.Conditions)}
+
+		existing, err := im.userSetRepo.GetByIDIncludingDeleted(ctx, us.ID)
+		if errors.Is(err, domain.ErrNotFound) {
+			if err := im.userSetRepo.Create(ctx, userSet); err != nil {
+				return takenID(bundle.SectionUserSets, userSet.ID, err)
+			}
+			im.track(domain.EntityUserSet, userSet.ID, nil, bundleUserSet(userSet))
+			im.counts[bundle.SectionUserSets].Created++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.DeletedAt != nil {
+			// A deleted user set keeps its row, so it is restored in place
+			userSet.CreatedAt = existing.CreatedAt
+			if err := im.userSetRepo.Update(ctx, userSet); err != nil {
+				return err
+			}
+			im.track(domain.EntityUserSet, userSet.ID, nil, bundleUserSet(userSet))
+			im.counts[bundle.SectionUserSets].Created++
+			continue
+		}
+		overwrite, err := im.conflict(bundle.SectionUserSets, us.ID)
+		if err != nil {
+			return err
+		}
+		if overwrite {
+			userSet.CreatedAt = existing.CreatedAt
+			if err := im.userSetRepo.Update(ctx, userSet); err != nil {
+				return err
+			}
+			im.track(domain.EntityUserSet, userSet.ID, bundleUserSet(existing), bundleUserSet(userSet))
+		}
+	}
+	return nil
+}
+
+// importResourceSets restores the resource sets of the bundle
+func (im *bundleImport) importResourceSets(ctx context.Context, b *bundle.Bundle) error {
+	for _, rs := range b.ResourceSets {
+		resourceSet := &domain.ResourceSet{ID: rs.ID, Name: rs.Name, Description: rs.Description, Conditions: normalizeJSON(rs.Conditions)}
+
+		existing, err := im.resourceSetRepo.GetByIDIncludingDeleted(ctx, rs.ID)
+		if errors.Is(err, domain.ErrNotFound) {
+			if err := im.resourceSetRepo.Create(ctx, resourceSet); err != nil {
+				return takenID(bundle.SectionResourceSets, resourceSet.ID, err)
+			}
+			im.track(domain.EntityResourceSet, resourceSet.ID, nil, bundleResourceSet(resourceSet))
+			im.counts[bundle.SectionResourceSets].Created++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.DeletedAt != nil {
+			// A deleted resource set keeps its row, so it is restored in place
+			resourceSet.CreatedAt = existing.CreatedAt
+			if err := im.resourceSetRepo.Update(ctx, resourceSet); err != nil {
+				return err
+			}
+			im.track(domain.EntityResourceSet, resourceSet.ID, nil, bundleResourceSet(resourceSet))
+			im.counts[bundle.SectionResourceSets].Created++
+			continue
+		}
+		overwrite, err := im.conflict(bundle.SectionResourceSets, rs.ID)
+		if err != nil {
+			return err
+		}
+		if overwrite {
+			resourceSet.CreatedAt = existing.CreatedAt
+			if err := im.resourceSetRepo.Update(ctx, resourceSet); err != nil {
+				return err
+			}
+			im.track(domain.EntityResourceSet, resourceSet.ID, bundleResourceSet(existing), bundleResourceSet(resourceSet))
+		}
+	}
+	return nil
+}
+
+// importPermissions restores the permissions of the bundle
+func (im *bundleImport) importPermissions(ctx context.Context, b *bundle.Bundle) error {
+	for _, p := range b.Permissions {
+		permission := &domain.Permission{
+			ID:            p.ID,
+			RoleID:        p.RoleID,
+			UserID:        p.UserID,
+			UserSetID:     p.UserSetID,
+			ResourceID:    p.ResourceID,
+			ResourceSetID: p.ResourceSetID,
+			ActionID:      p.ActionID,
+			ActionName:    p.ActionName,
+			Effect:        p.Effect,
+			Priority:      p.Priority,
+			Conditions:    normalizeJSON(p.Conditions),
+		}
+
+		existing, err := im.permissionRepo.GetByIDIncludingDeleted(ctx, p.ID)
+		if errors.Is(err, domain.ErrNotFound) {
+			if err := im.permissionRepo.Create(ctx, permission); err != nil {
+				return takenID(bundle.SectionPermissions, permission.ID, err)
+			}
+			im.track(domain.EntityPermission, permission.ID, nil, bundlePermission(permission))
+			im.counts[bundle.SectionPermissions].Created++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.DeletedAt != nil {
+			// A deleted permission keeps its row, so it is restored in place
+			permission.CreatedAt = existing.CreatedAt
+			if err := im.permissionRepo.Update(ctx, permission); err != nil {
+				return err
+			}
+			im.track(domain.EntityPermission, permission.ID, nil, bundlePermission(permission))
+			im.counts[bundle.SectionPermissions].Created++
+			continue
+		}
+		overwrite, err := im.conflict(bundle.SectionPermissions, p.ID)
+		if err != nil {
+			return err
+		}
+		if overwrite {
+			permission.CreatedAt = existing.CreatedAt
+			if err := im.permissionRepo.Update(ctx, permission); err != nil {
+				return err
+			}
+			im.track(domain.EntityPermission, permission.ID, bundlePermission(existing), bundlePermission(permission))
+		}
+	}
+	return nil
+}
+
+// importRelationships restores the relationship tuples of the bundle
+func (im *bundleImport) importRelationships(ctx context.Context, tuples []*domain.RelationTuple) error {
+	var writes []*domain.RelationTuple
+	for _, tuple := range tuples {
+		existing, err := findTuple(ctx, im.tupleRepo, tuple)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			if err := im.keep(bundle.SectionRelationships, tuple.String()); err != nil {
+				return err
+			}
+			continue
+		}
+		writes = append(writes, tuple)
+	}
+
+	if len(writes) == 0 {
+		return nil
+	}
+	if err := im.tupleRepo.Write(ctx, writes, nil); err != nil {
+		return err
+	}
+	for _, tuple := range writes {
+		// Writes skip tuples whose key is taken, which can only be by another tenant since the
+		// tuple was not found in this one
+		stored, err := findTuple(ctx, im.tupleRepo, tuple)
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			return fmt.Errorf("import conflict: %s %s belongs to another tenant", bundle.SectionRelationships, tuple.ID)
+		}
+		im.track(domain.EntityRelationship, tuple.String(), nil, bundleRelationship(tuple))
+	}
+	im.counts[bundle.SectionRelationships].Created += len(writes)
+	return nil
+}
+
+// importSchema makes the schema of the bundle the schema in use. Schema versions are immutable, so
+// overwriting a different schema writes the bundle's as a new version.
+func (im *bundleImport) importSchema(ctx context.Context, s *bundle.Schema) error {
+	if s == nil {
+		return nil
+	}
+
+	latest, err := im.schemaRepo.GetLatest(ctx)
+	if err != nil {
+		return err
+	}
+	relationSchema := &domain.RelationSchema{ID: s.ID, Definition: normalizeJSON(s.Definition)}
+	if latest != nil {
+		if equalJSON(latest.Definition, s.Definition) {
+			im.counts[bundle.SectionSchema].Skipped++
+			return nil
+		}
+		overwrite, err := im.conflict(bundle.SectionSchema, s.ID)
+		if err != nil || !overwrite {
+			return err
+		}
+		// The version written is new, and so is its ID
+		relationSchema.ID = ""
+	}
+
+	if err := im.schemaRepo.Create(ctx, relationSchema); err != nil {
+		return takenID(bundle.SectionSchema, relationSchema.ID, err)
+	}
+	im.track(domain.EntitySchema, domain.EntitySchema, entityOf(latest, bundleSchema), bundleSchema(relationSchema))
+	if latest == nil {
+		im.counts[bundle.SectionSchema].Created++
+	}
+	return nil
+}
+
+// revisionStates returns the first change made to every entity changed after a revision. The state
+// before that change is the state of the entity as of the revision.
+func (s *BundleService) revisionStates(ctx context.Context, revisionID int64) ([]domain.RevisionChange, error) {
+	changes, err := s.revisions.changesAfter(ctx, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	type entityKey struct{ entityType, id string }
+	seen := make(map[entityKey]bool)
+	var states []domain.RevisionChange
+	for _, change := range changes {
+		key := entityKey{change.EntityType, change.EntityID}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		states = append(states, change)
+	}
+	return states, nil
+}
+
+// ExportAt returns the model of the tenant as of a revision: the current model with every entity
+// changed since put back in the state it had at that revision
+func (s *BundleService) ExportAt(ctx context.Context, revisionID int64) (*bundle.Bundle, error) {
+	states, err := s.revisionStates(ctx, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := s.Export(ctx, &buf); err != nil {
+		return nil, err
+	}
+	b, err := bundle.Decode(&buf)
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range states {
+		if err := b.Set(entitySections[state.EntityType], state.EntityID, state.Before); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// Rollback restores the model of the tenant to its state as of a revision, atomically. Entities created
+// since are deleted and the others are put back as they were; the schema, whose versions are immutable,
+// is written again as a new version, and is kept when there was none. The rollback is itself recorded
+// as a new revision, which is returned, or nil when nothing changed since the revision.
+func (s *BundleService) Rollback(ctx context.Context, revisionID int64) (*domain.Revision, error) {
+	var revision *domain.Revision
+	err := s.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		states, err := s.revisionStates(ctx, revisionID)
+		if err != nil {
+			return err
+		}
+
+		im := s.newBundleImport(domain.ImportConflictOverwrite)
+		restored := &bundle.Bundle{}
+		var created []domain.RevisionChange
+		for _, state := range states {
+			if state.Before == nil {
+				created = append(created, state)
+				continue
+			}
+			if err := restored.Set(entitySections[state.EntityType], state.EntityID, state.Before); err != nil {
+				return err
+			}
+		}
+
+		// Entities are removed in the reverse order of their creation, so that dependents go first
+		for i := len(created) - 1; i >= 0; i-- {
+			if err := im.remove(ctx, created[i]); err != nil {
+				return err
+			}
+		}
+		tuples, err := bundleTuples(restored)
+		if err != nil {
+			return err
+		}
+		if err := im.restore(ctx, restored, tuples); err != nil {
+			return err
+		}
+
+		revision, err = s.revisions.Record(ctx, fmt.Sprintf("rollback to revision %d", revisionID), im.changes...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// remove deletes an entity given the change that created it. Entities that no longer exist are skipped.
+func (im *bundleImport) remove(ctx context.Context, change domain.RevisionChange) error {
+	switch change.EntityType {
+	case domain.EntityResource:
+		return softDelete(ctx, im, change, im.resourceRepo.GetByID, im.resourceRepo.Delete,
+			func(r *domain.Resource) *time.Time { return r.DeletedAt }, bundleResource)
+	case domain.EntityAction:
+		return softDelete(ctx, im, change, im.actionRepo.GetByID, im.actionRepo.Delete,
+			func(a *domain.Action) *time.Time { return a.DeletedAt }, bundleAction)
+	case domain.EntityRole:
+		return softDelete(ctx, im, change, im.roleRepo.GetByID, im.roleRepo.Delete,
+			func(r *domain.Role) *time.Time { return r.DeletedAt }, bundleRole)
+	case domain.EntityUser:
+		return softDelete(ctx, im, change, im.userRepo.GetByID, im.userRepo.Delete,
+			func(u *domain.User) *time.Time { return u.DeletedAt }, bundleUser)
+	case domain.EntityUserSet:
+		return softDelete(ctx, im, change, im.userSetRepo.GetByID, im.userSetRepo.Delete,
+			func(us *domain.UserSet) *time.Time { return us.DeletedAt }, bundleUserSet)
+	case domain.EntityResourceSet:
+		return softDelete(ctx, im, change, im.resourceSetRepo.GetByID, im.resourceSetRepo.Delete,
+			func(rs *domain.ResourceSet) *time.Time { return rs.DeletedAt }, bundleResourceSet)
+	case domain.EntityPermission:
+		return softDelete(ctx, im, change, im.permissionRepo.GetByID, im.permissionRepo.Delete,
+			func(p *domain.Permission) *time.Time { return p.DeletedAt }, bundlePermission)
+	case domain.EntityRoleParent:
+		var rp bundle.RoleParent
+		if err := json.Unmarshal(change.After, &rp); err != nil {
+			return err
+		}
+		parents, err := im.roleRepo.ListParents(ctx, rp.RoleID)
+		if err != nil {
+			return err
+		}
+		for _, parent := range parents {
+			if parent.ID != rp.ParentID {
+				continue
+			}
+			roleParent, err := im.roleRepo.RemoveParent(ctx, rp.RoleID, rp.ParentID)
+			if err != nil {
+				return err
+			}
+			im.track(change.EntityType, change.EntityID, bundleRoleParent(roleParent), nil)
+		}
+		return nil
+	case domain.EntityUserRole:
+		var ur bundle.UserRole
+		if err := json.Unmarshal(change.After, &ur); err != nil {
+			return err
+		}
+		if _, err := im.userRoleRepo.Get(ctx, ur.UserID, ur.RoleID); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		userRole, err := im.userRoleRepo.Delete(ctx, ur.UserID, ur.RoleID)
+		if err != nil {
+			return err
+		}
+		im.track(change.EntityType, userRole.ID, bundleUserRole(userRole), nil)
+		return nil
+	case domain.EntityRelationship:
+		tuple, err := domain.ParseRelationTuple(change.EntityID)
+		if err != nil {
+			return err
+		}
+		existing, err := findTuple(ctx, im.tupleRepo, tuple)
+		if err != nil || existing == nil {
+			return err
+		}
+		if err := im.tupleRepo.Write(ctx, nil, []*domain.RelationTuple{tuple}); err != nil {
+			return err
+		}
+		im.track(change.EntityType, change.EntityID, bundleRelationship(existing), nil)
+		return nil
+	}
+	// The schema cannot be removed once written
+	return nil
+}
+
+// softDelete deletes an entity unless it is missing or already deleted
+func softDelete[E, B any](
+	ctx context.Context,
+	im *bundleImport,
+	change domain.RevisionChange,
+	get, del func(ctx context.Context, id string) (*E, error),
+	deletedAt func(*E) *time.Time,
+	convert func(*E) B,
+) error {
+	existing, err := get(ctx, change.EntityID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil || deletedAt(existing) != nil {
+		return err
+	}
+	if _, err := del(ctx, change.EntityID); err != nil {
+		return err
+	}
+	im.track(change.EntityType, change.EntityID, convert(existing), nil)
+	return nil
+}
@@ -0,0 +1,362 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/condition"
+	"github.com/arifsetyawan/validra/src/pkg/tenant"
+)
+
+// Kinds of the entities a cached decision depends on. A decision depends on the user and the resource
+// it names, whether they exist or not, on the entities the check resolved and, when no permission
+// decided it, on the relationship graph.
+const (
+	dependencyUsername      = "username"
+	dependencyUser          = "user"
+	dependencyRole          = "role"
+	dependencyUserSet       = "user_set"
+	dependencyResourceName  = "resource_name"
+	dependencyResource      = "resource"
+	dependencyRelationships = "relationships"
+	// dependencyTenant is a dependency of every decision of a tenant
+	dependencyTenant = "tenant"
+)
+
+// DecisionCache caches the decisions of permission checks for a limited time, keeping the most
+// recently used ones up to a maximum. Decisions are dropped as soon as a revision changes an entity
+// they depend on, so a cached decision is the one the check would make.
+type DecisionCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// recent orders the entries from the most to the least recently used
+	recent *list.List
+	// dependents indexes the keys of the entries by dependency
+	dependents map[string]map[string]bool
+	// generation changes with every invalidation, so that a decision made from entities that changed
+	// while it was being made is not stored
+	generation uint64
+	// revision is the ID of the latest revision the cache was told about. It is not told about the
+	// revisions committed by other instances, so checks asking for them are decided afresh.
+	revision int64
+	stats    domain.DecisionCacheStats
+}
+
+// cachedDecision is a decision of the cache
+type cachedDecision struct {
+	key          string
+	granted      bool
+	context      map[string]interface{}
+	dependencies []string
+	expiresAt    time.Time
+	// revision is the ID of the latest revision the decision reflects
+	revision int64
+}
+
+// consistencyKey is the context key of the consistency required of the checks of a context
+type consistencyKey struct{}
+
+// WithConsistency returns a copy of ctx requiring the permission checks made with it to be decided
+// from data meeting consistency
+func WithConsistency(ctx context.Context, consistency domain.Consistency) context.Context {
+	return context.WithValue(ctx, consistencyKey{}, consistency)
+}
+
+// consistencyFrom returns the consistency required of the checks made with ctx
+func consistencyFrom(ctx context.Context) domain.Consistency {
+	consistency, _ := ctx.Value(consistencyKey{}).(domain.Consistency)
+	return consistency
+}
+
+// NewDecisionCache creates a DecisionCache kept up to date with the revisions of the model
+func NewDecisionCache(revisions *RevisionService, ttl time.Duration, maxEntries int) *DecisionCache {
+	c := &DecisionCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		recent:     list.New(),
+		dependents: make(map[string]map[string]bool),
+	}
+	revisions.Subscribe(c.invalidate)
+	return c
+}
+
+// Stats returns the use of the cache
+func (c *DecisionCache) Stats() domain.DecisionCacheStats {
+	if c == nil {
+		return domain.DecisionCacheStats{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Enabled = true
+	stats.Entries = len(c.entries)
+	stats.MaxEntries = c.maxEntries
+	stats.TTL = c.ttl
+	return stats
+}
+
+// Clear drops every cached decision
+func (c *DecisionCache) Clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.entries = make(map[string]*list.Element)
+	c.recent.Init()
+	c.dependents = make(map[string]map[string]bool)
+}
+
+// get returns the cached decision of a key, provided it reflects at least the revision minRevision
+func (c *DecisionCache) get(key string, minRevision int64) (*cachedDecision, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	decision := element.Value.(*cachedDecision)
+	if time.Now().After(decision.expiresAt) {
+		c.remove(element)
+		c.stats.Expirations++
+		c.stats.Misses++
+		return nil, false
+	}
+	if decision.revision < minRevision {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.recent.MoveToFront(element)
+	c.stats.Hits++
+	return decision, true
+}
+
+// begin returns the generation a decision made from now on is stored with
+func (c *DecisionCache) begin() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// put stores a decision unless the cache was invalidated since its generation began
+func (c *DecisionCache) put(generation uint64, decision *cachedDecision) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	if element, ok := c.entries[decision.key]; ok {
+		c.remove(element)
+	}
+
+	decision.expiresAt = time.Now().Add(c.ttl)
+	decision.revision = c.revision
+	c.entries[decision.key] = c.recent.PushFront(decision)
+	for _, dependency := range decision.dependencies {
+		if c.dependents[dependency] == nil {
+			c.dependents[dependency] = make(map[string]bool)
+		}
+		c.dependents[dependency][decision.key] = true
+	}
+
+	for len(c.entries) > c.maxEntries {
+		c.remove(c.recent.Back())
+		c.stats.Evictions++
+	}
+}
+
+// remove drops an entry
+func (c *DecisionCache) remove(element *list.Element) {
+	decision := c.recent.Remove(element).(*cachedDecision)
+	delete(c.entries, decision.key)
+	for _, dependency := range decision.dependencies {
+		delete(c.dependents[dependency], decision.key)
+		if len(c.dependents[dependency]) == 0 {
+			delete(c.dependents, dependency)
+		}
+	}
+}
+
+// invalidate drops the decisions depending on the entities changed by committed revisions
+func (c *DecisionCache) invalidate(ctx context.Context, revisions []*domain.Revision) {
+	tenantID := tenant.FromContext(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, revision := range revisions {
+		if revision.ID > c.revision {
+			c.revision = revision.ID
+		}
+		for _, change := range revision.Changes {
+			for _, dependency := range changeDependencies(change) {
+				for key := range c.dependents[dependencyKey(tenantID, dependency[0], dependency[1])] {
+					c.remove(c.entries[key])
+					c.stats.Invalidations++
+				}
+			}
+		}
+	}
+}
+
+// changedEntity holds the fields of a changed entity that decisions depend on
+type changedEntity struct {
+	Name       string  `json:"name"`
+	Username   string  `json:"username"`
+	ResourceID string  `json:"resource_id"`
+	RoleID     string  `json:"role_id"`
+	UserID     *string `json:"user_id"`
+	UserSetID  *string `json:"user_set_id"`
+}
+
+// changeDependencies returns the kind and ID of the dependencies of the decisions a change affects
+func changeDependencies(change domain.RevisionChange) [][2]string {
+	var dependencies [][2]string
+	add := func(kind, id string) {
+		if id != "" {
+			dependencies = append(dependencies, [2]string{kind, id})
+		}
+	}
+
+	for _, state := range []json.RawMessage{change.Before, change.After} {
+		var entity changedEntity
+		if len(state) > 0 {
+			if err := json.Unmarshal(state, &entity); err != nil {
+				return [][2]string{{dependencyTenant, "*"}}
+			}
+		}
+
+		switch change.EntityType {
+		case domain.EntityResource:
+			add(dependencyResource, change.EntityID)
+			add(dependencyResourceName, entity.Name)
+		case domain.EntityAction:
+			// Actions are looked up among the actions of the resource and of its ancestors
+			add(dependencyResource, entity.ResourceID)
+		case domain.EntityRole:
+			add(dependencyRole, change.EntityID)
+		case domain.EntityRoleParent:
+			add(dependencyRole, entity.RoleID)
+		case domain.EntityUser:
+			add(dependencyUser, change.EntityID)
+			add(dependencyUsername, entity.Username)
+		case domain.EntityUserRole:
+			if entity.UserID != nil {
+				add(dependencyUser, *entity.UserID)
+			}
+		case domain.EntityPermission:
+			// A permission only matters to the checks of its subject
+			add(dependencyRole, entity.RoleID)
+			if entity.UserID != nil {
+				add(dependencyUser, *entity.UserID)
+			}
+			if entity.UserSetID != nil {
+				add(dependencyUserSet, *entity.UserSetID)
+			}
+		case domain.EntityRelationship, domain.EntitySchema:
+			add(dependencyRelationships, "*")
+		default:
+			// Set conditions can change the membership of any user or resource
+			add(dependencyTenant, "*")
+		}
+	}
+	return dependencies
+}
+
+// dependencyKey identifies an entity of a tenant a decision depends on
+func dependencyKey(tenantID, kind, id string) string {
+	return tenantID + "/" + kind + ":" + id
+}
+
+// decisionKey identifies the decision of a check in the tenant of ctx. It returns false when the
+// request context of the check cannot be serialized.
+func decisionKey(ctx context.Context, check domain.PermissionCheck) (string, bool) {
+	// Maps are serialized with sorted keys, so equal contexts hash the same
+	requestContext, err := json.Marshal(check.Context)
+	if err != nil {
+		return "", false
+	}
+	hash := sha256.Sum256(requestContext)
+
+	key, err := json.Marshal([]string{tenant.FromContext(ctx), check.User, check.Action, check.Resource, hex.EncodeToString(hash[:])})
+	if err != nil {
+		return "", false
+	}
+	return string(key), true
+}
+
+// newCachedDecision builds the cached decision of a check from the lookups it was decided with.
+// It returns nil when the decision depends on the time of the check.
+func newCachedDecision(ctx context.Context, key string, lookups *checkLookups, check domain.PermissionCheck, granted bool, decisionContext map[string]interface{}) *cachedDecision {
+	subject := lookups.subjects[check.User]
+	resource, action := lookups.target(check.Resource, check.Action)
+
+	if _, ok := requestTime(check.Context); !ok && subject != nil {
+		for _, p := range subject.permissions {
+			if conditions, err := condition.Parse(p.Conditions); err == nil && conditions != nil && conditions.Reads("env") {
+				return nil
+			}
+		}
+	}
+
+	tenantID := tenant.FromContext(ctx)
+	dependencies := []string{
+		dependencyKey(tenantID, dependencyTenant, "*"),
+		dependencyKey(tenantID, dependencyUsername, check.User),
+		dependencyKey(tenantID, dependencyResourceName, check.Resource),
+	}
+	if subject != nil {
+		dependencies = append(dependencies, dependencyKey(tenantID, dependencyUser, subject.user.ID))
+		for _, role := range subject.roles {
+			dependencies = append(dependencies, dependencyKey(tenantID, dependencyRole, role.ID))
+		}
+		for _, userSet := range subject.userSets {
+			dependencies = append(dependencies, dependencyKey(tenantID, dependencyUserSet, userSet.ID))
+		}
+	}
+	if resource != nil {
+		dependencies = append(dependencies, dependencyKey(tenantID, dependencyResource, resource.ID))
+		for _, ancestor := range lookups.ancestors[resource.ID] {
+			dependencies = append(dependencies, dependencyKey(tenantID, dependencyResource, ancestor.ID))
+		}
+	}
+	if _, decidedByPermission := decisionContext["permissionId"]; !decidedByPermission && subject != nil && action != nil {
+		dependencies = append(dependencies, dependencyKey(tenantID, dependencyRelationships, "*"))
+	}
+
+	return &cachedDecision{
+		key:          key,
+		granted:      granted,
+		context:      copyContext(decisionContext),
+		dependencies: dependencies,
+	}
+}
+
+// copyContext copies a decision context, so that callers cannot change a cached decision
+func copyContext(decisionContext map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(decisionContext))
+	for k, v := range decisionContext {
+		copied[k] = v
+	}
+	return copied
+}
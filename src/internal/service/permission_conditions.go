@@ -0,0 +1,71 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/condition"
+)
+
+// permissionAttributes builds the attributes permission conditions are evaluated against:
+//   - "user", "resource" and "action" hold the attributes of the entities together with their id and name
+//   - "context" holds the request-time context sent with the check (client IP, device, ...)
+//   - "env" describes the time of the check: "time", "date", "hour", "minute", "weekday" and "unix"
+//
+// The time of the check is taken from the "time" key of the request context when it holds an
+// RFC 3339 timestamp, and is the current server time otherwise.
+func permissionAttributes(user *domain.User, resource *domain.Resource, action *domain.Action, requestContext map[string]interface{}) map[string]interface{} {
+	attributes := resourceSetAttributes(resource, action)
+
+	userAttributes := condition.Attributes(user.Attributes)
+	userAttributes["id"] = user.ID
+	userAttributes["username"] = user.Username
+	attributes["user"] = userAttributes
+
+	if requestContext == nil {
+		requestContext = map[string]interface{}{}
+	}
+	attributes["context"] = requestContext
+
+	now, ok := requestTime(requestContext)
+	if !ok {
+		now = time.Now()
+	}
+	attributes["env"] = map[string]interface{}{
+		"time":    now.Format(time.RFC3339),
+		"date":    now.Format("2006-01-02"),
+		"hour":    now.Hour(),
+		"minute":  now.Minute(),
+		"weekday": strings.ToLower(now.Weekday().String()),
+		"unix":    now.Unix(),
+	}
+
+	return attributes
+}
+
+// requestTime returns the time of a check sent in the "time" key of its request context, if any
+func requestTime(requestContext map[string]interface{}) (time.Time, bool) {
+	value, ok := requestContext["time"].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// permissionConditionsHold reports whether the conditions of a permission are satisfied.
+// A permission without conditions always applies and one with broken conditions never does.
+func permissionConditionsHold(p *domain.Permission, attributes map[string]interface{}) bool {
+	conditions, err := condition.Parse(p.Conditions)
+	if err != nil {
+		return false
+	}
+	if conditions == nil {
+		return true
+	}
+	return conditions.Evaluate(attributes)
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// maxBatchChecks is the maximum number of checks accepted by a single batch
+const maxBatchChecks = 100
+
+// maxConcurrentChecks is the number of checks of a batch evaluated at the same time
+const maxConcurrentChecks = 8
+
+// checkSubject holds what the checks of a user need to know about it
+type checkSubject struct {
+	user *domain.User
+	// roles holds the roles assigned to the user first, then the roles they inherit from
+	roles       []*domain.Role
+	assigned    int
+	userSets    []*domain.UserSet
+	permissions []*domain.Permission
+}
+
+// checkLookups holds the users and resources looked up for one or more checks.
+// It is only read once built, so the checks of a batch can share it concurrently.
+type checkLookups struct {
+	// subjects is keyed by username and holds nil for users that do not exist
+	subjects map[string]*checkSubject
+	// resources is keyed by name and holds nil for resources that do not exist
+	resources map[string]*domain.Resource
+	// actions is keyed by resource ID
+	actions map[string][]*domain.Action
+	// ancestors holds the ancestors each resource inherits permissions from, keyed by resource ID
+	ancestors    map[string][]*domain.Resource
+	resourceSets []*domain.ResourceSet
+	userSets     []*domain.UserSet
+}
+
+// lookup resolves the users and resources involved in the checks, each once
+func (s *PermissionService) lookup(ctx context.Context, checks []domain.PermissionCheck) (*checkLookups, error) {
+	lookups := &checkLookups{
+		subjects:  make(map[string]*checkSubject),
+		resources: make(map[string]*domain.Resource),
+		actions:   make(map[string][]*domain.Action),
+		ancestors: make(map[string][]*domain.Resource),
+	}
+
+	userSets, err := listUserSets(ctx, s.userSetRepo)
+	if err != nil {
+		return nil, err
+	}
+	lookups.userSets = userSets
+
+	for _, check := range checks {
+		if _, ok := lookups.subjects[check.User]; ok {
+			continue
+		}
+		subject, err := s.lookupSubject(ctx, userSets, check.User)
+		if err != nil {
+			return nil, err
+		}
+		lookups.subjects[check.User] = subject
+	}
+
+	resourceSets, err := listResourceSets(ctx, s.resourceSetRepo)
+	if err != nil {
+		return nil, err
+	}
+	lookups.resourceSets = resourceSets
+
+	for _, check := range checks {
+		if _, ok := lookups.resources[check.Resource]; ok {
+			continue
+		}
+		resource, err := s.resourceRepo.GetByName(ctx, check.Resource)
+		if errors.Is(err, domain.ErrNotFound) {
+			lookups.resources[check.Resource] = nil
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		lookups.resources[check.Resource] = resource
+		if _, ok := lookups.actions[resource.ID]; !ok {
+			actions, err := s.actionRepo.GetByResourceID(ctx, resource.ID)
+			if err != nil {
+				return nil, err
+			}
+			lookups.actions[resource.ID] = actions
+		}
+
+		ancestors, err := resourceAncestors(ctx, s.resourceRepo, resource, true)
+		if err != nil {
+			return nil, err
+		}
+		lookups.ancestors[resource.ID] = ancestors
+		for _, ancestor := range ancestors {
+			if _, ok := lookups.actions[ancestor.ID]; ok {
+				continue
+			}
+			actions, err := s.actionRepo.GetByResourceID(ctx, ancestor.ID)
+			if err != nil {
+				return nil, err
+			}
+			lookups.actions[ancestor.ID] = actions
+		}
+	}
+
+	return lookups, nil
+}
+
+// lookupSubject resolves a user together with its roles, the roles they inherit from, the user sets
+// among the given ones whose conditions it satisfies and every permission granted to any of them.
+// It returns nil when the user does not exist.
+func (s *PermissionService) lookupSubject(ctx context.Context, userSets []*domain.UserSet, username string) (*checkSubject, error) {
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return s.resolveSubject(ctx, userSets, user)
+}
+
+// resolveSubject resolves the roles, the user sets among the given ones and the permissions of a user
+func (s *PermissionService) resolveSubject(ctx context.Context, userSets []*domain.UserSet, user *domain.User) (*checkSubject, error) {
+	// Resolve the roles assigned to the user and every role they inherit from
+	assigned, err := s.userRoleRepo.ListRolesByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := expandRoles(ctx, s.roleRepo, assigned)
+	if err != nil {
+		return nil, err
+	}
+	roleIDs := make([]string, len(roles))
+	for i, role := range roles {
+		roleIDs[i] = role.ID
+	}
+
+	// Resolve the user sets whose conditions the user satisfies
+	userSets = filterUserSets(userSets, user)
+	userSetIDs := make([]string, len(userSets))
+	for i, userSet := range userSets {
+		userSetIDs[i] = userSet.ID
+	}
+
+	// Collect every permission granted to the user directly or through its roles and user sets
+	permissions, err := s.permissionRepo.ListBySubjects(ctx, domain.PermissionSubjects{
+		UserID:     user.ID,
+		RoleIDs:    roleIDs,
+		UserSetIDs: userSetIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &checkSubject{
+		user:        user,
+		roles:       roles,
+		assigned:    len(assigned),
+		userSets:    userSets,
+		permissions: permissions,
+	}, nil
+}
+
+// target returns the resource and the action of that resource with the given names, or nil when they do not exist
+func (l *checkLookups) target(resourceName, actionName string) (*domain.Resource, *domain.Action) {
+	resource := l.resources[resourceName]
+	if resource == nil {
+		return nil, nil
+	}
+	for _, action := range l.actions[resource.ID] {
+		if action.Name == actionName {
+			return resource, action
+		}
+	}
+	return resource, nil
+}
@@ -0,0 +1,625 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// lookupPageSize is the page size used when resources are listed for a lookup
+const lookupPageSize = 100
+
+// LookupResources returns the IDs of the resources the user may perform the action on, honoring roles,
+// user sets, resource sets, relationships, conditions and deny rules exactly like CheckPermission.
+// Only the candidate resources a grant of the user may apply to are evaluated, in ID order, with the user
+// looked up once; limit and offset page through the allowed resources.
+func (s *PermissionService) LookupResources(ctx context.Context, username, actionName string, requestContext map[string]interface{}, limit, offset int) ([]string, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	userSets, err := listUserSets(ctx, s.userSetRepo)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := s.lookupSubject(ctx, userSets, username)
+	if err != nil {
+		return nil, err
+	}
+	if subject == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	resourceSets, err := listResourceSets(ctx, s.resourceSetRepo)
+	if err != nil {
+		return nil, err
+	}
+	lookups := &checkLookups{resourceSets: resourceSets, userSets: userSets}
+
+	candidates, err := s.candidateResources(ctx, subject, resourceSets, actionName)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceIDs := []string{}
+	skipped := 0
+	for _, candidate := range candidates {
+		granted, err := s.decideTarget(ctx, lookups, subject, candidate.resource, candidate.action, requestContext, map[string]interface{}{}, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !granted {
+			continue
+		}
+
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		resourceIDs = append(resourceIDs, candidate.resource.ID)
+		if len(resourceIDs) == limit {
+			break
+		}
+	}
+
+	return resourceIDs, nil
+}
+
+// lookupCandidate is a resource a lookup evaluates, together with its action of the name looked up
+type lookupCandidate struct {
+	resource *domain.Resource
+	action   *domain.Action
+}
+
+// candidateResources returns the resources the user may be allowed to perform the action on, sorted by ID:
+// the resources targeted by the allow permissions of the user and their descendants inheriting them, the
+// members of the resource sets those permissions target, the resources allowing by default and the
+// resources related to the user. Every resource carrying the action is a candidate when the default
+// effect allows or a permission of the user is not restricted to a resource.
+func (s *PermissionService) candidateResources(ctx context.Context, subject *checkSubject, resourceSets []*domain.ResourceSet, actionName string) ([]lookupCandidate, error) {
+	if s.policy.DefaultEffect == domain.EffectAllow {
+		return s.actionCandidates(ctx, actionName, nil)
+	}
+
+	resources := map[string]*domain.Resource{}
+	var granted []*domain.Resource
+	grantedSets := map[string]bool{}
+	for _, p := range subject.permissions {
+		if p.Effect == domain.EffectDeny || (p.ActionName != nil && *p.ActionName != actionName) {
+			continue
+		}
+		if p.ResourceSetID != nil {
+			grantedSets[*p.ResourceSetID] = true
+			continue
+		}
+
+		resourceID := p.ResourceID
+		if p.ActionID != nil {
+			action, err := s.actionRepo.GetByID(ctx, *p.ActionID)
+			if errors.Is(err, domain.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if action.Name != actionName {
+				continue
+			}
+			if resourceID == nil {
+				resourceID = &action.ResourceID
+			}
+		}
+		if resourceID == nil {
+			// The permission applies to every resource
+			return s.actionCandidates(ctx, actionName, nil)
+		}
+
+		resource, err := s.resourceRepo.GetByID(ctx, *resourceID)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if resource.DeletedAt == nil {
+			granted = append(granted, resource)
+		}
+	}
+
+	// Permissions granted on a resource apply to the descendants inheriting from it
+	for _, resource := range granted {
+		resources[resource.ID] = resource
+		descendants, err := s.inheritingDescendants(ctx, resource)
+		if err != nil {
+			return nil, err
+		}
+		for _, descendant := range descendants {
+			resources[descendant.ID] = descendant
+		}
+	}
+
+	for offset := 0; ; offset += lookupPageSize {
+		page, err := s.resourceRepo.ListByDefaultEffect(ctx, domain.EffectAllow, lookupPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range page {
+			resources[resource.ID] = resource
+		}
+		if len(page) < lookupPageSize {
+			break
+		}
+	}
+
+	related, err := s.checker.relatedObjects(ctx, subjectRef{Type: domain.SubjectTypeUser, ID: subject.user.ID}, domain.ObjectTypeResource, DefaultCheckDepth)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range related {
+		if _, ok := resources[id]; ok {
+			continue
+		}
+		resource, err := s.resourceRepo.GetByID(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			// Tuples may name resources that were never created
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if resource.DeletedAt == nil {
+			resources[resource.ID] = resource
+		}
+	}
+
+	// Members of resource sets are found by evaluating the set conditions against every resource carrying the action
+	var sets []*domain.ResourceSet
+	for _, resourceSet := range resourceSets {
+		if grantedSets[resourceSet.ID] {
+			sets = append(sets, resourceSet)
+		}
+	}
+	if len(sets) > 0 {
+		return s.actionCandidates(ctx, actionName, func(resource *domain.Resource, action *domain.Action) bool {
+			_, ok := resources[resource.ID]
+			return ok || len(filterResourceSets(sets, resource, action)) > 0
+		})
+	}
+
+	candidates := make([]lookupCandidate, 0, len(resources))
+	for _, resource := range resources {
+		action, err := s.findAction(ctx, resource, actionName)
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			candidates = append(candidates, lookupCandidate{resource: resource, action: action})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].resource.ID < candidates[j].resource.ID
+	})
+	return candidates, nil
+}
+
+// actionCandidates returns the resources carrying an action of the given name, together with that action,
+// sorted by ID. When match is not nil only the resources it matches are returned.
+func (s *PermissionService) actionCandidates(ctx context.Context, actionName string, match func(*domain.Resource, *domain.Action) bool) ([]lookupCandidate, error) {
+	actions := map[string]*domain.Action{}
+	for offset := 0; ; offset += lookupPageSize {
+		page, err := s.actionRepo.List(ctx, lookupPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, action := range page {
+			if action.Name == actionName {
+				actions[action.ResourceID] = action
+			}
+		}
+		if len(page) < lookupPageSize {
+			break
+		}
+	}
+
+	candidates := []lookupCandidate{}
+	for offset := 0; ; offset += lookupPageSize {
+		page, err := s.resourceRepo.List(ctx, lookupPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range page {
+			action, ok := actions[resource.ID]
+			if !ok || resource.DeletedAt != nil || (match != nil && !match(resource, action)) {
+				continue
+			}
+			candidates = append(candidates, lookupCandidate{resource: resource, action: action})
+		}
+		if len(page) < lookupPageSize {
+			break
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].resource.ID < candidates[j].resource.ID
+	})
+	return candidates, nil
+}
+
+// inheritingDescendants returns the descendants of a resource that inherit its permissions,
+// leaving out the subtrees of the resources blocking inheritance
+func (s *PermissionService) inheritingDescendants(ctx context.Context, resource *domain.Resource) ([]*domain.Resource, error) {
+	descendants := []*domain.Resource{}
+	visited := map[string]bool{resource.ID: true}
+	level := []*domain.Resource{resource}
+
+	for depth := 0; len(level) > 0; depth++ {
+		if depth == maxResourceDepth {
+			return nil, fmt.Errorf("resource hierarchy of %s is too deep or cyclic", resource.ID)
+		}
+
+		var next []*domain.Resource
+		for _, parent := range level {
+			for offset := 0; ; offset += lookupPageSize {
+				children, err := s.resourceRepo.ListChildren(ctx, parent.ID, lookupPageSize, offset)
+				if err != nil {
+					return nil, err
+				}
+				for _, child := range children {
+					if visited[child.ID] || child.BlockInheritance {
+						continue
+					}
+					visited[child.ID] = true
+					descendants = append(descendants, child)
+					next = append(next, child)
+				}
+				if len(children) < lookupPageSize {
+					break
+				}
+			}
+		}
+		level = next
+	}
+
+	return descendants, nil
+}
+
+// LookupUsers returns every user that may perform the action on the resource, together with the path
+// that grants it. Roles and user sets whose own grants allow the action are included on request;
+// their permission conditions are evaluated without user attributes.
+// The permissions that may apply to the resource are listed first and only the users they are granted
+// to, directly or through roles and user sets, and the users related to the resource are evaluated,
+// unless the default effect allows.
+func (s *PermissionService) LookupUsers(ctx context.Context, resourceName, actionName string, requestContext map[string]interface{}, includeRoles, includeUserSets bool) ([]domain.AccessGrant, error) {
+	lookups, err := s.lookup(ctx, []domain.PermissionCheck{{Resource: resourceName, Action: actionName}})
+	if err != nil {
+		return nil, err
+	}
+	resource, action := lookups.target(resourceName, actionName)
+	if resource == nil {
+		return nil, fmt.Errorf("resource not found")
+	}
+	if action == nil {
+		return nil, fmt.Errorf("action not found")
+	}
+
+	// Roles and user sets are granted access through their own permissions only
+	policy := s.policy.ForResource(resource)
+	target := newCheckTarget(filterResourceSets(lookups.resourceSets, resource, action), &domain.User{}, resource, action, requestContext)
+	ancestors, inheritedActions, err := s.inheritance(ctx, lookups, resource, action)
+	if err != nil {
+		return nil, err
+	}
+	target.inherit(ancestors, inheritedActions)
+	permissions, err := s.permissionRepo.ListByTarget(ctx, target.permissionTarget())
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.grantedRoles(ctx, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.candidateUsers(ctx, lookups.userSets, permissions, roles, resource, policy)
+	if err != nil {
+		return nil, err
+	}
+	grants := []domain.AccessGrant{}
+	for _, user := range users {
+		subject, err := s.resolveSubject(ctx, lookups.userSets, user)
+		if err != nil {
+			return nil, err
+		}
+		decision := map[string]interface{}{}
+		granted, err := s.decideTarget(ctx, lookups, subject, resource, action, requestContext, decision, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !granted {
+			continue
+		}
+
+		path, err := s.grantPath(ctx, subject, decision)
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, domain.AccessGrant{
+			SubjectType: domain.GrantSubjectUser,
+			SubjectID:   user.ID,
+			SubjectName: user.Username,
+			Reason:      decision["reason"].(string),
+			Path:        path,
+		})
+	}
+
+	if includeRoles {
+		roleGrants, err := s.lookupRoleGrants(ctx, roles, permissions, target, policy)
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, roleGrants...)
+	}
+	if includeUserSets {
+		grants = append(grants, lookupUserSetGrants(lookups.userSets, permissions, target, policy)...)
+	}
+
+	return grants, nil
+}
+
+// grantedRoles returns the roles granted an allow permission among the given ones, followed by the
+// roles inheriting from them, walking the hierarchy breadth first
+func (s *PermissionService) grantedRoles(ctx context.Context, permissions []*domain.Permission) ([]*domain.Role, error) {
+	visited := map[string]bool{}
+	var roles []*domain.Role
+	for _, p := range permissions {
+		if p.RoleID == "" || p.Effect == domain.EffectDeny || visited[p.RoleID] {
+			continue
+		}
+		visited[p.RoleID] = true
+		role, err := s.roleRepo.GetByID(ctx, p.RoleID)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if role.DeletedAt == nil {
+			roles = append(roles, role)
+		}
+	}
+
+	for queue := roles; len(queue) > 0; {
+		role := queue[0]
+		queue = queue[1:]
+
+		children, err := s.roleRepo.ListChildren(ctx, role.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if visited[child.ID] {
+				continue
+			}
+			visited[child.ID] = true
+			roles = append(roles, child)
+			queue = append(queue, child)
+		}
+	}
+
+	return roles, nil
+}
+
+// candidateUsers returns the users the permissions on the resource may grant access to, sorted by username:
+// the users they are granted to directly, the users assigned one of the granted roles, the members of the
+// user sets they are granted to and the users related to the resource. Every user is a candidate when
+// the default effect allows.
+func (s *PermissionService) candidateUsers(ctx context.Context, userSets []*domain.UserSet, permissions []*domain.Permission, roles []*domain.Role, resource *domain.Resource, policy domain.CombiningPolicy) ([]*domain.User, error) {
+	users := map[string]*domain.User{}
+
+	var grantedSets []*domain.UserSet
+	for _, userSet := range userSets {
+		for _, p := range permissions {
+			if p.UserSetID != nil && *p.UserSetID == userSet.ID && p.Effect != domain.EffectDeny {
+				grantedSets = append(grantedSets, userSet)
+				break
+			}
+		}
+	}
+
+	// User set members are found by evaluating the set conditions against every user
+	if policy.DefaultEffect == domain.EffectAllow || len(grantedSets) > 0 {
+		for offset := 0; ; offset += lookupPageSize {
+			page, err := s.userRepo.List(ctx, lookupPageSize, offset)
+			if err != nil {
+				return nil, err
+			}
+			for _, user := range page {
+				if policy.DefaultEffect == domain.EffectAllow || len(filterUserSets(grantedSets, user)) > 0 {
+					users[user.ID] = user
+				}
+			}
+			if len(page) < lookupPageSize {
+				break
+			}
+		}
+	}
+
+	for _, role := range roles {
+		assigned, err := s.userRoleRepo.ListUsersByRoleID(ctx, role.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range assigned {
+			users[user.ID] = user
+		}
+	}
+
+	userIDs, err := s.checker.relatedSubjects(ctx, subjectRef{Type: domain.ObjectTypeResource, ID: resource.ID}, domain.SubjectTypeUser, DefaultCheckDepth)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range permissions {
+		if p.UserID != nil && p.Effect != domain.EffectDeny {
+			userIDs = append(userIDs, *p.UserID)
+		}
+	}
+	for _, id := range userIDs {
+		if _, ok := users[id]; ok {
+			continue
+		}
+		user, err := s.userRepo.GetByID(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			// Tuples may name users that were never created
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if user.DeletedAt == nil {
+			users[user.ID] = user
+		}
+	}
+
+	candidates := make([]*domain.User, 0, len(users))
+	for _, user := range users {
+		candidates = append(candidates, user)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Username < candidates[j].Username
+	})
+	return candidates, nil
+}
+
+// grantPath describes how a user was granted access from the context of its decision:
+// the roles or user set leading to the deciding permission, the chain of relationship tuples
+// or the default effect
+func (s *PermissionService) grantPath(ctx context.Context, subject *checkSubject, decision map[string]interface{}) ([]string, error) {
+	if relationshipPath, ok := decision["relationshipPath"].([]string); ok {
+		return relationshipPath, nil
+	}
+
+	permissionID, ok := decision["permissionId"].(string)
+	if !ok {
+		return []string{"default:" + domain.EffectAllow}, nil
+	}
+
+	path := []string{domain.GrantSubjectUser + ":" + subject.user.Username}
+	for _, p := range subject.permissions {
+		if p.ID != permissionID {
+			continue
+		}
+
+		switch {
+		case p.UserID != nil:
+			// Granted to the user directly
+		case p.UserSetID != nil:
+			for _, userSet := range subject.userSets {
+				if userSet.ID == *p.UserSetID {
+					path = append(path, domain.GrantSubjectUserSet+":"+userSet.Name)
+				}
+			}
+		default:
+			roles, err := rolePath(ctx, s.roleRepo, subject.roles[:subject.assigned], p.RoleID)
+			if err != nil {
+				return nil, err
+			}
+			for _, role := range roles {
+				path = append(path, domain.GrantSubjectRole+":"+role.Name)
+			}
+		}
+		break
+	}
+
+	return append(path, "permission:"+permissionID), nil
+}
+
+// lookupRoleGrants returns every role among the given ones whose own or inherited permissions, among the
+// permissions on the target, allow it
+func (s *PermissionService) lookupRoleGrants(ctx context.Context, roles []*domain.Role, permissions []*domain.Permission, target checkTarget, policy domain.CombiningPolicy) ([]domain.AccessGrant, error) {
+	grants := []domain.AccessGrant{}
+	for _, role := range roles {
+		expanded, err := expandRoles(ctx, s.roleRepo, []*domain.Role{role})
+		if err != nil {
+			return nil, err
+		}
+		roleIDs := make([]string, len(expanded))
+		for i, r := range expanded {
+			roleIDs[i] = r.ID
+		}
+
+		var rolePermissions []*domain.Permission
+		for _, p := range permissions {
+			if slices.Contains(roleIDs, p.RoleID) {
+				rolePermissions = append(rolePermissions, p)
+			}
+		}
+		granted, decisive, _ := decide(rolePermissions, target, policy.Algorithm)
+		if decisive == nil || !granted {
+			continue
+		}
+
+		chain, err := rolePath(ctx, s.roleRepo, []*domain.Role{role}, decisive.RoleID)
+		if err != nil {
+			return nil, err
+		}
+		path := make([]string, 0, len(chain)+1)
+		for _, r := range chain {
+			path = append(path, domain.GrantSubjectRole+":"+r.Name)
+		}
+		grants = append(grants, domain.AccessGrant{
+			SubjectType: domain.GrantSubjectRole,
+			SubjectID:   role.ID,
+			SubjectName: role.Name,
+			Reason:      "allowed by permission",
+			Path:        append(path, "permission:"+decisive.ID),
+		})
+	}
+
+	return grants, nil
+}
+
+// lookupUserSetGrants returns every user set whose permissions, among the permissions on the target, allow it
+func lookupUserSetGrants(userSets []*domain.UserSet, permissions []*domain.Permission, target checkTarget, policy domain.CombiningPolicy) []domain.AccessGrant {
+	grants := []domain.AccessGrant{}
+	for _, userSet := range userSets {
+		var userSetPermissions []*domain.Permission
+		for _, p := range permissions {
+			if p.UserSetID != nil && *p.UserSetID == userSet.ID {
+				userSetPermissions = append(userSetPermissions, p)
+			}
+		}
+		granted, decisive, _ := decide(userSetPermissions, target, policy.Algorithm)
+		if decisive == nil || !granted {
+			continue
+		}
+
+		grants = append(grants, domain.AccessGrant{
+			SubjectType: domain.GrantSubjectUserSet,
+			SubjectID:   userSet.ID,
+			SubjectName: userSet.Name,
+			Reason:      "allowed by permission",
+			Path:        []string{domain.GrantSubjectUserSet + ":" + userSet.Name, "permission:" + decisive.ID},
+		})
+	}
+
+	return grants
+}
+
+// findAction returns the action of the resource with the given name, or nil when it does not exist
+func (s *PermissionService) findAction(ctx context.Context, resource *domain.Resource, actionName string) (*domain.Action, error) {
+	actions, err := s.actionRepo.GetByResourceID(ctx, resource.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, action := range actions {
+		if action.Name == actionName {
+			return action, nil
+		}
+	}
+	return nil, nil
+}
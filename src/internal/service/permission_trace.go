@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/condition"
+)
+
+// recordDecision stores the rule that decided a check into the trace, if any
+func recordDecision(trace *domain.DecisionTrace, rule domain.DecisionRule) {
+	if trace != nil {
+		trace.Decision = rule
+	}
+}
+
+// explainPermission describes why a permission applies to the check target or not.
+// It follows the same steps as permissionMatches.
+func explainPermission(p *domain.Permission, target checkTarget) domain.PermissionTrace {
+	trace := domain.PermissionTrace{
+		ID:       p.ID,
+		Subject:  permissionSubject(p),
+		Effect:   p.Effect,
+		Priority: p.Priority,
+	}
+
+	switch {
+	case p.ResourceSetID != nil && !target.resourceSetIDs[*p.ResourceSetID]:
+		trace.Reason = "resource set does not match"
+		return trace
+	case p.ResourceID != nil && !target.resourceIDs[*p.ResourceID]:
+		trace.Reason = "resource does not match"
+		return trace
+	case p.ActionID != nil && !target.actionIDs[*p.ActionID],
+		p.ActionName != nil && *p.ActionName != target.actionName:
+		trace.Reason = "action does not match"
+		return trace
+	}
+
+	conditions, err := condition.Parse(p.Conditions)
+	switch {
+	case err != nil:
+		trace.Reason = err.Error()
+	case conditions == nil:
+		trace.Applicable = true
+		trace.Reason = "applies"
+	default:
+		conditionTrace := conditions.Explain(target.attributes)
+		trace.Conditions = &conditionTrace
+		trace.Applicable = conditionTrace.Matched
+		if trace.Applicable {
+			trace.Reason = "conditions matched"
+		} else {
+			trace.Reason = "conditions not satisfied"
+		}
+	}
+
+	return trace
+}
+
+// permissionSubject describes the subject a permission is granted to as "type:id"
+func permissionSubject(p *domain.Permission) string {
+	switch {
+	case p.UserID != nil:
+		return "user:" + *p.UserID
+	case p.UserSetID != nil:
+		return "user_set:" + *p.UserSetID
+	}
+	return "role:" + p.RoleID
+}
+
+// explainUserSets evaluates the conditions of every user set against the user
+func explainUserSets(ctx context.Context, userSetRepo domain.UserSetRepository, user *domain.User) ([]domain.SetTrace, error) {
+	attributes := condition.Attributes(user.Attributes)
+
+	traces := []domain.SetTrace{}
+	for offset := 0; ; offset += userSetPageSize {
+		userSets, err := userSetRepo.List(ctx, userSetPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, userSet := range userSets {
+			traces = append(traces, explainSet(userSet.ID, userSet.Name, userSet.Conditions, attributes))
+		}
+
+		if len(userSets) < userSetPageSize {
+			return traces, nil
+		}
+	}
+}
+
+// explainResourceSets evaluates the conditions of the resource sets against the resource and action
+func explainResourceSets(resourceSets []*domain.ResourceSet, resource *domain.Resource, action *domain.Action) []domain.SetTrace {
+	attributes := resourceSetAttributes(resource, action)
+
+	traces := make([]domain.SetTrace, len(resourceSets))
+	for i, resourceSet := range resourceSets {
+		traces[i] = explainSet(resourceSet.ID, resourceSet.Name, resourceSet.Conditions, attributes)
+	}
+	return traces
+}
+
+// explainSet evaluates the conditions of a user set or resource set against the attributes
+func explainSet(id, name string, data []byte, attributes map[string]interface{}) domain.SetTrace {
+	trace := domain.SetTrace{ID: id, Name: name}
+
+	conditions, err := condition.Parse(data)
+	if err != nil {
+		trace.Error = err.Error()
+		return trace
+	}
+	if conditions == nil {
+		return trace
+	}
+
+	conditionTrace := conditions.Explain(attributes)
+	trace.Conditions = &conditionTrace
+	trace.Member = conditionTrace.Matched
+	return trace
+}
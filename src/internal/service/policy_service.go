@@ -0,0 +1,1036 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/bundle"
+	"github.com/arifsetyawan/validra/src/pkg/policy"
+	"github.com/arifsetyawan/validra/src/pkg/tenant"
+)
+
+// policyPageSize is the page size used to load the entities a policy document is applied to
+const policyPageSize = 100
+
+// errPolicyPlanned rolls back the transaction a plan is computed in
+var errPolicyPlanned = errors.New("policy planned")
+
+// PolicyService plans and applies declarative policy documents
+type PolicyService struct {
+	transactor      domain.Transactor
+	resourceRepo    domain.ResourceRepository
+	actionRepo      domain.ActionRepository
+	roleRepo        domain.RoleRepository
+	userRoleRepo    domain.UserRoleRepository
+	userSetRepo     domain.UserSetRepository
+	resourceSetRepo domain.ResourceSetRepository
+	permissionRepo  domain.PermissionRepository
+	revisions       *RevisionService
+}
+
+// NewPolicyService creates a new PolicyService
+func NewPolicyService(
+	transactor domain.Transactor,
+	resourceRepo domain.ResourceRepository,
+	actionRepo domain.ActionRepository,
+	roleRepo domain.RoleRepository,
+	userRoleRepo domain.UserRoleRepository,
+	userSetRepo domain.UserSetRepository,
+	resourceSetRepo domain.ResourceSetRepository,
+	permissionRepo domain.PermissionRepository,
+	revisions *RevisionService,
+) *PolicyService {
+	return &PolicyService{
+		transactor:      transactor,
+		resourceRepo:    resourceRepo,
+		actionRepo:      actionRepo,
+		roleRepo:        roleRepo,
+		userRoleRepo:    userRoleRepo,
+		userSetRepo:     userSetRepo,
+		resourceSetRepo: resourceSetRepo,
+		permissionRepo:  permissionRepo,
+		revisions:       revisions,
+	}
+}
+
+// policyState indexes the entities of the tenant by name
+type policyState struct {
+	resources map[string]*domain.Resource
+	// actions is keyed by resource ID, then action name
+	actions      map[string]map[string]*domain.Action
+	resourceSets map[string]*domain.ResourceSet
+	userSets     map[string]*domain.UserSet
+	roles        map[string]*domain.Role
+}
+
+// policyRun reconciles the entities of the tenant with a document and records the changes it makes,
+// both by name for the plan and by ID for the revision
+type policyRun struct {
+	*PolicyService
+	state           *policyState
+	changes         []domain.PolicyChange
+	revisionChanges []domain.RevisionChange
+}
+
+// record notes a change made to an entity
+func (r *policyRun) record(entityType, name, operation string) {
+	r.changes = append(r.changes, domain.PolicyChange{EntityType: entityType, Name: name, Operation: operation})
+}
+
+// track notes the states of an entity before and after a change, given in bundle form
+func (r *policyRun) track(entityType, id string, before, after interface{}) {
+	r.revisionChanges = append(r.revisionChanges, entityChanges(entityType, id, before, after)...)
+}
+
+// PlanDocument parses a YAML or JSON policy document and plans it
+func (s *PolicyService) PlanDocument(ctx context.Context, data []byte, prune bool) (*domain.PolicyPlan, error) {
+	document, err := policy.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.Plan(ctx, document, prune)
+}
+
+// ApplyDocument parses a YAML or JSON policy document and applies it
+func (s *PolicyService) ApplyDocument(ctx context.Context, data []byte, prune bool) (*domain.PolicyPlan, error) {
+	document, err := policy.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.Apply(ctx, document, prune)
+}
+
+// Plan computes the changes Apply would make, without making them. The document is reconciled in a
+// transaction that is rolled back, so the plan reports exactly what applying it would do.
+func (s *PolicyService) Plan(ctx context.Context, document *policy.Document, prune bool) (*domain.PolicyPlan, error) {
+	var plan *domain.PolicyPlan
+	err := s.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		plan, err = s.reconcile(ctx, document, prune)
+		if err != nil {
+			return err
+		}
+		return errPolicyPlanned
+	})
+	if err != nil && !errors.Is(err, errPolicyPlanned) {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Apply reconciles the tenant with the document atomically: either every change is made or none is.
+//
+// Entities are matched by name. Missing entities are created and those that differ are updated. The
+// entities the document declares are fully managed by it: actions of declared resources, parents of
+// declared roles and permissions of declared roles and user sets that the document does not list are
+// deleted. With prune, resources, resource sets, user sets and roles the document does not declare are
+// deleted as well, together with their actions, role assignments and permissions; otherwise they are
+// left untouched.
+func (s *PolicyService) Apply(ctx context.Context, document *policy.Document, prune bool) (*domain.PolicyPlan, error) {
+	var plan *domain.PolicyPlan
+	err := s.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		plan, err = s.reconcile(ctx, document, prune)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// reconcile makes the changes needed for the tenant to match the document
+func (s *PolicyService) reconcile(ctx context.Context, document *policy.Document, prune bool) (*domain.PolicyPlan, error) {
+	if document.Tenant != "" && document.Tenant != tenant.FromContext(ctx) {
+		return nil, fmt.Errorf("invalid policy: document describes tenant %q, not %q", document.Tenant, tenant.FromContext(ctx))
+	}
+	if prune {
+		if err := validatePrune(document); err != nil {
+			return nil, err
+		}
+	}
+
+	state, err := s.loadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	run := &policyRun{PolicyService: s, state: state, changes: []domain.PolicyChange{}}
+
+	resources, err := orderResources(document.Resources)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resources {
+		if err := run.applyResource(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, rs := range document.ResourceSets {
+		if err := run.applyResourceSet(ctx, rs); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, us := range document.UserSets {
+		if err := run.applyUserSet(ctx, us); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create every role before linking them, as parents may be declared after their children
+	for _, r := range document.Roles {
+		if err := run.applyRole(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	if err := run.roleRepo.LockHierarchy(ctx); err != nil {
+		return nil, err
+	}
+	for _, r := range document.Roles {
+		if err := run.applyRoleParents(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, us := range document.UserSets {
+		userSetID := state.userSets[us.Name].ID
+		subject := domain.PermissionSubjects{UserSetIDs: []string{userSetID}}
+		newPermission := func() *domain.Permission { return &domain.Permission{UserSetID: &userSetID} }
+		if err := run.applyPermissions(ctx, subject, newPermission, domain.PolicyEntityUserSet+":"+us.Name, us.Permissions); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range document.Roles {
+		roleID := state.roles[r.Name].ID
+		subject := domain.PermissionSubjects{RoleIDs: []string{roleID}}
+		newPermission := func() *domain.Permission { return &domain.Permission{RoleID: roleID} }
+		if err := run.applyPermissions(ctx, subject, newPermission, domain.PolicyEntityRole+":"+r.Name, r.Permissions); err != nil {
+			return nil, err
+		}
+	}
+
+	if prune {
+		if err := run.prune(ctx, document); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.revisions.Record(ctx, "apply policy", run.revisionChanges...); err != nil {
+		return nil, err
+	}
+	return domain.NewPolicyPlan(run.changes), nil
+}
+
+// validatePrune checks that everything the document refers to is declared by it, since anything else would be pruned
+func validatePrune(document *policy.Document) error {
+	resources := make(map[string]map[string]bool, len(document.Resources))
+	for _, r := range document.Resources {
+		actions := make(map[string]bool, len(r.Actions))
+		for _, a := range r.Actions {
+			actions[a.Name] = true
+		}
+		resources[r.Name] = actions
+	}
+	resourceSets := make(map[string]bool, len(document.ResourceSets))
+	for _, rs := range document.ResourceSets {
+		resourceSets[rs.Name] = true
+	}
+	roles := make(map[string]bool, len(document.Roles))
+	for _, r := range document.Roles {
+		roles[r.Name] = true
+	}
+
+	for _, r := range document.Resources {
+		if _, ok := resources[r.Parent]; r.Parent != "" && !ok {
+			return fmt.Errorf("invalid policy: resource %q: parent resource %q is not declared and would be pruned", r.Name, r.Parent)
+		}
+	}
+	checkPermissions := func(subjectName string, permissions []policy.Permission) error {
+		for _, p := range permissions {
+			if p.ResourceSet != "" {
+				if !resourceSets[p.ResourceSet] {
+					return fmt.Errorf("invalid policy: %s: resource set %q is not declared and would be pruned", subjectName, p.ResourceSet)
+				}
+				continue
+			}
+			if !resources[p.Resource][p.Action] {
+				return fmt.Errorf("invalid policy: %s: action %q of resource %q is not declared and would be pruned", subjectName, p.Action, p.Resource)
+			}
+		}
+		return nil
+	}
+	for _, us := range document.UserSets {
+		if err := checkPermissions(domain.PolicyEntityUserSet+":"+us.Name, us.Permissions); err != nil {
+			return err
+		}
+	}
+	for _, r := range document.Roles {
+		for _, parent := range r.Parents {
+			if !roles[parent] {
+				return fmt.Errorf("invalid policy: role %q: parent role %q is not declared and would be pruned", r.Name, parent)
+			}
+		}
+		if err := checkPermissions(domain.PolicyEntityRole+":"+r.Name, r.Permissions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadState loads the resources, sets and roles of the tenant, skipping deleted ones
+func (s *PolicyService) loadState(ctx context.Context) (*policyState, error) {
+	state := &policyState{
+		resources:    make(map[string]*domain.Resource),
+		actions:      make(map[string]map[string]*domain.Action),
+		resourceSets: make(map[string]*domain.ResourceSet),
+		userSets:     make(map[string]*domain.UserSet),
+		roles:        make(map[string]*domain.Role),
+	}
+
+	for offset := 0; ; offset += policyPageSize {
+		resources, err := s.resourceRepo.List(ctx, policyPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range resources {
+			if r.DeletedAt == nil {
+				state.resources[r.Name] = r
+			}
+		}
+		if len(resources) < policyPageSize {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += policyPageSize {
+		resourceSets, err := s.resourceSetRepo.List(ctx, policyPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, rs := range resourceSets {
+			state.resourceSets[rs.Name] = rs
+		}
+		if len(resourceSets) < policyPageSize {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += policyPageSize {
+		userSets, err := s.userSetRepo.List(ctx, policyPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, us := range userSets {
+			state.userSets[us.Name] = us
+		}
+		if len(userSets) < policyPageSize {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += policyPageSize {
+		roles, err := s.roleRepo.List(ctx, policyPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range roles {
+			if r.DeletedAt == nil {
+				state.roles[r.Name] = r
+			}
+		}
+		if len(roles) < policyPageSize {
+			break
+		}
+	}
+
+	return state, nil
+}
+
+// resourceActions returns the actions of a resource by name, loading them on first use
+func (r *policyRun) resourceActions(ctx context.Context, resourceID string) (map[string]*domain.Action, error) {
+	if actions, ok := r.state.actions[resourceID]; ok {
+		return actions, nil
+	}
+
+	actions, err := r.actionRepo.GetByResourceID(ctx, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*domain.Action, len(actions))
+	for _, a := range actions {
+		if a.DeletedAt == nil {
+			byName[a.Name] = a
+		}
+	}
+	r.state.actions[resourceID] = byName
+	return byName, nil
+}
+
+// applyResource creates or updates a resource and reconciles its actions
+func (r *policyRun) applyResource(ctx context.Context, pr policy.Resource) error {
+	var parentID *string
+	if pr.Parent != "" {
+		parent, ok := r.state.resources[pr.Parent]
+		if !ok {
+			return fmt.Errorf("invalid policy: resource %q: parent resource %q not found", pr.Name, pr.Parent)
+		}
+		parentID = &parent.ID
+	}
+
+	desired := &domain.Resource{
+		Name:               pr.Name,
+		Description:        pr.Description,
+		Attributes:         normalizeJSON(pr.Attributes),
+		CombiningAlgorithm: pr.CombiningAlgorithm,
+		DefaultEffect:      pr.DefaultEffect,
+		ParentID:           parentID,
+		BlockInheritance:   pr.BlockInheritance,
+	}
+
+	resource, ok := r.state.resources[pr.Name]
+	switch {
+	case !ok:
+		if err := r.resourceRepo.Create(ctx, desired); err != nil {
+			return err
+		}
+		resource = desired
+		r.state.resources[pr.Name] = resource
+		r.state.actions[resource.ID] = map[string]*domain.Action{}
+		r.track(domain.EntityResource, resource.ID, nil, bundleResource(resource))
+		r.record(domain.PolicyEntityResource, pr.Name, domain.PolicyOperationCreate)
+	case !resourceMatches(resource, desired):
+		if parentID != nil && !equalIDs(resource.ParentID, parentID) {
+			// Moving a resource under one of its descendants would make the tree cyclic
+			ancestors, err := resourceAncestors(ctx, r.resourceRepo, r.state.resources[pr.Parent], false)
+			if err != nil {
+				return err
+			}
+			for _, ancestor := range ancestors {
+				if ancestor.ID == resource.ID {
+					return fmt.Errorf("invalid policy: resource %q cannot be moved under its descendant %q", pr.Name, pr.Parent)
+				}
+			}
+		}
+
+		before := bundleResource(resource)
+		resource.Description = desired.Description
+		resource.Attributes = desired.Attributes
+		resource.CombiningAlgorithm = desired.CombiningAlgorithm
+		resource.DefaultEffect = desired.DefaultEffect
+		resource.ParentID = desired.ParentID
+		resource.BlockInheritance = desired.BlockInheritance
+		if err := r.resourceRepo.Update(ctx, resource); err != nil {
+			return err
+		}
+		r.track(domain.EntityResource, resource.ID, before, bundleResource(resource))
+		r.record(domain.PolicyEntityResource, pr.Name, domain.PolicyOperationUpdate)
+	}
+
+	actions, err := r.resourceActions(ctx, resource.ID)
+	if err != nil {
+		return err
+	}
+	declared := make(map[string]bool, len(pr.Actions))
+	for _, a := range pr.Actions {
+		declared[a.Name] = true
+		name := pr.Name + "#" + a.Name
+		attributes := normalizeJSON(a.Attributes)
+
+		action, ok := actions[a.Name]
+		switch {
+		case !ok:
+			action = &domain.Action{
+				ResourceID:  resource.ID,
+				Name:        a.Name,
+				Description: a.Description,
+				Attributes:  attributes,
+			}
+			if err := r.actionRepo.Create(ctx, action); err != nil {
+				return err
+			}
+			actions[a.Name] = action
+			r.track(domain.EntityAction, action.ID, nil, bundleAction(action))
+			r.record(domain.PolicyEntityAction, name, domain.PolicyOperationCreate)
+		case action.Description != a.Description || !equalJSON(action.Attributes, attributes):
+			before := bundleAction(action)
+			action.Description = a.Description
+			action.Attributes = attributes
+			if err := r.actionRepo.Update(ctx, action); err != nil {
+				return err
+			}
+			r.track(domain.EntityAction, action.ID, before, bundleAction(action))
+			r.record(domain.PolicyEntityAction, name, domain.PolicyOperationUpdate)
+		}
+	}
+
+	// Actions of a declared resource are managed by the document
+	for name, action := range actions {
+		if declared[name] {
+			continue
+		}
+		if err := r.deleteAction(ctx, pr.Name, action); err != nil {
+			return err
+		}
+		delete(actions, name)
+	}
+
+	return nil
+}
+
+// applyResourceSet creates or updates a resource set
+func (r *policyRun) applyResourceSet(ctx context.Context, rs policy.ResourceSet) error {
+	conditions := normalizeJSON(rs.Conditions)
+
+	resourceSet, ok := r.state.resourceSets[rs.Name]
+	switch {
+	case !ok:
+		resourceSet = &domain.ResourceSet{Name: rs.Name, Description: rs.Description, Conditions: conditions}
+		if err := r.resourceSetRepo.Create(ctx, resourceSet); err != nil {
+			return err
+		}
+		r.state.resourceSets[rs.Name] = resourceSet
+		r.track(domain.EntityResourceSet, resourceSet.ID, nil, bundleResourceSet(resourceSet))
+		r.record(domain.PolicyEntityResourceSet, rs.Name, domain.PolicyOperationCreate)
+	case resourceSet.Description != rs.Description || !equalJSON(resourceSet.Conditions, conditions):
+		before := bundleResourceSet(resourceSet)
+		resourceSet.Description = rs.Description
+		resourceSet.Conditions = conditions
+		if err := r.resourceSetRepo.Update(ctx, resourceSet); err != nil {
+			return err
+		}
+		r.track(domain.EntityResourceSet, resourceSet.ID, before, bundleResourceSet(resourceSet))
+		r.record(domain.PolicyEntityResourceSet, rs.Name, domain.PolicyOperationUpdate)
+	}
+
+	return nil
+}
+
+// applyUserSet creates or updates a user set
+func (r *policyRun) applyUserSet(ctx context.Context, us policy.UserSet) error {
+	conditions := normalizeJSON(us.Conditions)
+
+	userSet, ok := r.state.userSets[us.Name]
+	switch {
+	case !ok:
+		userSet = &domain.UserSet{Name: us.Name, Description: us.Description, Conditions: conditions}
+		if err := r.userSetRepo.Create(ctx, userSet); err != nil {
+			return err
+		}
+		r.state.userSets[us.Name] = userSet
+		r.track(domain.EntityUserSet, userSet.ID, nil, bundleUserSet(userSet))
+		r.record(domain.PolicyEntityUserSet, us.Name, domain.PolicyOperationCreate)
+	case userSet.Description != us.Description || !equalJSON(userSet.Conditions, conditions):
+		before := bundleUserSet(userSet)
+		userSet.Description = us.Description
+		userSet.Conditions = conditions
+		if err := r.userSetRepo.Update(ctx, userSet); err != nil {
+			return err
+		}
+		r.track(domain.EntityUserSet, userSet.ID, before, bundleUserSet(userSet))
+		r.record(domain.PolicyEntityUserSet, us.Name, domain.PolicyOperationUpdate)
+	}
+
+	return nil
+}
+
+// applyRole creates or updates a role
+func (r *policyRun) applyRole(ctx context.Context, pr policy.Role) error {
+	role, ok := r.state.roles[pr.Name]
+	switch {
+	case !ok:
+		role = &domain.Role{Name: pr.Name, Description: pr.Description}
+		if err := r.roleRepo.Create(ctx, role); err != nil {
+			return err
+		}
+		r.state.roles[pr.Name] = role
+		r.track(domain.EntityRole, role.ID, nil, bundleRole(role))
+		r.record(domain.PolicyEntityRole, pr.Name, domain.PolicyOperationCreate)
+	case role.Description != pr.Description:
+		before := bundleRole(role)
+		role.Description = pr.Description
+		if err := r.roleRepo.Update(ctx, role); err != nil {
+			return err
+		}
+		r.track(domain.EntityRole, role.ID, before, bundleRole(role))
+		r.record(domain.PolicyEntityRole, pr.Name, domain.PolicyOperationUpdate)
+	}
+
+	return nil
+}
+
+// applyRoleParents makes a role inherit from exactly the parents the document declares
+func (r *policyRun) applyRoleParents(ctx context.Context, pr policy.Role) error {
+	role := r.state.roles[pr.Name]
+
+	current, err := r.roleRepo.ListParents(ctx, role.ID)
+	if err != nil {
+		return err
+	}
+	linked := make(map[string]*domain.Role, len(current))
+	for _, parent := range current {
+		linked[parent.ID] = parent
+	}
+
+	declared := make(map[string]bool, len(pr.Parents))
+	for _, name := range pr.Parents {
+		parent, ok := r.state.roles[name]
+		if !ok {
+			return fmt.Errorf("invalid policy: role %q: parent role %q not found", pr.Name, name)
+		}
+		declared[parent.ID] = true
+		if linked[parent.ID] != nil {
+			continue
+		}
+
+		// Reject links that would make the role inherit from itself
+		ancestors, err := expandRoles(ctx, r.roleRepo, []*domain.Role{parent})
+		if err != nil {
+			return err
+		}
+		for _, ancestor := range ancestors {
+			if ancestor.ID == role.ID {
+				return fmt.Errorf("invalid policy: role %q: inheriting from %q would create a cycle", pr.Name, name)
+			}
+		}
+
+		roleParent := &domain.RoleParent{RoleID: role.ID, ParentID: parent.ID}
+		if err := r.roleRepo.AddParent(ctx, roleParent); err != nil {
+			return err
+		}
+		linked[parent.ID] = parent
+		r.track(domain.EntityRoleParent, bundle.RoleParentKey(role.ID, parent.ID), nil, bundleRoleParent(roleParent))
+		r.record(domain.PolicyEntityRoleParent, pr.Name+" > "+name, domain.PolicyOperationCreate)
+	}
+
+	for _, parent := range current {
+		if declared[parent.ID] {
+			continue
+		}
+		roleParent, err := r.roleRepo.RemoveParent(ctx, role.ID, parent.ID)
+		if err != nil {
+			return err
+		}
+		r.track(domain.EntityRoleParent, bundle.RoleParentKey(role.ID, parent.ID), bundleRoleParent(roleParent), nil)
+		r.record(domain.PolicyEntityRoleParent, pr.Name+" > "+parent.Name, domain.PolicyOperationDelete)
+	}
+
+	return nil
+}
+
+// applyPermissions makes a role or user set hold exactly the permissions the document declares.
+// Grants that only differ by priority are updated.
+func (r *policyRun) applyPermissions(ctx context.Context, subject domain.PermissionSubjects, newPermission func() *domain.Permission, subjectName string, permissions []policy.Permission) error {
+	existing, err := r.permissionRepo.ListBySubjects(ctx, subject)
+	if err != nil {
+		return err
+	}
+	kept := make(map[string]bool, len(existing))
+
+	for _, p := range permissions {
+		permission := newPermission()
+		if p.ResourceSet != "" {
+			resourceSet, ok := r.state.resourceSets[p.ResourceSet]
+			if !ok {
+				return fmt.Errorf("invalid policy: %s: resource set %q not found", subjectName, p.ResourceSet)
+			}
+			permission.ResourceSetID = &resourceSet.ID
+			if p.Action != "" {
+				actionName := p.Action
+				permission.ActionName = &actionName
+			}
+		} else {
+			resource, ok := r.state.resources[p.Resource]
+			if !ok {
+				return fmt.Errorf("invalid policy: %s: resource %q not found", subjectName, p.Resource)
+			}
+			actions, err := r.resourceActions(ctx, resource.ID)
+			if err != nil {
+				return err
+			}
+			action, ok := actions[p.Action]
+			if !ok {
+				return fmt.Errorf("invalid policy: %s: action %q of resource %q not found", subjectName, p.Action, p.Resource)
+			}
+			permission.ResourceID = &resource.ID
+			permission.ActionID = &action.ID
+		}
+		permission.Effect = p.EffectOrDefault()
+		permission.Priority = p.Priority
+		permission.Conditions = normalizeJSON(p.Conditions)
+		name := r.permissionName(subjectName, permission)
+
+		var match *domain.Permission
+		for _, e := range existing {
+			if !kept[e.ID] && samePermission(e, permission) {
+				match = e
+				break
+			}
+		}
+		switch {
+		case match == nil:
+			if err := r.permissionRepo.Create(ctx, permission); err != nil {
+				return err
+			}
+			r.track(domain.EntityPermission, permission.ID, nil, bundlePermission(permission))
+			r.record(domain.PolicyEntityPermission, name, domain.PolicyOperationCreate)
+		case match.Priority != permission.Priority:
+			before := bundlePermission(match)
+			match.Priority = permission.Priority
+			if err := r.permissionRepo.Update(ctx, match); err != nil {
+				return err
+			}
+			r.track(domain.EntityPermission, match.ID, before, bundlePermission(match))
+			r.record(domain.PolicyEntityPermission, name, domain.PolicyOperationUpdate)
+		}
+		if match != nil {
+			kept[match.ID] = true
+		}
+	}
+
+	for _, e := range existing {
+		if kept[e.ID] {
+			continue
+		}
+		deleted, err := r.permissionRepo.Delete(ctx, e.ID)
+		if err != nil {
+			return err
+		}
+		r.track(domain.EntityPermission, deleted.ID, bundlePermission(deleted), nil)
+		r.record(domain.PolicyEntityPermission, r.permissionName(subjectName, e), domain.PolicyOperationDelete)
+	}
+
+	return nil
+}
+
+// prune deletes the resources, resource sets, user sets and roles the document does not declare
+func (r *policyRun) prune(ctx context.Context, document *policy.Document) error {
+	declared := make(map[string]bool)
+	for _, pr := range document.Roles {
+		declared[domain.PolicyEntityRole+":"+pr.Name] = true
+	}
+	for _, us := range document.UserSets {
+		declared[domain.PolicyEntityUserSet+":"+us.Name] = true
+	}
+	for _, rs := range document.ResourceSets {
+		declared[domain.PolicyEntityResourceSet+":"+rs.Name] = true
+	}
+	for _, pr := range document.Resources {
+		declared[domain.PolicyEntityResource+":"+pr.Name] = true
+	}
+
+	for _, name := range sortedKeys(r.state.roles) {
+		if declared[domain.PolicyEntityRole+":"+name] {
+			continue
+		}
+		if err := r.deleteRole(ctx, r.state.roles[name]); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(r.state.userSets) {
+		if declared[domain.PolicyEntityUserSet+":"+name] {
+			continue
+		}
+		userSet := r.state.userSets[name]
+		if err := r.deletePermissions(ctx, func(p *domain.Permission) bool {
+			return p.UserSetID != nil && *p.UserSetID == userSet.ID
+		}); err != nil {
+			return err
+		}
+		deleted, err := r.userSetRepo.Delete(ctx, userSet.ID)
+		if err != nil {
+			return err
+		}
+		r.track(domain.EntityUserSet, deleted.ID, bundleUserSet(deleted), nil)
+		delete(r.state.userSets, name)
+		r.record(domain.PolicyEntityUserSet, name, domain.PolicyOperationDelete)
+	}
+
+	for _, name := range sortedKeys(r.state.resourceSets) {
+		if declared[domain.PolicyEntityResourceSet+":"+name] {
+			continue
+		}
+		resourceSet := r.state.resourceSets[name]
+		if err := r.deletePermissions(ctx, func(p *domain.Permission) bool {
+			return p.ResourceSetID != nil && *p.ResourceSetID == resourceSet.ID
+		}); err != nil {
+			return err
+		}
+		deleted, err := r.resourceSetRepo.Delete(ctx, resourceSet.ID)
+		if err != nil {
+			return err
+		}
+		r.track(domain.EntityResourceSet, deleted.ID, bundleResourceSet(deleted), nil)
+		delete(r.state.resourceSets, name)
+		r.record(domain.PolicyEntityResourceSet, name, domain.PolicyOperationDelete)
+	}
+
+	for _, name := range sortedKeys(r.state.resources) {
+		if declared[domain.PolicyEntityResource+":"+name] {
+			continue
+		}
+		resource := r.state.resources[name]
+		actions, err := r.resourceActions(ctx, resource.ID)
+		if err != nil {
+			return err
+		}
+		for _, actionName := range sortedKeys(actions) {
+			if err := r.deleteAction(ctx, name, actions[actionName]); err != nil {
+				return err
+			}
+		}
+		if err := r.deletePermissions(ctx, func(p *domain.Permission) bool {
+			return p.ResourceID != nil && *p.ResourceID == resource.ID
+		}); err != nil {
+			return err
+		}
+		deleted, err := r.resourceRepo.Delete(ctx, resource.ID)
+		if err != nil {
+			return err
+		}
+		r.track(domain.EntityResource, deleted.ID, bundleResource(deleted), nil)
+		delete(r.state.resources, name)
+		r.record(domain.PolicyEntityResource, name, domain.PolicyOperationDelete)
+	}
+
+	return nil
+}
+
+// deleteRole deletes a role together with its parent links, assignments and permissions
+func (r *policyRun) deleteRole(ctx context.Context, role *domain.Role) error {
+	parents, err := r.roleRepo.ListParents(ctx, role.ID)
+	if err != nil {
+		return err
+	}
+	for _, parent := range parents {
+		roleParent, err := r.roleRepo.RemoveParent(ctx, role.ID, parent.ID)
+		if err != nil {
+			return err
+		}
+		r.track(domain.EntityRoleParent, bundle.RoleParentKey(role.ID, parent.ID), bundleRoleParent(roleParent), nil)
+		r.record(domain.PolicyEntityRoleParent, role.Name+" > "+parent.Name, domain.PolicyOperationDelete)
+	}
+
+	users, err := r.userRoleRepo.ListUsersByRoleID(ctx, role.ID)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		userRole, err := r.userRoleRepo.Delete(ctx, user.ID, role.ID)
+		if err != nil {
+			return err
+		}
+		r.track(domain.EntityUserRole, userRole.ID, bundleUserRole(userRole), nil)
+	}
+
+	if err := r.deletePermissions(ctx, func(p *domain.Permission) bool {
+		return p.RoleID == role.ID
+	}); err != nil {
+		return err
+	}
+	deleted, err := r.roleRepo.Delete(ctx, role.ID)
+	if err != nil {
+		return err
+	}
+	r.track(domain.EntityRole, deleted.ID, bundleRole(deleted), nil)
+	delete(r.state.roles, role.Name)
+	r.record(domain.PolicyEntityRole, role.Name, domain.PolicyOperationDelete)
+	return nil
+}
+
+// deleteAction deletes an action together with the permissions granted on it
+func (r *policyRun) deleteAction(ctx context.Context, resourceName string, action *domain.Action) error {
+	if err := r.deletePermissions(ctx, func(p *domain.Permission) bool {
+		return p.ActionID != nil && *p.ActionID == action.ID
+	}); err != nil {
+		return err
+	}
+	deleted, err := r.actionRepo.Delete(ctx, action.ID)
+	if err != nil {
+		return err
+	}
+	r.track(domain.EntityAction, deleted.ID, bundleAction(deleted), nil)
+	r.record(domain.PolicyEntityAction, resourceName+"#"+action.Name, domain.PolicyOperationDelete)
+	return nil
+}
+
+// deletePermissions deletes every permission of the tenant the filter selects
+func (r *policyRun) deletePermissions(ctx context.Context, filter func(p *domain.Permission) bool) error {
+	var selected []*domain.Permission
+	for offset := 0; ; offset += policyPageSize {
+		permissions, err := r.permissionRepo.List(ctx, policyPageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, p := range permissions {
+			if filter(p) {
+				selected = append(selected, p)
+			}
+		}
+		if len(permissions) < policyPageSize {
+			break
+		}
+	}
+
+	for _, p := range selected {
+		deleted, err := r.permissionRepo.Delete(ctx, p.ID)
+		if err != nil {
+			return err
+		}
+		r.track(domain.EntityPermission, deleted.ID, bundlePermission(deleted), nil)
+		r.record(domain.PolicyEntityPermission, r.permissionName(r.subjectName(p), p), domain.PolicyOperationDelete)
+	}
+	return nil
+}
+
+// permissionName describes a permission as "<subject> <effect> <resource>#<action>" or
+// "<subject> <effect> resource_set:<name>", followed by "#<action>" when restricted to an action
+func (r *policyRun) permissionName(subjectName string, p *domain.Permission) string {
+	target := "*"
+	switch {
+	case p.ResourceSetID != nil:
+		target = domain.PolicyEntityResourceSet + ":" + *p.ResourceSetID
+		for name, resourceSet := range r.state.resourceSets {
+			if resourceSet.ID == *p.ResourceSetID {
+				target = domain.PolicyEntityResourceSet + ":" + name
+			}
+		}
+		if p.ActionName != nil {
+			target += "#" + *p.ActionName
+		}
+	case p.ResourceID != nil:
+		target = *p.ResourceID
+		for name, resource := range r.state.resources {
+			if resource.ID == *p.ResourceID {
+				target = name
+			}
+		}
+		if p.ActionID != nil {
+			actionName := *p.ActionID
+			for name, action := range r.state.actions[*p.ResourceID] {
+				if action.ID == *p.ActionID {
+					actionName = name
+				}
+			}
+			target += "#" + actionName
+		}
+	}
+	return subjectName + " " + p.Effect + " " + target
+}
+
+// subjectName describes the subject a permission is granted to by name when it is known
+func (r *policyRun) subjectName(p *domain.Permission) string {
+	switch {
+	case p.UserID != nil:
+		return domain.GrantSubjectUser + ":" + *p.UserID
+	case p.UserSetID != nil:
+		for name, userSet := range r.state.userSets {
+			if userSet.ID == *p.UserSetID {
+				return domain.PolicyEntityUserSet + ":" + name
+			}
+		}
+		return domain.PolicyEntityUserSet + ":" + *p.UserSetID
+	}
+	for name, role := range r.state.roles {
+		if role.ID == p.RoleID {
+			return domain.PolicyEntityRole + ":" + name
+		}
+	}
+	return domain.PolicyEntityRole + ":" + p.RoleID
+}
+
+// orderResources orders resources so that parents declared in the document come before their children
+func orderResources(resources []policy.Resource) ([]policy.Resource, error) {
+	byName := make(map[string]policy.Resource, len(resources))
+	for _, r := range resources {
+		byName[r.Name] = r
+	}
+
+	ordered := make([]policy.Resource, 0, len(resources))
+	done := make(map[string]bool, len(resources))
+	visiting := make(map[string]bool)
+	var visit func(r policy.Resource) error
+	visit = func(r policy.Resource) error {
+		if done[r.Name] {
+			return nil
+		}
+		if visiting[r.Name] {
+			return fmt.Errorf("invalid policy: resource %q is its own ancestor", r.Name)
+		}
+		visiting[r.Name] = true
+		if parent, ok := byName[r.Parent]; ok {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		delete(visiting, r.Name)
+		done[r.Name] = true
+		ordered = append(ordered, r)
+		return nil
+	}
+
+	for _, r := range resources {
+		if err := visit(r); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// resourceMatches reports whether a resource already has the fields a document declares
+func resourceMatches(resource, desired *domain.Resource) bool {
+	return resource.Description == desired.Description &&
+		equalJSON(resource.Attributes, desired.Attributes) &&
+		resource.CombiningAlgorithm == desired.CombiningAlgorithm &&
+		resource.DefaultEffect == desired.DefaultEffect &&
+		equalIDs(resource.ParentID, desired.ParentID) &&
+		resource.BlockInheritance == desired.BlockInheritance
+}
+
+// samePermission reports whether two permissions grant the same effect on the same target under the same conditions
+func samePermission(a, b *domain.Permission) bool {
+	return a.Effect == b.Effect &&
+		equalIDs(a.ActionID, b.ActionID) &&
+		equalIDs(a.ResourceID, b.ResourceID) &&
+		equalIDs(a.ResourceSetID, b.ResourceSetID) &&
+		equalIDs(a.ActionName, b.ActionName) &&
+		equalJSON(a.Conditions, b.Conditions)
+}
+
+// normalizeJSON re-encodes a JSON value so that equal values have equal encodings, nil standing for no value
+func normalizeJSON(data []byte) []byte {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return data
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return data
+	}
+	return normalized
+}
+
+// equalJSON reports whether two JSON values are equal regardless of formatting and key order
+func equalJSON(a, b []byte) bool {
+	return bytes.Equal(normalizeJSON(a), normalizeJSON(b))
+}
+
+// sortedKeys returns the keys of a map in ascending order, so that changes are made in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
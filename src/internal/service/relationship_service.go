@@ -0,0 +1,464 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/schema"
+)
+
+// DefaultCheckDepth is the maximum number of relationship hops followed by a check
+const DefaultCheckDepth = 25
+
+// maxRelationshipBatch is the maximum number of tuples accepted by a single write
+const maxRelationshipBatch = 1000
+
+// ErrCheckDepthExceeded is returned when a check needs more hops than its depth allows
+var ErrCheckDepthExceeded = errors.New("maximum relationship check depth exceeded")
+
+// errRelationCycle leaves a relation undecided because it depends on itself. Like a depth error, it
+// reads as "not held" where the relation grants access and as "held" where an exclusion takes access
+// away, so that an undecided relation never grants access.
+var errRelationCycle = errors.New("relation depends on itself")
+
+// undecided reports whether err leaves a relation undecided rather than failing the check
+func undecided(err error) bool {
+	return errors.Is(err, errRelationCycle) || errors.Is(err, ErrCheckDepthExceeded)
+}
+
+// worseUndecided returns the undecided error to report out of two, preferring a depth error to a cycle
+func worseUndecided(current, err error) error {
+	if current == nil || errors.Is(err, ErrCheckDepthExceeded) {
+		return err
+	}
+	return current
+}
+
+// RelationshipService handles business logic for relationship tuples
+type RelationshipService struct {
+	tupleRepo  domain.RelationTupleRepository
+	schemaRepo domain.RelationSchemaRepository
+	checker    *relationChecker
+	revisions  *RevisionService
+}
+
+// NewRelationshipService creates a new RelationshipService
+func NewRelationshipService(tupleRepo domain.RelationTupleRepository, schemaRepo domain.RelationSchemaRepository, revisions *RevisionService) *RelationshipService {
+	return &RelationshipService{
+		tupleRepo:  tupleRepo,
+		schemaRepo: schemaRepo,
+		checker:    newRelationChecker(tupleRepo, schemaRepo),
+		revisions:  revisions,
+	}
+}
+
+// WriteRelationships atomically writes and deletes a batch of relationship tuples
+func (s *RelationshipService) WriteRelationships(ctx context.Context, writes, deletes []*domain.RelationTuple) error {
+	if len(writes)+len(deletes) == 0 {
+		return fmt.Errorf("at least one relationship is required")
+	}
+	if len(writes)+len(deletes) > maxRelationshipBatch {
+		return fmt.Errorf("at most %d relationships can be written at once", maxRelationshipBatch)
+	}
+
+	for _, tuple := range append(append([]*domain.RelationTuple{}, writes...), deletes...) {
+		if err := tuple.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Written tuples must agree with the schema; deletes are not checked so that tuples
+	// made obsolete by a schema change can still be removed
+	definition, err := latestSchema(ctx, s.schemaRepo)
+	if err != nil {
+		return err
+	}
+	for _, tuple := range writes {
+		if err := validateTupleSchema(definition, tuple); err != nil {
+			return err
+		}
+	}
+
+	return s.revisions.Commit(ctx, "write relationships", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		// Only tuples that actually appear or disappear are changes, since writing an existing
+		// tuple and deleting a missing one are no-ops
+		exists := map[string]bool{}
+		var changes []domain.RevisionChange
+		for _, tuple := range deletes {
+			existing, err := findTuple(ctx, s.tupleRepo, tuple)
+			if err != nil {
+				return nil, err
+			}
+			if existing != nil && !exists[tuple.String()] {
+				changes = append(changes, entityChange(domain.EntityRelationship, tuple.String(), bundleRelationship(existing), nil))
+			}
+			exists[tuple.String()] = false
+		}
+		var created []*domain.RelationTuple
+		for _, tuple := range writes {
+			found, seen := exists[tuple.String()]
+			if !seen {
+				existing, err := findTuple(ctx, s.tupleRepo, tuple)
+				if err != nil {
+					return nil, err
+				}
+				found = existing != nil
+			}
+			if !found {
+				created = append(created, tuple)
+			}
+			exists[tuple.String()] = true
+		}
+
+		if err := s.tupleRepo.Write(ctx, writes, deletes); err != nil {
+			return nil, err
+		}
+		for _, tuple := range created {
+			changes = append(changes, entityChange(domain.EntityRelationship, tuple.String(), nil, bundleRelationship(tuple)))
+		}
+		return changes, nil
+	})
+}
+
+// CreateRelationship writes a single relationship tuple
+func (s *RelationshipService) CreateRelationship(ctx context.Context, tuple *domain.RelationTuple) error {
+	return s.WriteRelationships(ctx, []*domain.RelationTuple{tuple}, nil)
+}
+
+// DeleteRelationship deletes a single relationship tuple
+func (s *RelationshipService) DeleteRelationship(ctx context.Context, tuple *domain.RelationTuple) error {
+	return s.WriteRelationships(ctx, nil, []*domain.RelationTuple{tuple})
+}
+
+// ListRelationships retrieves a paginated list of relationship tuples matching the filter
+func (s *RelationshipService) ListRelationships(ctx context.Context, filter domain.RelationTupleFilter, limit, offset int) ([]*domain.RelationTuple, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+	return s.tupleRepo.List(ctx, filter, limit, offset)
+}
+
+// CheckRelationship reports whether the subject holds the relation on the object, following at most depth hops.
+// When it does, the chain of tuples that proves it is returned.
+func (s *RelationshipService) CheckRelationship(ctx context.Context, object, relation, subject string, depth int) (bool, []*domain.RelationTuple, error) {
+	objectType, objectID, err := domain.ParseObject(object)
+	if err != nil {
+		return false, nil, err
+	}
+	subjectType, subjectID, subjectRelation, err := domain.ParseSubject(subject)
+	if err != nil {
+		return false, nil, err
+	}
+	if relation == "" {
+		return false, nil, fmt.Errorf("relation is required")
+	}
+	if depth <= 0 {
+		depth = DefaultCheckDepth
+	}
+
+	return s.checker.check(ctx, objectType, objectID, relation, subjectRef{
+		Type:     subjectType,
+		ID:       subjectID,
+		Relation: subjectRelation,
+	}, depth)
+}
+
+// findTuple retrieves a stored relationship tuple, nil when it does not exist
+func findTuple(ctx context.Context, tupleRepo domain.RelationTupleRepository, tuple *domain.RelationTuple) (*domain.RelationTuple, error) {
+	tuples, err := tupleRepo.List(ctx, domain.RelationTupleFilter{
+		ObjectType:      tuple.ObjectType,
+		ObjectID:        tuple.ObjectID,
+		Relation:        tuple.Relation,
+		SubjectType:     tuple.SubjectType,
+		SubjectID:       tuple.SubjectID,
+		SubjectRelation: &tuple.SubjectRelation,
+	}, 1, 0)
+	if err != nil || len(tuples) == 0 {
+		return nil, err
+	}
+	return tuples[0], nil
+}
+
+// RelationTupleRepository returns the relationship tuple repository
+func (s *RelationshipService) RelationTupleRepository() domain.RelationTupleRepository {
+	return s.tupleRepo
+}
+
+// subjectRef identifies the subject a relationship check is about
+type subjectRef struct {
+	Type     string
+	ID       string
+	Relation string
+}
+
+// relationChecker walks the relationship graph, applying the rewrite rules of the schema, to answer checks
+type relationChecker struct {
+	tupleRepo  domain.RelationTupleRepository
+	schemaRepo domain.RelationSchemaRepository
+}
+
+// newRelationChecker creates a relationChecker reading tuples and the schema from the repositories
+func newRelationChecker(tupleRepo domain.RelationTupleRepository, schemaRepo domain.RelationSchemaRepository) *relationChecker {
+	return &relationChecker{
+		tupleRepo:  tupleRepo,
+		schemaRepo: schemaRepo,
+	}
+}
+
+// check reports whether the subject holds the relation on the object, returning the chain of
+// tuples from the object to the subject when it does
+func (c *relationChecker) check(ctx context.Context, objectType, objectID, relation string, subject subjectRef, depth int) (bool, []*domain.RelationTuple, error) {
+	definition, err := latestSchema(ctx, c.schemaRepo)
+	if err != nil {
+		return false, nil, err
+	}
+
+	w := &relationWalk{
+		ctx:       ctx,
+		tupleRepo: c.tupleRepo,
+		schema:    definition,
+		subject:   subject,
+		visiting:  map[string]bool{},
+	}
+	found, path, err := w.relation(objectType, objectID, relation, depth)
+	if errors.Is(err, errRelationCycle) {
+		return false, nil, nil
+	}
+	return found, path, err
+}
+
+// relatedObjects returns the IDs of the objects of the given type the subject is related to, directly or
+// through the objects and usersets it is related to, following tuples up to depth hops. Every object
+// a check could find the subject from is returned, so checks only need to be made against those.
+func (c *relationChecker) relatedObjects(ctx context.Context, subject subjectRef, objectType string, depth int) ([]string, error) {
+	visited := map[subjectRef]bool{{Type: subject.Type, ID: subject.ID}: true}
+	frontier := []subjectRef{{Type: subject.Type, ID: subject.ID}}
+	found := map[string]bool{}
+	var ids []string
+
+	for ; depth > 0 && len(frontier) > 0; depth-- {
+		var next []subjectRef
+		for _, ref := range frontier {
+			tuples, err := c.tupleRepo.List(ctx, domain.RelationTupleFilter{SubjectType: ref.Type, SubjectID: ref.ID}, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			for _, tuple := range tuples {
+				if tuple.ObjectType == objectType && !found[tuple.ObjectID] {
+					found[tuple.ObjectID] = true
+					ids = append(ids, tuple.ObjectID)
+				}
+				object := subjectRef{Type: tuple.ObjectType, ID: tuple.ObjectID}
+				if !visited[object] {
+					visited[object] = true
+					next = append(next, object)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return ids, nil
+}
+
+// relatedSubjects returns the IDs of the subjects of the given type related to the object, directly or
+// through the objects and usersets it is related to, following tuples up to depth hops. Every subject
+// a check of the object could find is returned, so checks only need to be made for those.
+func (c *relationChecker) relatedSubjects(ctx context.Context, object subjectRef, subjectType string, depth int) ([]string, error) {
+	visited := map[subjectRef]bool{{Type: object.Type, ID: object.ID}: true}
+	frontier := []subjectRef{{Type: object.Type, ID: object.ID}}
+	found := map[string]bool{}
+	var ids []string
+
+	for ; depth > 0 && len(frontier) > 0; depth-- {
+		var next []subjectRef
+		for _, ref := range frontier {
+			tuples, err := c.tupleRepo.List(ctx, domain.RelationTupleFilter{ObjectType: ref.Type, ObjectID: ref.ID}, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			for _, tuple := range tuples {
+				if tuple.SubjectType == subjectType && tuple.SubjectRelation == "" && !found[tuple.SubjectID] {
+					found[tuple.SubjectID] = true
+					ids = append(ids, tuple.SubjectID)
+				}
+				subject := subjectRef{Type: tuple.SubjectType, ID: tuple.SubjectID}
+				if !visited[subject] {
+					visited[subject] = true
+					next = append(next, subject)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return ids, nil
+}
+
+// relationWalk holds the state of a single check
+type relationWalk struct {
+	ctx       context.Context
+	tupleRepo domain.RelationTupleRepository
+	schema    *schema.Schema
+	subject   subjectRef
+	// visiting holds the object relations on the current path, to break cycles
+	visiting map[string]bool
+}
+
+// relation reports whether the subject holds the relation on the object, applying the rewrite
+// the schema declares for it. Relations the schema does not declare are made of stored tuples only.
+func (w *relationWalk) relation(objectType, objectID, relation string, depth int) (bool, []*domain.RelationTuple, error) {
+	key := objectType + ":" + objectID + "#" + relation
+	if w.visiting[key] {
+		return false, nil, errRelationCycle
+	}
+	if depth <= 0 {
+		return false, nil, ErrCheckDepthExceeded
+	}
+	w.visiting[key] = true
+	defer delete(w.visiting, key)
+
+	rewrite := schema.Rewrite{Operator: schema.OperatorThis}
+	if w.schema != nil {
+		if definition, ok := w.schema.Relation(objectType, relation); ok {
+			rewrite = definition.Effective()
+		}
+	}
+
+	return w.rewrite(objectType, objectID, relation, rewrite, depth)
+}
+
+// rewrite evaluates a rewrite node of the relation on the object
+func (w *relationWalk) rewrite(objectType, objectID, relation string, rewrite schema.Rewrite, depth int) (bool, []*domain.RelationTuple, error) {
+	switch rewrite.Operator {
+	case schema.OperatorThis:
+		return w.direct(objectType, objectID, relation, depth)
+
+	case schema.OperatorComputedUserset:
+		return w.relation(objectType, objectID, rewrite.Relation, depth-1)
+
+	case schema.OperatorTupleToUserset:
+		tuples, err := w.tuples(objectType, objectID, rewrite.Tupleset)
+		if err != nil {
+			return false, nil, err
+		}
+		// Undecided tuples are only reported when no other tuple grants the relation
+		var pending error
+		for _, tuple := range tuples {
+			found, path, err := w.relation(tuple.SubjectType, tuple.SubjectID, rewrite.Relation, depth-1)
+			if err != nil {
+				if !undecided(err) {
+					return false, nil, err
+				}
+				pending = worseUndecided(pending, err)
+				continue
+			}
+			if found {
+				return true, append([]*domain.RelationTuple{tuple}, path...), nil
+			}
+		}
+		return false, nil, pending
+
+	case schema.OperatorUnion:
+		var pending error
+		for _, child := range rewrite.Children {
+			found, path, err := w.rewrite(objectType, objectID, relation, child, depth)
+			if err != nil {
+				if !undecided(err) {
+					return false, nil, err
+				}
+				pending = worseUndecided(pending, err)
+				continue
+			}
+			if found {
+				return true, path, nil
+			}
+		}
+		return false, nil, pending
+
+	case schema.OperatorIntersection:
+		// A child that does not hold decides the intersection even when another one is undecided
+		var paths []*domain.RelationTuple
+		var pending error
+		for _, child := range rewrite.Children {
+			found, path, err := w.rewrite(objectType, objectID, relation, child, depth)
+			if err != nil {
+				if !undecided(err) {
+					return false, nil, err
+				}
+				pending = worseUndecided(pending, err)
+				continue
+			}
+			if !found {
+				return false, nil, nil
+			}
+			paths = append(paths, path...)
+		}
+		if pending != nil {
+			return false, nil, pending
+		}
+		return true, paths, nil
+
+	case schema.OperatorExclusion:
+		found, path, err := w.rewrite(objectType, objectID, relation, rewrite.Children[0], depth)
+		if err != nil || !found {
+			return false, nil, err
+		}
+		// An undecided exclusion fails closed: its error is passed on, so that the relation is not
+		// held, rather than read as "not excluded"
+		excluded, _, err := w.rewrite(objectType, objectID, relation, rewrite.Children[1], depth)
+		if err != nil || excluded {
+			return false, nil, err
+		}
+		return true, path, nil
+	}
+
+	return false, nil, fmt.Errorf("unknown rewrite operator %q", rewrite.Operator)
+}
+
+// direct looks for the subject among the tuples stored for the relation, then in every userset they point at
+func (w *relationWalk) direct(objectType, objectID, relation string, depth int) (bool, []*domain.RelationTuple, error) {
+	tuples, err := w.tuples(objectType, objectID, relation)
+	if err != nil {
+		return false, nil, err
+	}
+
+	// Direct relationships first
+	for _, tuple := range tuples {
+		if tuple.SubjectType == w.subject.Type && tuple.SubjectID == w.subject.ID && tuple.SubjectRelation == w.subject.Relation {
+			return true, []*domain.RelationTuple{tuple}, nil
+		}
+	}
+
+	// Then every userset the relation points at, undecided ones only being reported when no other grants it
+	var pending error
+	for _, tuple := range tuples {
+		if tuple.SubjectRelation == "" {
+			continue
+		}
+		found, path, err := w.relation(tuple.SubjectType, tuple.SubjectID, tuple.SubjectRelation, depth-1)
+		if err != nil {
+			if !undecided(err) {
+				return false, nil, err
+			}
+			pending = worseUndecided(pending, err)
+			continue
+		}
+		if found {
+			return true, append([]*domain.RelationTuple{tuple}, path...), nil
+		}
+	}
+
+	return false, nil, pending
+}
+
+// tuples lists every tuple stored for the relation on the object
+func (w *relationWalk) tuples(objectType, objectID, relation string) ([]*domain.RelationTuple, error) {
+	return w.tupleRepo.List(w.ctx, domain.RelationTupleFilter{
+		ObjectType: objectType,
+		ObjectID:   objectID,
+		Relation:   relation,
+	}, 0, 0)
+}
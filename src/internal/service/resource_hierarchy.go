@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// maxResourceDepth bounds the walk up the resource tree, guarding against cycles left in the data
+const maxResourceDepth = 32
+
+// resourceAncestors returns the ancestors of a resource, nearest first. With inheritedOnly set the walk
+// stops at the first resource blocking inheritance, so only the ancestors whose permissions apply to
+// the resource are returned.
+func resourceAncestors(ctx context.Context, resourceRepo domain.ResourceRepository, resource *domain.Resource, inheritedOnly bool) ([]*domain.Resource, error) {
+	ancestors := []*domain.Resource{}
+	visited := map[string]bool{resource.ID: true}
+
+	for current := resource; current.ParentID != nil; {
+		if inheritedOnly && current.BlockInheritance {
+			break
+		}
+		if visited[*current.ParentID] || len(ancestors) == maxResourceDepth {
+			return nil, fmt.Errorf("resource hierarchy of %s is too deep or cyclic", resource.ID)
+		}
+
+		parent, err := resourceRepo.GetByID(ctx, *current.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.DeletedAt != nil {
+			break
+		}
+		visited[parent.ID] = true
+		ancestors = append(ancestors, parent)
+		current = parent
+	}
+
+	return ancestors, nil
+}
+
+// inheritance returns the ancestors the resource inherits permissions from together with their
+// actions of the same name as the action checked, reusing what the lookups already hold
+func (s *PermissionService) inheritance(ctx context.Context, lookups *checkLookups, resource *domain.Resource, action *domain.Action) ([]*domain.Resource, []*domain.Action, error) {
+	ancestors, ok := lookups.ancestors[resource.ID]
+	if !ok {
+		var err error
+		ancestors, err = resourceAncestors(ctx, s.resourceRepo, resource, true)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	actions := []*domain.Action{}
+	for _, ancestor := range ancestors {
+		ancestorActions, ok := lookups.actions[ancestor.ID]
+		if !ok {
+			var err error
+			ancestorActions, err = s.actionRepo.GetByResourceID(ctx, ancestor.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		for _, a := range ancestorActions {
+			if a.Name == action.Name {
+				actions = append(actions, a)
+			}
+		}
+	}
+
+	return ancestors, actions, nil
+}
@@ -10,12 +10,14 @@ import (
 // ResourceService handles business logic for resources
 type ResourceService struct {
 	resourceRepo domain.ResourceRepository
+	revisions    *RevisionService
 }
 
 // NewResourceService creates a new ResourceService
-func NewResourceService(resourceRepo domain.ResourceRepository) *ResourceService {
+func NewResourceService(resourceRepo domain.ResourceRepository, revisions *RevisionService) *ResourceService {
 	return &ResourceService{
 		resourceRepo: resourceRepo,
+		revisions:    revisions,
 	}
 }
 
@@ -24,8 +26,21 @@ func (s *ResourceService) CreateResource(ctx context.Context, resource *domain.R
 	if resource.Name == "" {
 		return fmt.Errorf("resource name is required")
 	}
+	if err := validateResourcePolicy(resource); err != nil {
+		return err
+	}
+	if resource.ParentID != nil {
+		if _, err := s.getResource(ctx, *resource.ParentID); err != nil {
+			return fmt.Errorf("parent resource not found")
+		}
+	}
 
-	return s.resourceRepo.Create(ctx, resource)
+	return s.revisions.Commit(ctx, "create resource", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		if err := s.resourceRepo.Create(ctx, resource); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityResource, resource.ID, nil, bundleResource(resource)), nil
+	})
 }
 
 // GetResourceByID retrieves a resource by ID
@@ -49,16 +64,123 @@ func (s *ResourceService) UpdateResource(ctx context.Context, resource *domain.R
 	if resource.Name == "" {
 		return fmt.Errorf("resource name is required")
 	}
+	if err := validateResourcePolicy(resource); err != nil {
+		return err
+	}
+
+	return s.revisions.Commit(ctx, "update resource", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		before, _ := s.resourceRepo.GetByID(ctx, resource.ID)
+		if err := s.resourceRepo.Update(ctx, resource); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityResource, resource.ID, entityOf(before, bundleResource), bundleResource(resource)), nil
+	})
+}
+
+// MoveResource nests a resource under a new parent, or makes it a root resource when parentID is nil.
+// A resource cannot be moved under itself or one of its descendants.
+func (s *ResourceService) MoveResource(ctx context.Context, id string, parentID *string) (*domain.Resource, error) {
+	resource, err := s.getResource(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("resource not found")
+	}
+
+	if parentID != nil {
+		parent, err := s.getResource(ctx, *parentID)
+		if err != nil {
+			return nil, fmt.Errorf("parent resource not found")
+		}
+		ancestors, err := resourceAncestors(ctx, s.resourceRepo, parent, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range append(ancestors, parent) {
+			if r.ID == resource.ID {
+				return nil, fmt.Errorf("resource cannot be moved under itself or its descendants")
+			}
+		}
+	}
+
+	before := bundleResource(resource)
+	resource.ParentID = parentID
+	err = s.revisions.Commit(ctx, "move resource", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		if err := s.resourceRepo.Update(ctx, resource); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityResource, resource.ID, before, bundleResource(resource)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// ListChildResources retrieves a paginated list of the resources nested directly under a resource
+func (s *ResourceService) ListChildResources(ctx context.Context, id string, limit, offset int) ([]*domain.Resource, error) {
+	if _, err := s.getResource(ctx, id); err != nil {
+		return nil, fmt.Errorf("resource not found")
+	}
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+	return s.resourceRepo.ListChildren(ctx, id, limit, offset)
+}
 
-	return s.resourceRepo.Update(ctx, resource)
+// ListResourceAncestors retrieves the ancestors of a resource, nearest first.
+// Ancestors above a resource blocking inheritance are listed too.
+func (s *ResourceService) ListResourceAncestors(ctx context.Context, id string) ([]*domain.Resource, error) {
+	resource, err := s.getResource(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("resource not found")
+	}
+	return resourceAncestors(ctx, s.resourceRepo, resource, false)
 }
 
 // DeleteResource deletes a resource by ID
 func (s *ResourceService) DeleteResource(ctx context.Context, id string) (*domain.Resource, error) {
-	return s.resourceRepo.Delete(ctx, id)
+	var resource *domain.Resource
+	err := s.revisions.Commit(ctx, "delete resource", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		var err error
+		resource, err = s.resourceRepo.Delete(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityResource, resource.ID, bundleResource(resource), nil), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
 }
 
 // ResourceRepository returns the resource repository
 func (s *ResourceService) ResourceRepository() domain.ResourceRepository {
 	return s.resourceRepo
 }
+
+// getResource retrieves a resource that has not been deleted
+func (s *ResourceService) getResource(ctx context.Context, id string) (*domain.Resource, error) {
+	resource, err := s.resourceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if resource.DeletedAt != nil {
+		return nil, fmt.Errorf("resource not found")
+	}
+	return resource, nil
+}
+
+// validateResourcePolicy checks the combining policy overrides of a resource
+func validateResourcePolicy(resource *domain.Resource) error {
+	if resource.CombiningAlgorithm != "" {
+		if err := domain.ValidateCombiningAlgorithm(resource.CombiningAlgorithm); err != nil {
+			return err
+		}
+	}
+	if resource.DefaultEffect != "" {
+		if err := domain.ValidateDefaultEffect(resource.DefaultEffect); err != nil {
+			return err
+		}
+	}
+	return nil
+}
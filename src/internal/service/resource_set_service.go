@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/condition"
+)
+
+// resourceSetPageSize is the page size used when every resource set has to be evaluated
+const resourceSetPageSize = 100
+
+// ResourceSetService handles business logic for resource sets
+type ResourceSetService struct {
+	resourceSetRepo domain.ResourceSetRepository
+	resourceRepo    domain.ResourceRepository
+	actionRepo      domain.ActionRepository
+	revisions       *RevisionService
+}
+
+// NewResourceSetService creates a new ResourceSetService
+func NewResourceSetService(resourceSetRepo domain.ResourceSetRepository, resourceRepo domain.ResourceRepository, actionRepo domain.ActionRepository, revisions *RevisionService) *ResourceSetService {
+	return &ResourceSetService{
+		resourceSetRepo: resourceSetRepo,
+		resourceRepo:    resourceRepo,
+		actionRepo:      actionRepo,
+		revisions:       revisions,
+	}
+}
+
+// CreateResourceSet creates a new resource set
+func (s *ResourceSetService) CreateResourceSet(ctx context.Context, resourceSet *domain.ResourceSet) error {
+	if err := validateResourceSet(resourceSet); err != nil {
+		return err
+	}
+
+	return s.revisions.Commit(ctx, "create resource set", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		if err := s.resourceSetRepo.Create(ctx, resourceSet); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityResourceSet, resourceSet.ID, nil, bundleResourceSet(resourceSet)), nil
+	})
+}
+
+// GetResourceSetByID retrieves a resource set by ID
+func (s *ResourceSetService) GetResourceSetByID(ctx context.Context, id string) (*domain.ResourceSet, error) {
+	return s.resourceSetRepo.GetByID(ctx, id)
+}
+
+// ListResourceSets retrieves a paginated list of resource sets
+func (s *ResourceSetService) ListResourceSets(ctx context.Context, limit, offset int) ([]*domain.ResourceSet, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+	return s.resourceSetRepo.List(ctx, limit, offset)
+}
+
+// UpdateResourceSet updates an existing resource set
+func (s *ResourceSetService) UpdateResourceSet(ctx context.Context, resourceSet *domain.ResourceSet) error {
+	if resourceSet.ID == "" {
+		return fmt.Errorf("resource set ID is required")
+	}
+	if err := validateResourceSet(resourceSet); err != nil {
+		return err
+	}
+
+	return s.revisions.Commit(ctx, "update resource set", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		before, _ := s.resourceSetRepo.GetByID(ctx, resourceSet.ID)
+		if err := s.resourceSetRepo.Update(ctx, resourceSet); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityResourceSet, resourceSet.ID, entityOf(before, bundleResourceSet), bundleResourceSet(resourceSet)), nil
+	})
+}
+
+// DeleteResourceSet deletes a resource set by ID
+func (s *ResourceSetService) DeleteResourceSet(ctx context.Context, id string) (*domain.ResourceSet, error) {
+	var resourceSet *domain.ResourceSet
+	err := s.revisions.Commit(ctx, "delete resource set", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		var err error
+		resourceSet, err = s.resourceSetRepo.Delete(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityResourceSet, resourceSet.ID, bundleResourceSet(resourceSet), nil), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resourceSet, nil
+}
+
+// IsMember reports whether a resource, and optionally one of its actions, satisfies the conditions of a resource set
+func (s *ResourceSetService) IsMember(ctx context.Context, resourceSetID, resourceID, actionID string) (bool, error) {
+	resourceSet, err := s.resourceSetRepo.GetByID(ctx, resourceSetID)
+	if err != nil {
+		return false, fmt.Errorf("resource set not found")
+	}
+	resource, err := s.resourceRepo.GetByID(ctx, resourceID)
+	if err != nil {
+		return false, fmt.Errorf("resource not found")
+	}
+
+	var action *domain.Action
+	if actionID != "" {
+		action, err = s.actionRepo.GetByID(ctx, actionID)
+		if err != nil || action.ResourceID != resource.ID {
+			return false, fmt.Errorf("action not found")
+		}
+	}
+
+	return isResourceSetMember(resourceSet, resource, action)
+}
+
+// ResourceSetRepository returns the resource set repository
+func (s *ResourceSetService) ResourceSetRepository() domain.ResourceSetRepository {
+	return s.resourceSetRepo
+}
+
+// validateResourceSet checks the required fields and the condition grammar of a resource set
+func validateResourceSet(resourceSet *domain.ResourceSet) error {
+	if resourceSet.Name == "" {
+		return fmt.Errorf("resource set name is required")
+	}
+
+	conditions, err := condition.Parse(resourceSet.Conditions)
+	if err != nil {
+		return err
+	}
+	if conditions == nil {
+		return fmt.Errorf("invalid conditions: conditions are required")
+	}
+
+	return nil
+}
+
+// resourceSetAttributes builds the attributes resource set conditions are evaluated against.
+// The attributes of the resource are available under "resource" and those of the action
+// under "action", each together with the "id" and "name" of the entity.
+func resourceSetAttributes(resource *domain.Resource, action *domain.Action) map[string]interface{} {
+	resourceAttributes := condition.Attributes(resource.Attributes)
+	resourceAttributes["id"] = resource.ID
+	resourceAttributes["name"] = resource.Name
+
+	attributes := map[string]interface{}{
+		"resource": resourceAttributes,
+	}
+
+	if action != nil {
+		actionAttributes := condition.Attributes(action.Attributes)
+		actionAttributes["id"] = action.ID
+		actionAttributes["name"] = action.Name
+		attributes["action"] = actionAttributes
+	}
+
+	return attributes
+}
+
+// isResourceSetMember evaluates the conditions of a resource set against a resource and action
+func isResourceSetMember(resourceSet *domain.ResourceSet, resource *domain.Resource, action *domain.Action) (bool, error) {
+	conditions, err := condition.Parse(resourceSet.Conditions)
+	if err != nil {
+		return false, err
+	}
+	if conditions == nil {
+		return false, nil
+	}
+
+	return conditions.Evaluate(resourceSetAttributes(resource, action)), nil
+}
+
+// listResourceSets returns every resource set
+func listResourceSets(ctx context.Context, resourceSetRepo domain.ResourceSetRepository) ([]*domain.ResourceSet, error) {
+	var all []*domain.ResourceSet
+	for offset := 0; ; offset += resourceSetPageSize {
+		resourceSets, err := resourceSetRepo.List(ctx, resourceSetPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, resourceSets...)
+
+		if len(resourceSets) < resourceSetPageSize {
+			return all, nil
+		}
+	}
+}
+
+// filterResourceSets returns the resource sets the resource and action are a member of
+func filterResourceSets(resourceSets []*domain.ResourceSet, resource *domain.Resource, action *domain.Action) []*domain.ResourceSet {
+	var matched []*domain.ResourceSet
+	for _, resourceSet := range resourceSets {
+		member, err := isResourceSetMember(resourceSet, resource, action)
+		if err != nil {
+			// A resource set with broken conditions never matches
+			continue
+		}
+		if member {
+			matched = append(matched, resourceSet)
+		}
+	}
+	return matched
+}
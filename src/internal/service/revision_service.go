@@ -0,0 +1,333 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/tenant"
+)
+
+// revisionPageSize is the page size used to read the revisions following a revision
+const revisionPageSize = 100
+
+// watchPollInterval is how often watches look for the revisions committed by other instances
+const watchPollInterval = 5 * time.Second
+
+// RevisionListener is told about the revisions of a committed transaction. The context carries the
+// tenant the revisions belong to.
+type RevisionListener func(ctx context.Context, revisions []*domain.Revision)
+
+// revisionTxKey is the context key of the revisions recorded in the transaction of a context
+type revisionTxKey struct{}
+
+// pendingRevisions holds the revisions recorded in a transaction until it commits
+type pendingRevisions struct {
+	revisions []*domain.Revision
+}
+
+// revisionTrackerKey is the context key of the RevisionTracker of a context
+type revisionTrackerKey struct{}
+
+// RevisionTracker remembers the latest revision committed by the operations made with a context
+type RevisionTracker struct {
+	mu     sync.Mutex
+	latest int64
+}
+
+// TrackRevisions returns a copy of ctx whose committed revisions are remembered by the returned tracker
+func TrackRevisions(ctx context.Context) (context.Context, *RevisionTracker) {
+	tracker := &RevisionTracker{}
+	return context.WithValue(ctx, revisionTrackerKey{}, tracker), tracker
+}
+
+// Latest returns the ID of the latest revision committed, 0 when none was
+func (t *RevisionTracker) Latest() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.latest
+}
+
+// RevisionService records every change made to the authorization data as a revision
+type RevisionService struct {
+	transactor   domain.Transactor
+	revisionRepo domain.RevisionRepository
+
+	mu           sync.RWMutex
+	listeners    map[int]RevisionListener
+	nextListener int
+}
+
+// NewRevisionService creates a new RevisionService
+func NewRevisionService(transactor domain.Transactor, revisionRepo domain.RevisionRepository) *RevisionService {
+	return &RevisionService{
+		transactor:   transactor,
+		revisionRepo: revisionRepo,
+		listeners:    make(map[int]RevisionListener),
+	}
+}
+
+// InTransaction implements domain.Transactor. The revisions recorded in the transaction are announced
+// to the listeners once it commits, so services running their writes in their own transactions are
+// given the revision service as their transactor.
+func (s *RevisionService) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inRevisionTransaction(ctx) {
+		return s.transactor.InTransaction(ctx, fn)
+	}
+
+	pending := &pendingRevisions{}
+	if err := s.transactor.InTransaction(context.WithValue(ctx, revisionTxKey{}, pending), fn); err != nil {
+		return err
+	}
+	s.announce(ctx, pending.revisions)
+	return nil
+}
+
+// inRevisionTransaction reports whether ctx belongs to a transaction that may not be committed yet
+func inRevisionTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(revisionTxKey{}).(*pendingRevisions)
+	return ok
+}
+
+// Subscribe registers a listener told about every committed revision until unsubscribe is called
+func (s *RevisionService) Subscribe(listener RevisionListener) (unsubscribe func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextListener
+	s.nextListener++
+	s.listeners[id] = listener
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.listeners, id)
+	}
+}
+
+// announce tells the listeners about committed revisions
+func (s *RevisionService) announce(ctx context.Context, revisions []*domain.Revision) {
+	if len(revisions) == 0 {
+		return
+	}
+
+	if tracker, ok := ctx.Value(revisionTrackerKey{}).(*RevisionTracker); ok {
+		tracker.mu.Lock()
+		for _, revision := range revisions {
+			if revision.ID > tracker.latest {
+				tracker.latest = revision.ID
+			}
+		}
+		tracker.mu.Unlock()
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, listener := range s.listeners {
+		listener(ctx, revisions)
+	}
+}
+
+// Commit runs fn in a transaction and records the changes it returns as a new revision in that same
+// transaction, so that no change is ever made without its revision
+func (s *RevisionService) Commit(ctx context.Context, description string, fn func(ctx context.Context) ([]domain.RevisionChange, error)) error {
+	return s.InTransaction(ctx, func(ctx context.Context) error {
+		changes, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = s.Record(ctx, description, changes...)
+		return err
+	})
+}
+
+// Record stores changes as a new revision. Callers making several writes should run them and Record
+// in a single transaction started with InTransaction. Nothing is stored, and nil is returned, when there is no change.
+func (s *RevisionService) Record(ctx context.Context, description string, changes ...domain.RevisionChange) (*domain.Revision, error) {
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	revision := &domain.Revision{
+		Description: description,
+		Changes:     changes,
+	}
+	if err := s.revisionRepo.Create(ctx, revision); err != nil {
+		return nil, err
+	}
+
+	// A revision recorded outside a transaction is committed already
+	if pending, ok := ctx.Value(revisionTxKey{}).(*pendingRevisions); ok {
+		pending.revisions = append(pending.revisions, revision)
+	} else {
+		s.announce(ctx, []*domain.Revision{revision})
+	}
+	return revision, nil
+}
+
+// ListRevisions retrieves a paginated list of revisions, latest first
+func (s *RevisionService) ListRevisions(ctx context.Context, limit, offset int) ([]*domain.Revision, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+	return s.revisionRepo.List(ctx, limit, offset)
+}
+
+// GetRevision retrieves a revision together with its changes
+func (s *RevisionService) GetRevision(ctx context.Context, id int64) (*domain.Revision, error) {
+	revision, err := s.revisionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("revision not found")
+	}
+	return revision, nil
+}
+
+// LatestRevisionID returns the ID of the latest revision of the tenant, 0 when there is none
+func (s *RevisionService) LatestRevisionID(ctx context.Context) (int64, error) {
+	revisions, err := s.revisionRepo.List(ctx, 1, 0)
+	if err != nil {
+		return 0, err
+	}
+	if len(revisions) == 0 {
+		return 0, nil
+	}
+	return revisions[0].ID, nil
+}
+
+// Watch calls fn with every revision of the tenant following the revision after, oldest first, and
+// then with the revisions committed from then on, until ctx is done or fn fails. Only the changes to
+// entities of the given types are kept, all of them when none is given, and revisions left without
+// changes are skipped. Revisions committed through this instance are delivered as soon as they are,
+// the others are polled for.
+func (s *RevisionService) Watch(ctx context.Context, after int64, entityTypes []string, fn func(revision *domain.Revision) error) error {
+	tenantID := tenant.FromContext(ctx)
+	wake := make(chan struct{}, 1)
+	unsubscribe := s.Subscribe(func(ctx context.Context, _ []*domain.Revision) {
+		if tenant.FromContext(ctx) != tenantID {
+			return
+		}
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			revisions, err := s.revisionRepo.ListAfter(ctx, after, revisionPageSize)
+			if err != nil {
+				return err
+			}
+			for _, revision := range revisions {
+				after = revision.ID
+				if revision = filterRevision(revision, entityTypes); revision == nil {
+					continue
+				}
+				if err := fn(revision); err != nil {
+					return err
+				}
+			}
+			if len(revisions) < revisionPageSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// filterRevision returns the revision with only the changes to entities of the given types, nil when
+// none is left. Every change is kept when no type is given.
+func filterRevision(revision *domain.Revision, entityTypes []string) *domain.Revision {
+	if len(entityTypes) == 0 {
+		return revision
+	}
+
+	var changes []domain.RevisionChange
+	for _, change := range revision.Changes {
+		if slices.Contains(entityTypes, change.EntityType) {
+			changes = append(changes, change)
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	filtered := *revision
+	filtered.Changes = changes
+	return &filtered
+}
+
+// changesAfter returns the changes recorded by the revisions following a revision, oldest first
+func (s *RevisionService) changesAfter(ctx context.Context, id int64) ([]domain.RevisionChange, error) {
+	if _, err := s.GetRevision(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var changes []domain.RevisionChange
+	for after := id; ; {
+		revisions, err := s.revisionRepo.ListAfter(ctx, after, revisionPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, revision := range revisions {
+			changes = append(changes, revision.Changes...)
+			after = revision.ID
+		}
+		if len(revisions) < revisionPageSize {
+			return changes, nil
+		}
+	}
+}
+
+// entityChange describes the change of an entity from one state to another, given in bundle form.
+// A nil state stands for an entity that does not exist, so a nil before is a create and a nil after a delete.
+func entityChange(entityType, entityID string, before, after interface{}) domain.RevisionChange {
+	change := domain.RevisionChange{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Operation:  domain.OperationUpdate,
+	}
+	if before == nil {
+		change.Operation = domain.OperationCreate
+	} else {
+		change.Before, _ = json.Marshal(before)
+	}
+	if after == nil {
+		change.Operation = domain.OperationDelete
+	} else {
+		change.After, _ = json.Marshal(after)
+	}
+	return change
+}
+
+// entityChanges returns the change of an entity as a list, empty when both states are equal
+func entityChanges(entityType, entityID string, before, after interface{}) []domain.RevisionChange {
+	change := entityChange(entityType, entityID, before, after)
+	if change.Operation == domain.OperationUpdate && bytes.Equal(change.Before, change.After) {
+		return nil
+	}
+	return []domain.RevisionChange{change}
+}
+
+// entityOf converts an optional entity to its bundle form, nil when the entity does not exist
+func entityOf[E, B any](entity *E, convert func(*E) B) interface{} {
+	if entity == nil {
+		return nil
+	}
+	return convert(entity)
+}
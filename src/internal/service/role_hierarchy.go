@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// expandRoles returns the given roles followed by every role they inherit from,
+// walking the hierarchy breadth first and skipping roles that were already visited
+func expandRoles(ctx context.Context, roleRepo domain.RoleRepository, roles []*domain.Role) ([]*domain.Role, error) {
+	visited := make(map[string]bool, len(roles))
+	expanded := make([]*domain.Role, 0, len(roles))
+	queue := make([]*domain.Role, 0, len(roles))
+
+	for _, role := range roles {
+		if !visited[role.ID] {
+			visited[role.ID] = true
+			expanded = append(expanded, role)
+			queue = append(queue, role)
+		}
+	}
+
+	for len(queue) > 0 {
+		role := queue[0]
+		queue = queue[1:]
+
+		parents, err := roleRepo.ListParents(ctx, role.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			if visited[parent.ID] {
+				continue
+			}
+			visited[parent.ID] = true
+			expanded = append(expanded, parent)
+			queue = append(queue, parent)
+		}
+	}
+
+	return expanded, nil
+}
+
+// rolePath returns the chain of roles leading from one of the given roles to the role with the given ID,
+// following the hierarchy breadth first. It returns nil when the role cannot be reached.
+func rolePath(ctx context.Context, roleRepo domain.RoleRepository, roles []*domain.Role, roleID string) ([]*domain.Role, error) {
+	// reachedFrom maps every visited role to the role it was reached from, nil for the given roles
+	reachedFrom := make(map[string]*domain.Role, len(roles))
+	byID := make(map[string]*domain.Role, len(roles))
+	queue := make([]*domain.Role, 0, len(roles))
+
+	for _, role := range roles {
+		if _, ok := byID[role.ID]; !ok {
+			byID[role.ID] = role
+			reachedFrom[role.ID] = nil
+			queue = append(queue, role)
+		}
+	}
+
+	for len(queue) > 0 {
+		role := queue[0]
+		queue = queue[1:]
+
+		if role.ID == roleID {
+			var path []*domain.Role
+			for current := role; current != nil; current = reachedFrom[current.ID] {
+				path = append([]*domain.Role{current}, path...)
+			}
+			return path, nil
+		}
+
+		parents, err := roleRepo.ListParents(ctx, role.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			if _, ok := byID[parent.ID]; ok {
+				continue
+			}
+			byID[parent.ID] = parent
+			reachedFrom[parent.ID] = role
+			queue = append(queue, parent)
+		}
+	}
+
+	return nil, nil
+}
@@ -5,17 +5,22 @@ import (
 	"fmt"
 
 	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/bundle"
 )
 
 // RoleService handles business logic for roles
 type RoleService struct {
-	roleRepo domain.RoleRepository
+	roleRepo       domain.RoleRepository
+	permissionRepo domain.PermissionRepository
+	revisions      *RevisionService
 }
 
 // NewRoleService creates a new RoleService
-func NewRoleService(roleRepo domain.RoleRepository) *RoleService {
+func NewRoleService(roleRepo domain.RoleRepository, permissionRepo domain.PermissionRepository, revisions *RevisionService) *RoleService {
 	return &RoleService{
-		roleRepo: roleRepo,
+		roleRepo:       roleRepo,
+		permissionRepo: permissionRepo,
+		revisions:      revisions,
 	}
 }
 
@@ -25,7 +30,12 @@ func (s *RoleService) CreateRole(ctx context.Context, role *domain.Role) error {
 		return fmt.Errorf("role name is required")
 	}
 
-	return s.roleRepo.Create(ctx, role)
+	return s.revisions.Commit(ctx, "create role", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		if err := s.roleRepo.Create(ctx, role); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityRole, role.ID, nil, bundleRole(role)), nil
+	})
 }
 
 // GetRoleByID retrieves a role by ID
@@ -48,17 +58,149 @@ func (s *RoleService) UpdateRole(ctx context.Context, role *domain.Role) error {
 	}
 
 	// Check if the role exists
-	_, err := s.roleRepo.GetByID(ctx, role.ID)
+	before, err := s.roleRepo.GetByID(ctx, role.ID)
 	if err != nil {
 		return fmt.Errorf("role not found")
 	}
 
-	return s.roleRepo.Update(ctx, role)
+	return s.revisions.Commit(ctx, "update role", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		if err := s.roleRepo.Update(ctx, role); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityRole, role.ID, bundleRole(before), bundleRole(role)), nil
+	})
 }
 
 // DeleteRole deletes a role by ID
 func (s *RoleService) DeleteRole(ctx context.Context, id string) (*domain.Role, error) {
-	return s.roleRepo.Delete(ctx, id)
+	var role *domain.Role
+	err := s.revisions.Commit(ctx, "delete role", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		var err error
+		role, err = s.roleRepo.Delete(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityRole, role.ID, bundleRole(role), nil), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// AddParentRole makes a role inherit every grant of a parent role
+func (s *RoleService) AddParentRole(ctx context.Context, roleID, parentID string) (*domain.RoleParent, error) {
+	if parentID == "" {
+		return nil, fmt.Errorf("parent role ID is required")
+	}
+
+	role, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("role not found")
+	}
+	parent, err := s.roleRepo.GetByID(ctx, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("parent role not found")
+	}
+
+	roleParent := &domain.RoleParent{
+		RoleID:   role.ID,
+		ParentID: parent.ID,
+	}
+	err = s.revisions.Commit(ctx, "add parent role", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		// The hierarchy is checked and changed under a lock, so that concurrent links cannot form a cycle
+		if err := s.roleRepo.LockHierarchy(ctx); err != nil {
+			return nil, err
+		}
+
+		// Reject links that would make the role inherit from itself
+		ancestors, err := expandRoles(ctx, s.roleRepo, []*domain.Role{parent})
+		if err != nil {
+			return nil, err
+		}
+		for _, ancestor := range ancestors {
+			if ancestor.ID == role.ID {
+				return nil, fmt.Errorf("role hierarchy cycle detected")
+			}
+		}
+
+		// Check if the link already exists
+		parents, err := s.roleRepo.ListParents(ctx, role.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range parents {
+			if p.ID == parent.ID {
+				return nil, fmt.Errorf("parent role already assigned")
+			}
+		}
+
+		if err := s.roleRepo.AddParent(ctx, roleParent); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityRoleParent, bundle.RoleParentKey(role.ID, parent.ID), nil, bundleRoleParent(roleParent)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return roleParent, nil
+}
+
+// RemoveParentRole stops a role from inheriting the grants of a parent role
+func (s *RoleService) RemoveParentRole(ctx context.Context, roleID, parentID string) (*domain.RoleParent, error) {
+	if parentID == "" {
+		return nil, fmt.Errorf("parent role ID is required")
+	}
+
+	var roleParent *domain.RoleParent
+	err := s.revisions.Commit(ctx, "remove parent role", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		var err error
+		roleParent, err = s.roleRepo.RemoveParent(ctx, roleID, parentID)
+		if err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityRoleParent, bundle.RoleParentKey(roleID, parentID), bundleRoleParent(roleParent), nil), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return roleParent, nil
+}
+
+// ListParentRoles retrieves the roles a role directly inherits from
+func (s *RoleService) ListParentRoles(ctx context.Context, roleID string) ([]*domain.Role, error) {
+	if _, err := s.roleRepo.GetByID(ctx, roleID); err != nil {
+		return nil, fmt.Errorf("role not found")
+	}
+
+	return s.roleRepo.ListParents(ctx, roleID)
+}
+
+// GetEffectiveRole retrieves a role together with every role it inherits from,
+// directly or transitively, and the permissions granted through all of them
+func (s *RoleService) GetEffectiveRole(ctx context.Context, roleID string) (*domain.Role, []*domain.Role, []*domain.Permission, error) {
+	role, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("role not found")
+	}
+
+	roles, err := expandRoles(ctx, s.roleRepo, []*domain.Role{role})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	roleIDs := make([]string, len(roles))
+	for i, r := range roles {
+		roleIDs[i] = r.ID
+	}
+	permissions, err := s.permissionRepo.ListBySubjects(ctx, domain.PermissionSubjects{RoleIDs: roleIDs})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// The first expanded role is the role itself
+	return role, roles[1:], permissions, nil
 }
 
 // RoleRepository returns the role repository
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/schema"
+)
+
+// SchemaService handles business logic for relation schemas
+type SchemaService struct {
+	schemaRepo domain.RelationSchemaRepository
+	revisions  *RevisionService
+}
+
+// NewSchemaService creates a new SchemaService
+func NewSchemaService(schemaRepo domain.RelationSchemaRepository, revisions *RevisionService) *SchemaService {
+	return &SchemaService{
+		schemaRepo: schemaRepo,
+		revisions:  revisions,
+	}
+}
+
+// WriteSchema validates a schema definition and stores it as a new version
+func (s *SchemaService) WriteSchema(ctx context.Context, definition []byte) (*domain.RelationSchema, error) {
+	if _, err := schema.Parse(definition); err != nil {
+		return nil, err
+	}
+
+	relationSchema := &domain.RelationSchema{
+		Definition: definition,
+	}
+	err := s.revisions.Commit(ctx, "write schema", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		// The schema is a single entity whose versions are its successive states
+		previous, err := s.schemaRepo.GetLatest(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.schemaRepo.Create(ctx, relationSchema); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntitySchema, domain.EntitySchema, entityOf(previous, bundleSchema), bundleSchema(relationSchema)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return relationSchema, nil
+}
+
+// GetSchema retrieves a schema version
+func (s *SchemaService) GetSchema(ctx context.Context, version int) (*domain.RelationSchema, error) {
+	relationSchema, err := s.schemaRepo.GetByVersion(ctx, version)
+	if err != nil {
+		return nil, fmt.Errorf("schema not found")
+	}
+	return relationSchema, nil
+}
+
+// GetLatestSchema retrieves the schema version currently in use
+func (s *SchemaService) GetLatestSchema(ctx context.Context) (*domain.RelationSchema, error) {
+	relationSchema, err := s.schemaRepo.GetLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if relationSchema == nil {
+		return nil, fmt.Errorf("schema not found")
+	}
+	return relationSchema, nil
+}
+
+// ListSchemas retrieves a paginated list of schema versions, latest first
+func (s *SchemaService) ListSchemas(ctx context.Context, limit, offset int) ([]*domain.RelationSchema, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+	return s.schemaRepo.List(ctx, limit, offset)
+}
+
+// RelationSchemaRepository returns the relation schema repository
+func (s *SchemaService) RelationSchemaRepository() domain.RelationSchemaRepository {
+	return s.schemaRepo
+}
+
+// latestSchema loads and parses the schema version currently in use.
+// It returns nil when no schema has been written.
+func latestSchema(ctx context.Context, schemaRepo domain.RelationSchemaRepository) (*schema.Schema, error) {
+	relationSchema, err := schemaRepo.GetLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if relationSchema == nil {
+		return nil, nil
+	}
+
+	return schema.Parse(relationSchema.Definition)
+}
+
+// validateTupleSchema checks a tuple against the schema. Tuples on object types the schema
+// does not declare are accepted as they are.
+func validateTupleSchema(definition *schema.Schema, tuple *domain.RelationTuple) error {
+	if definition == nil || !definition.HasType(tuple.ObjectType) {
+		return nil
+	}
+
+	relation, ok := definition.Relation(tuple.ObjectType, tuple.Relation)
+	if !ok {
+		return fmt.Errorf("invalid relationship %s: relation %q is not declared on type %q", tuple, tuple.Relation, tuple.ObjectType)
+	}
+	if !relation.AllowsDirect() {
+		return fmt.Errorf("invalid relationship %s: relation %q is computed and cannot be written", tuple, tuple.Relation)
+	}
+
+	if tuple.SubjectRelation != "" && definition.HasType(tuple.SubjectType) {
+		if _, ok := definition.Relation(tuple.SubjectType, tuple.SubjectRelation); !ok {
+			return fmt.Errorf("invalid relationship %s: relation %q is not declared on type %q", tuple, tuple.SubjectRelation, tuple.SubjectType)
+		}
+	}
+
+	return nil
+}
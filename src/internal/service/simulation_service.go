@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// maxSimulationChanges is the maximum number of changes a single simulation can propose
+const maxSimulationChanges = 100
+
+// errSimulated rolls back the transaction a simulation is run in
+var errSimulated = errors.New("simulated")
+
+// SimulationService decides checks as if proposed changes had been made, without making them
+type SimulationService struct {
+	transactor        domain.Transactor
+	permissionService *PermissionService
+	roleService       *RoleService
+	userRoleService   *UserRoleService
+	userService       *UserService
+	resourceService   *ResourceService
+	actionService     *ActionService
+}
+
+// NewSimulationService creates a new SimulationService
+func NewSimulationService(
+	transactor domain.Transactor,
+	permissionService *PermissionService,
+	roleService *RoleService,
+	userRoleService *UserRoleService,
+	userService *UserService,
+	resourceService *ResourceService,
+	actionService *ActionService,
+) *SimulationService {
+	return &SimulationService{
+		transactor:        transactor,
+		permissionService: permissionService,
+		roleService:       roleService,
+		userRoleService:   userRoleService,
+		userService:       userService,
+		resourceService:   resourceService,
+		actionService:     actionService,
+	}
+}
+
+// Simulate decides the checks, makes the proposed changes and decides the checks again. The changes are
+// made through the same services as the API, so they are validated the same way, in a transaction that
+// is always rolled back: nothing is persisted.
+func (s *SimulationService) Simulate(ctx context.Context, changes []domain.SimulationChange, checks []domain.PermissionCheck) ([]domain.SimulationResult, error) {
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("at least one change is required")
+	}
+	if len(changes) > maxSimulationChanges {
+		return nil, fmt.Errorf("at most %d changes can be simulated at once", maxSimulationChanges)
+	}
+	if len(checks) == 0 {
+		return nil, fmt.Errorf("at least one check is required")
+	}
+	if len(checks) > maxBatchChecks {
+		return nil, fmt.Errorf("at most %d checks can be made at once", maxBatchChecks)
+	}
+
+	results := make([]domain.SimulationResult, len(checks))
+	err := s.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		for i, check := range checks {
+			results[i].Check = check
+			results[i].Before = s.decide(ctx, check)
+		}
+
+		for i, change := range changes {
+			if err := s.apply(ctx, change); err != nil {
+				var failure lookupError
+				if errors.As(err, &failure) {
+					return failure.err
+				}
+				return fmt.Errorf("invalid change %d: %w", i, err)
+			}
+		}
+
+		for i, check := range checks {
+			results[i].After = s.decide(ctx, check)
+			results[i].Changed = results[i].Before.Granted != results[i].After.Granted
+		}
+		return errSimulated
+	})
+	if err != nil && !errors.Is(err, errSimulated) {
+		return nil, err
+	}
+	return results, nil
+}
+
+// decide decides a check. Checks share the connection of the simulation's transaction, so unlike a
+// batch they are decided one at a time.
+func (s *SimulationService) decide(ctx context.Context, check domain.PermissionCheck) domain.PermissionDecision {
+	granted, context, err := s.permissionService.CheckPermission(ctx, check.User, check.Action, check.Resource, check.Context)
+	decision := domain.PermissionDecision{Granted: granted, Context: context}
+	if err != nil {
+		decision.Error = err.Error()
+	}
+	return decision
+}
+
+// apply makes a proposed change
+func (s *SimulationService) apply(ctx context.Context, change domain.SimulationChange) error {
+	switch change.Type {
+	case domain.SimulationGrantPermission:
+		if change.Permission == nil {
+			return fmt.Errorf("permission is required")
+		}
+		if change.Permission.UserSetID != nil {
+			return s.permissionService.GrantUserSetPermission(ctx, change.Permission)
+		}
+		return s.permissionService.GrantRolePermission(ctx, change.Permission)
+	case domain.SimulationRevokePermission:
+		var err error
+		if change.UserSetID != "" {
+			_, err = s.permissionService.RevokeUserSetPermission(ctx, change.UserSetID, change.PermissionID)
+		} else {
+			_, err = s.permissionService.RevokeRolePermission(ctx, change.RoleID, change.PermissionID)
+		}
+		return err
+	case domain.SimulationAssignRole:
+		_, err := s.userRoleService.AssignRole(ctx, change.UserID, change.RoleID)
+		return err
+	case domain.SimulationRevokeRole:
+		_, err := s.userRoleService.RevokeRole(ctx, change.UserID, change.RoleID)
+		return err
+	case domain.SimulationDeleteRole:
+		if _, err := s.roleService.GetRoleByID(ctx, change.RoleID); err != nil {
+			return lookupFailure("role", err)
+		}
+		_, err := s.roleService.DeleteRole(ctx, change.RoleID)
+		return err
+	case domain.SimulationSetAttributes:
+		return s.setAttributes(ctx, change)
+	}
+	return fmt.Errorf("unknown change type %q", change.Type)
+}
+
+// setAttributes replaces the attributes of a user, resource or action
+func (s *SimulationService) setAttributes(ctx context.Context, change domain.SimulationChange) error {
+	switch change.EntityType {
+	case domain.EntityUser:
+		user, err := s.userService.GetUserByID(ctx, change.EntityID)
+		if err != nil {
+			return lookupFailure("user", err)
+		}
+		user.Attributes = change.Attributes
+		return s.userService.UpdateUser(ctx, user)
+	case domain.EntityResource:
+		resource, err := s.resourceService.GetResourceByID(ctx, change.EntityID)
+		if err != nil {
+			return lookupFailure("resource", err)
+		}
+		resource.Attributes = change.Attributes
+		return s.resourceService.UpdateResource(ctx, resource)
+	case domain.EntityAction:
+		action, err := s.actionService.GetActionByID(ctx, change.EntityID)
+		if err != nil {
+			return lookupFailure("action", err)
+		}
+		action.Attributes = change.Attributes
+		return s.actionService.UpdateAction(ctx, action)
+	}
+	return fmt.Errorf("attributes of %q cannot be set", change.EntityType)
+}
+
+// lookupError is a failure to look up an entity a change refers to, which is not caused by the change
+type lookupError struct {
+	err error
+}
+
+func (e lookupError) Error() string {
+	return e.err.Error()
+}
+
+func (e lookupError) Unwrap() error {
+	return e.err
+}
+
+// lookupFailure reports a missing entity a change refers to as an invalid change and any other lookup
+// failure as is
+func lookupFailure(entity string, err error) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("%s not found", entity)
+	}
+	return lookupError{err: err}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+)
+
+// UserRoleService handles business logic for assigning roles to users
+type UserRoleService struct {
+	userRoleRepo domain.UserRoleRepository
+	userRepo     domain.UserRepository
+	roleRepo     domain.RoleRepository
+	revisions    *RevisionService
+}
+
+// NewUserRoleService creates a new UserRoleService
+func NewUserRoleService(userRoleRepo domain.UserRoleRepository, userRepo domain.UserRepository, roleRepo domain.RoleRepository, revisions *RevisionService) *UserRoleService {
+	return &UserRoleService{
+		userRoleRepo: userRoleRepo,
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		revisions:    revisions,
+	}
+}
+
+// AssignRole assigns a role to a user
+func (s *UserRoleService) AssignRole(ctx context.Context, userID, roleID string) (*domain.UserRole, error) {
+	if roleID == "" {
+		return nil, fmt.Errorf("role ID is required")
+	}
+
+	// Verify that both sides of the assignment exist
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	if _, err := s.roleRepo.GetByID(ctx, roleID); err != nil {
+		return nil, fmt.Errorf("role not found")
+	}
+
+	// Check if the role is already assigned
+	if existing, err := s.userRoleRepo.Get(ctx, userID, roleID); err == nil && existing != nil {
+		return nil, fmt.Errorf("role already assigned")
+	}
+
+	userRole := &domain.UserRole{
+		UserID: userID,
+		RoleID: roleID,
+	}
+	err := s.revisions.Commit(ctx, "assign role", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		if err := s.userRoleRepo.Create(ctx, userRole); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityUserRole, userRole.ID, nil, bundleUserRole(userRole)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return userRole, nil
+}
+
+// RevokeRole removes a role from a user
+func (s *UserRoleService) RevokeRole(ctx context.Context, userID, roleID string) (*domain.UserRole, error) {
+	if roleID == "" {
+		return nil, fmt.Errorf("role ID is required")
+	}
+
+	var userRole *domain.UserRole
+	err := s.revisions.Commit(ctx, "revoke role", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		var err error
+		userRole, err = s.userRoleRepo.Delete(ctx, userID, roleID)
+		if err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityUserRole, userRole.ID, bundleUserRole(userRole), nil), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userRole, nil
+}
+
+// ListUserRoles retrieves the roles assigned to a user
+func (s *UserRoleService) ListUserRoles(ctx context.Context, userID string) ([]*domain.Role, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return s.userRoleRepo.ListRolesByUserID(ctx, userID)
+}
+
+// ListRoleUsers retrieves the users a role is assigned to
+func (s *UserRoleService) ListRoleUsers(ctx context.Context, roleID string) ([]*domain.User, error) {
+	if _, err := s.roleRepo.GetByID(ctx, roleID); err != nil {
+		return nil, fmt.Errorf("role not found")
+	}
+
+	return s.userRoleRepo.ListUsersByRoleID(ctx, roleID)
+}
+
+// UserRoleRepository returns the user role repository
+func (s *UserRoleService) UserRoleRepository() domain.UserRoleRepository {
+	return s.userRoleRepo
+}
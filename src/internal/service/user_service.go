@@ -9,13 +9,15 @@ import (
 
 // UserService handles business logic for users
 type UserService struct {
-	userRepo domain.UserRepository
+	userRepo  domain.UserRepository
+	revisions *RevisionService
 }
 
 // NewUserService creates a new UserService
-func NewUserService(userRepo domain.UserRepository) *UserService {
+func NewUserService(userRepo domain.UserRepository, revisions *RevisionService) *UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:  userRepo,
+		revisions: revisions,
 	}
 }
 
@@ -31,7 +33,12 @@ func (s *UserService) CreateUser(ctx context.Context, user *domain.User) error {
 		return fmt.Errorf("username already exists")
 	}
 
-	return s.userRepo.Create(ctx, user)
+	return s.revisions.Commit(ctx, "create user", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityUser, user.ID, nil, bundleUser(user)), nil
+	})
 }
 
 // GetUserByID retrieves a user by ID
@@ -67,12 +74,30 @@ func (s *UserService) UpdateUser(ctx context.Context, user *domain.User) error {
 		return fmt.Errorf("username already exists")
 	}
 
-	return s.userRepo.Update(ctx, user)
+	return s.revisions.Commit(ctx, "update user", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		before, _ := s.userRepo.GetByID(ctx, user.ID)
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityUser, user.ID, entityOf(before, bundleUser), bundleUser(user)), nil
+	})
 }
 
 // DeleteUser deletes a user by ID
 func (s *UserService) DeleteUser(ctx context.Context, id string) (*domain.User, error) {
-	return s.userRepo.Delete(ctx, id)
+	var user *domain.User
+	err := s.revisions.Commit(ctx, "delete user", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		var err error
+		user, err = s.userRepo.Delete(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityUser, user.ID, bundleUser(user), nil), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
 // UserRepository returns the user repository
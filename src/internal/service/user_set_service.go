@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arifsetyawan/validra/src/internal/domain"
+	"github.com/arifsetyawan/validra/src/pkg/condition"
+)
+
+// userSetPageSize is the page size used when every user set has to be evaluated
+const userSetPageSize = 100
+
+// UserSetService handles business logic for user sets
+type UserSetService struct {
+	userSetRepo domain.UserSetRepository
+	userRepo    domain.UserRepository
+	revisions   *RevisionService
+}
+
+// NewUserSetService creates a new UserSetService
+func NewUserSetService(userSetRepo domain.UserSetRepository, userRepo domain.UserRepository, revisions *RevisionService) *UserSetService {
+	return &UserSetService{
+		userSetRepo: userSetRepo,
+		userRepo:    userRepo,
+		revisions:   revisions,
+	}
+}
+
+// CreateUserSet creates a new user set
+func (s *UserSetService) CreateUserSet(ctx context.Context, userSet *domain.UserSet) error {
+	if err := validateUserSet(userSet); err != nil {
+		return err
+	}
+
+	return s.revisions.Commit(ctx, "create user set", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		if err := s.userSetRepo.Create(ctx, userSet); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityUserSet, userSet.ID, nil, bundleUserSet(userSet)), nil
+	})
+}
+
+// GetUserSetByID retrieves a user set by ID
+func (s *UserSetService) GetUserSetByID(ctx context.Context, id string) (*domain.UserSet, error) {
+	return s.userSetRepo.GetByID(ctx, id)
+}
+
+// ListUserSets retrieves a paginated list of user sets
+func (s *UserSetService) ListUserSets(ctx context.Context, limit, offset int) ([]*domain.UserSet, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+	return s.userSetRepo.List(ctx, limit, offset)
+}
+
+// UpdateUserSet updates an existing user set
+func (s *UserSetService) UpdateUserSet(ctx context.Context, userSet *domain.UserSet) error {
+	if userSet.ID == "" {
+		return fmt.Errorf("user set ID is required")
+	}
+	if err := validateUserSet(userSet); err != nil {
+		return err
+	}
+
+	return s.revisions.Commit(ctx, "update user set", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		before, _ := s.userSetRepo.GetByID(ctx, userSet.ID)
+		if err := s.userSetRepo.Update(ctx, userSet); err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityUserSet, userSet.ID, entityOf(before, bundleUserSet), bundleUserSet(userSet)), nil
+	})
+}
+
+// DeleteUserSet deletes a user set by ID
+func (s *UserSetService) DeleteUserSet(ctx context.Context, id string) (*domain.UserSet, error) {
+	var userSet *domain.UserSet
+	err := s.revisions.Commit(ctx, "delete user set", func(ctx context.Context) ([]domain.RevisionChange, error) {
+		var err error
+		userSet, err = s.userSetRepo.Delete(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return entityChanges(domain.EntityUserSet, userSet.ID, bundleUserSet(userSet), nil), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userSet, nil
+}
+
+// IsMember reports whether a user currently satisfies the conditions of a user set
+func (s *UserSetService) IsMember(ctx context.Context, userSetID, userID string) (bool, error) {
+	userSet, err := s.userSetRepo.GetByID(ctx, userSetID)
+	if err != nil {
+		return false, fmt.Errorf("user set not found")
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("user not found")
+	}
+
+	return isUserSetMember(userSet, user)
+}
+
+// UserSetRepository returns the user set repository
+func (s *UserSetService) UserSetRepository() domain.UserSetRepository {
+	return s.userSetRepo
+}
+
+// validateUserSet checks the required fields and the condition grammar of a user set
+func validateUserSet(userSet *domain.UserSet) error {
+	if userSet.Name == "" {
+		return fmt.Errorf("user set name is required")
+	}
+
+	conditions, err := condition.Parse(userSet.Conditions)
+	if err != nil {
+		return err
+	}
+	if conditions == nil {
+		return fmt.Errorf("invalid conditions: conditions are required")
+	}
+
+	return nil
+}
+
+// isUserSetMember evaluates the conditions of a user set against the attributes of a user
+func isUserSetMember(userSet *domain.UserSet, user *domain.User) (bool, error) {
+	conditions, err := condition.Parse(userSet.Conditions)
+	if err != nil {
+		return false, err
+	}
+	if conditions == nil {
+		return false, nil
+	}
+
+	return conditions.Evaluate(condition.Attributes(user.Attributes)), nil
+}
+
+// listUserSets returns every user set
+func listUserSets(ctx context.Context, userSetRepo domain.UserSetRepository) ([]*domain.UserSet, error) {
+	var all []*domain.UserSet
+	for offset := 0; ; offset += userSetPageSize {
+		userSets, err := userSetRepo.List(ctx, userSetPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, userSets...)
+
+		if len(userSets) < userSetPageSize {
+			return all, nil
+		}
+	}
+}
+
+// filterUserSets returns the user sets the user is a member of
+func filterUserSets(userSets []*domain.UserSet, user *domain.User) []*domain.UserSet {
+	var matched []*domain.UserSet
+	for _, userSet := range userSets {
+		member, err := isUserSetMember(userSet, user)
+		if err != nil {
+			// A user set with broken conditions never matches
+			continue
+		}
+		if member {
+			matched = append(matched, userSet)
+		}
+	}
+	return matched
+}
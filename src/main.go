@@ -17,6 +17,8 @@ import (
 	"github.com/arifsetyawan/validra/src/internal/service"
 	"github.com/arifsetyawan/validra/src/pkg/database"
 	"github.com/arifsetyawan/validra/src/pkg/logger"
+	policydoc "github.com/arifsetyawan/validra/src/pkg/policy"
+	"github.com/arifsetyawan/validra/src/pkg/tenant"
 	"github.com/arifsetyawan/validra/src/pkg/validator"
 	"github.com/labstack/echo/v4"
 
@@ -46,11 +48,34 @@ func main() {
 	cfg := config.Load()
 	log.Info("Configuration loaded")
 
+	// Validate the global permission combining policy
+	policy := domain.CombiningPolicy{
+		Algorithm:     cfg.Policy.CombiningAlgorithm,
+		DefaultEffect: cfg.Policy.DefaultEffect,
+	}
+	if err := policy.Validate(); err != nil {
+		log.Error("Invalid policy configuration: %v", err)
+		os.Exit(1)
+	}
+	if cfg.Tenant.Default != "" {
+		if err := tenant.Validate(cfg.Tenant.Default); err != nil {
+			log.Error("Invalid tenant configuration: %v", err)
+			os.Exit(1)
+		}
+	}
+
 	// Initialize database repositories
 	var resourceRepo domain.ResourceRepository
 	var userRepo domain.UserRepository
 	var roleRepo domain.RoleRepository
 	var actionRepo domain.ActionRepository
+	var userRoleRepo domain.UserRoleRepository
+	var userSetRepo domain.UserSetRepository
+	var resourceSetRepo domain.ResourceSetRepository
+	var tupleRepo domain.RelationTupleRepository
+	var schemaRepo domain.RelationSchemaRepository
+	var permissionRepo domain.PermissionRepository
+	var revisionRepo domain.RevisionRepository
 
 	// Initialize PostgreSQL with GORM
 	db, err := database.NewPostgresDB(
@@ -80,6 +105,13 @@ func main() {
 	userRepo = repository.NewUserRepository(db)
 	roleRepo = repository.NewRoleRepository(db)
 	actionRepo = repository.NewActionRepository(db)
+	userRoleRepo = repository.NewUserRoleRepository(db)
+	userSetRepo = repository.NewUserSetRepository(db)
+	resourceSetRepo = repository.NewResourceSetRepository(db)
+	tupleRepo = repository.NewRelationTupleRepository(db)
+	schemaRepo = repository.NewRelationSchemaRepository(db)
+	permissionRepo = repository.NewPermissionRepository(db)
+	revisionRepo = repository.NewRevisionRepository(db)
 
 	// Initialize Echo
 	e := echo.New()
@@ -87,15 +119,103 @@ func main() {
 
 	// Setup middleware
 	middleware.SetupMiddleware(e, log)
+	middleware.SetupTenant(e, cfg.Tenant.Header, cfg.Tenant.Default)
+	middleware.SetupRevisionToken(e)
 
 	// Initialize services
-	resourceService := service.NewResourceService(resourceRepo)
-	userService := service.NewUserService(userRepo)
-	roleService := service.NewRoleService(roleRepo)
-	actionService := service.NewActionService(actionRepo, resourceRepo)
+	revisionService := service.NewRevisionService(db, revisionRepo)
+	var decisionCache *service.DecisionCache
+	if cfg.Cache.TTL > 0 && cfg.Cache.MaxEntries > 0 {
+		decisionCache = service.NewDecisionCache(revisionService, time.Duration(cfg.Cache.TTL)*time.Second, cfg.Cache.MaxEntries)
+	}
+	resourceService := service.NewResourceService(resourceRepo, revisionService)
+	userService := service.NewUserService(userRepo, revisionService)
+	roleService := service.NewRoleService(roleRepo, permissionRepo, revisionService)
+	actionService := service.NewActionService(actionRepo, resourceRepo, revisionService)
+	userRoleService := service.NewUserRoleService(userRoleRepo, userRepo, roleRepo, revisionService)
+	userSetService := service.NewUserSetService(userSetRepo, userRepo, revisionService)
+	resourceSetService := service.NewResourceSetService(resourceSetRepo, resourceRepo, actionRepo, revisionService)
+	relationshipService := service.NewRelationshipService(tupleRepo, schemaRepo, revisionService)
+	schemaService := service.NewSchemaService(schemaRepo, revisionService)
+	permissionService := service.NewPermissionService(
+		userRepo,
+		actionRepo,
+		resourceRepo,
+		roleRepo,
+		permissionRepo,
+		userRoleRepo,
+		userSetRepo,
+		resourceSetRepo,
+		tupleRepo,
+		schemaRepo,
+		policy,
+		revisionService,
+		decisionCache,
+	)
+	policyService := service.NewPolicyService(
+		revisionService,
+		resourceRepo,
+		actionRepo,
+		roleRepo,
+		userRoleRepo,
+		userSetRepo,
+		resourceSetRepo,
+		permissionRepo,
+		revisionService,
+	)
+
+	bundleService := service.NewBundleService(
+		revisionService,
+		resourceRepo,
+		actionRepo,
+		roleRepo,
+		userRepo,
+		userRoleRepo,
+		userSetRepo,
+		resourceSetRepo,
+		permissionRepo,
+		tupleRepo,
+		schemaRepo,
+		revisionService,
+	)
+
+	simulationService := service.NewSimulationService(
+		revisionService,
+		permissionService,
+		roleService,
+		userRoleService,
+		userService,
+		resourceService,
+		actionService,
+	)
+
+	// Apply the policy document configured for startup
+	if cfg.Policy.File != "" {
+		if err := applyPolicyFile(policyService, cfg.Policy.File, cfg.Tenant.Default, cfg.Policy.Prune); err != nil {
+			log.Error("Failed to apply policy file %s: %v", cfg.Policy.File, err)
+			os.Exit(1)
+		}
+		log.Info("Policy file %s applied", cfg.Policy.File)
+	}
 
 	// Register routes
-	router.Register(e, resourceService, userService, roleService, actionService)
+	router.Register(e, &router.Services{
+		Resource:     resourceService,
+		User:         userService,
+		Role:         roleService,
+		Action:       actionService,
+		UserRole:     userRoleService,
+		UserSet:      userSetService,
+		ResourceSet:  resourceSetService,
+		Relationship: relationshipService,
+		Schema:       schemaService,
+		Permission:   permissionService,
+		Policy:       policyService,
+		Bundle:       bundleService,
+		Revision:     revisionService,
+		Simulation:   simulationService,
+		Cache:        decisionCache,
+	})
 	log.Info("Routes registered")
 
 	// Setup Swagger
@@ -134,3 +254,27 @@ func main() {
 
 	log.Info("Server shutdown complete")
 }
+
+// applyPolicyFile applies a policy document to the tenant it names, or to the default tenant
+func applyPolicyFile(policyService *service.PolicyService, path, defaultTenant string, prune bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	document, err := policydoc.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	// The document tenant is validated like the API does, which could never reach it otherwise
+	id := document.Tenant
+	if id == "" {
+		id = defaultTenant
+	} else if err := tenant.Validate(id); err != nil {
+		return fmt.Errorf("policy tenant %q: %w", id, err)
+	}
+	ctx := tenant.WithID(context.Background(), id)
+
+	_, err = policyService.Apply(ctx, document, prune)
+	return err
+}
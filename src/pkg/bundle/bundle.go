@@ -0,0 +1,464 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Format identifies a bundle among other JSON documents
+const Format = "validra.bundle"
+
+// Version is the version of the bundle format understood by this package
+const Version = 1
+
+// Sections of a bundle, in the order they are written and restored
+const (
+	SectionResources     = "resources"
+	SectionActions       = "actions"
+	SectionRoles         = "roles"
+	SectionRoleParents   = "role_parents"
+	SectionUsers         = "users"
+	SectionUserRoles     = "user_roles"
+	SectionUserSets      = "user_sets"
+	SectionResourceSets  = "resource_sets"
+	SectionPermissions   = "permissions"
+	SectionRelationships = "relationships"
+	SectionSchema        = "schema"
+)
+
+// Bundle is a complete, self-describing copy of the model of a tenant. Unlike a policy document,
+// entities keep their IDs and refer to each other by ID, so that a bundle restores an exact copy.
+type Bundle struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	Tenant     string    `json:"tenant"`
+	ExportedAt time.Time `json:"exported_at"`
+
+	Resources     []Resource     `json:"resources"`
+	Actions       []Action       `json:"actions"`
+	Roles         []Role         `json:"roles"`
+	RoleParents   []RoleParent   `json:"role_parents"`
+	Users         []User         `json:"users"`
+	UserRoles     []UserRole     `json:"user_roles"`
+	UserSets      []UserSet      `json:"user_sets"`
+	ResourceSets  []ResourceSet  `json:"resource_sets"`
+	Permissions   []Permission   `json:"permissions"`
+	Relationships []Relationship `json:"relationships"`
+	// Schema is the relation schema version in use, if any
+	Schema *Schema `json:"schema"`
+}
+
+// Resource is a resource of the bundle
+type Resource struct {
+	ID                 string          `json:"id"`
+	Name               string          `json:"name"`
+	Description        string          `json:"description,omitempty"`
+	Attributes         json.RawMessage `json:"attributes,omitempty"`
+	CombiningAlgorithm string          `json:"combining_algorithm,omitempty"`
+	DefaultEffect      string          `json:"default_effect,omitempty"`
+	ParentID           *string         `json:"parent_id,omitempty"`
+	BlockInheritance   bool            `json:"block_inheritance,omitempty"`
+}
+
+// Action is an action of a resource of the bundle
+type Action struct {
+	ID          string          `json:"id"`
+	ResourceID  string          `json:"resource_id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Attributes  json.RawMessage `json:"attributes,omitempty"`
+}
+
+// Role is a role of the bundle
+type Role struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// RoleParent makes a role of the bundle inherit from another
+type RoleParent struct {
+	RoleID   string `json:"role_id"`
+	ParentID string `json:"parent_id"`
+}
+
+// User is a user of the bundle
+type User struct {
+	ID         string          `json:"id"`
+	Username   string          `json:"username"`
+	Attributes json.RawMessage `json:"attributes,omitempty"`
+}
+
+// UserRole assigns a role of the bundle to a user
+type UserRole struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+	RoleID string `json:"role_id"`
+}
+
+// UserSet is a user set of the bundle
+type UserSet struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Conditions  json.RawMessage `json:"conditions,omitempty"`
+}
+
+// ResourceSet is a resource set of the bundle
+type ResourceSet struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Conditions  json.RawMessage `json:"conditions,omitempty"`
+}
+
+// Permission is a grant of the bundle
+type Permission struct {
+	ID            string          `json:"id"`
+	RoleID        string          `json:"role_id,omitempty"`
+	UserID        *string         `json:"user_id,omitempty"`
+	UserSetID     *string         `json:"user_set_id,omitempty"`
+	ResourceID    *string         `json:"resource_id,omitempty"`
+	ResourceSetID *string         `json:"resource_set_id,omitempty"`
+	ActionID      *string         `json:"action_id,omitempty"`
+	ActionName    *string         `json:"action_name,omitempty"`
+	Effect        string          `json:"effect"`
+	Priority      int             `json:"priority,omitempty"`
+	Conditions    json.RawMessage `json:"conditions,omitempty"`
+}
+
+// Relationship is a relationship tuple of the bundle in its "object#relation@subject" form
+type Relationship struct {
+	ID    string `json:"id"`
+	Tuple string `json:"tuple"`
+}
+
+// Schema is a relation schema definition of the bundle
+type Schema struct {
+	ID         string          `json:"id"`
+	Version    int             `json:"version"`
+	Definition json.RawMessage `json:"definition"`
+}
+
+// Decode reads and validates a bundle. Unknown fields are rejected so that a bundle written by a
+// newer format is not silently restored in part.
+func Decode(r io.Reader) (*Bundle, error) {
+	var b Bundle
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&b); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	if err := b.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	return &b, nil
+}
+
+// Validate checks the format and version of the bundle, that IDs are unique and that every
+// reference points at an entity of the bundle
+func (b *Bundle) Validate() error {
+	if b.Format != Format {
+		return fmt.Errorf("unknown format %q, expected %q", b.Format, Format)
+	}
+	if b.Version != Version {
+		return fmt.Errorf("unsupported version %d, expected %d", b.Version, Version)
+	}
+
+	resources := make(map[string]bool, len(b.Resources))
+	for _, r := range b.Resources {
+		if err := addID(resources, SectionResources, r.ID); err != nil {
+			return err
+		}
+		if r.Name == "" {
+			return fmt.Errorf("resource %s: name is required", r.ID)
+		}
+	}
+	for _, r := range b.Resources {
+		if r.ParentID != nil && !resources[*r.ParentID] {
+			return fmt.Errorf("resource %s: parent resource %s is not in the bundle", r.ID, *r.ParentID)
+		}
+	}
+
+	actions := make(map[string]bool, len(b.Actions))
+	for _, a := range b.Actions {
+		if err := addID(actions, SectionActions, a.ID); err != nil {
+			return err
+		}
+		if a.Name == "" {
+			return fmt.Errorf("action %s: name is required", a.ID)
+		}
+		if !resources[a.ResourceID] {
+			return fmt.Errorf("action %s: resource %s is not in the bundle", a.ID, a.ResourceID)
+		}
+	}
+
+	roles := make(map[string]bool, len(b.Roles))
+	for _, r := range b.Roles {
+		if err := addID(roles, SectionRoles, r.ID); err != nil {
+			return err
+		}
+		if r.Name == "" {
+			return fmt.Errorf("role %s: name is required", r.ID)
+		}
+	}
+	for _, rp := range b.RoleParents {
+		if !roles[rp.RoleID] || !roles[rp.ParentID] {
+			return fmt.Errorf("role parent %s > %s: roles are not in the bundle", rp.RoleID, rp.ParentID)
+		}
+	}
+
+	users := make(map[string]bool, len(b.Users))
+	for _, u := range b.Users {
+		if err := addID(users, SectionUsers, u.ID); err != nil {
+			return err
+		}
+		if u.Username == "" {
+			return fmt.Errorf("user %s: username is required", u.ID)
+		}
+	}
+	userRoles := make(map[string]bool, len(b.UserRoles))
+	for _, ur := range b.UserRoles {
+		if err := addID(userRoles, SectionUserRoles, ur.ID); err != nil {
+			return err
+		}
+		if !users[ur.UserID] || !roles[ur.RoleID] {
+			return fmt.Errorf("user role %s: user or role is not in the bundle", ur.ID)
+		}
+	}
+
+	userSets := make(map[string]bool, len(b.UserSets))
+	for _, us := range b.UserSets {
+		if err := addID(userSets, SectionUserSets, us.ID); err != nil {
+			return err
+		}
+		if us.Name == "" {
+			return fmt.Errorf("user set %s: name is required", us.ID)
+		}
+	}
+	resourceSets := make(map[string]bool, len(b.ResourceSets))
+	for _, rs := range b.ResourceSets {
+		if err := addID(resourceSets, SectionResourceSets, rs.ID); err != nil {
+			return err
+		}
+		if rs.Name == "" {
+			return fmt.Errorf("resource set %s: name is required", rs.ID)
+		}
+	}
+
+	permissions := make(map[string]bool, len(b.Permissions))
+	for _, p := range b.Permissions {
+		if err := addID(permissions, SectionPermissions, p.ID); err != nil {
+			return err
+		}
+		switch {
+		case p.RoleID != "" && !roles[p.RoleID],
+			p.UserID != nil && !users[*p.UserID],
+			p.UserSetID != nil && !userSets[*p.UserSetID],
+			p.ResourceID != nil && !resources[*p.ResourceID],
+			p.ResourceSetID != nil && !resourceSets[*p.ResourceSetID],
+			p.ActionID != nil && !actions[*p.ActionID]:
+			return fmt.Errorf("permission %s: references an entity that is not in the bundle", p.ID)
+		}
+	}
+
+	relationships := make(map[string]bool, len(b.Relationships))
+	for _, r := range b.Relationships {
+		if err := addID(relationships, SectionRelationships, r.ID); err != nil {
+			return err
+		}
+		if r.Tuple == "" {
+			return fmt.Errorf("relationship %s: tuple is required", r.ID)
+		}
+	}
+
+	if b.Schema != nil && len(b.Schema.Definition) == 0 {
+		return fmt.Errorf("schema: definition is required")
+	}
+
+	return nil
+}
+
+// addID records the ID of an entity, rejecting empty and duplicate IDs
+func addID(ids map[string]bool, section, id string) error {
+	if id == "" {
+		return fmt.Errorf("%s: id is required", section)
+	}
+	if ids[id] {
+		return fmt.Errorf("%s: id %s appears more than once", section, id)
+	}
+	ids[id] = true
+	return nil
+}
+
+// RoleParentKey returns the key identifying a role parent link within its section
+func RoleParentKey(roleID, parentID string) string {
+	return roleID + ":" + parentID
+}
+
+// Set puts a JSON encoded entity into a section in place of the entity with the same key, or removes
+// that entity when data is empty. Entities are keyed by ID, except role parents, keyed by RoleParentKey,
+// and relationships, keyed by tuple. The schema section holds a single entity, whatever the key.
+func (b *Bundle) Set(section, key string, data json.RawMessage) error {
+	var err error
+	switch section {
+	case SectionResources:
+		b.Resources, err = set(b.Resources, func(r Resource) string { return r.ID }, key, data)
+	case SectionActions:
+		b.Actions, err = set(b.Actions, func(a Action) string { return a.ID }, key, data)
+	case SectionRoles:
+		b.Roles, err = set(b.Roles, func(r Role) string { return r.ID }, key, data)
+	case SectionRoleParents:
+		b.RoleParents, err = set(b.RoleParents, func(rp RoleParent) string { return RoleParentKey(rp.RoleID, rp.ParentID) }, key, data)
+	case SectionUsers:
+		b.Users, err = set(b.Users, func(u User) string { return u.ID }, key, data)
+	case SectionUserRoles:
+		b.UserRoles, err = set(b.UserRoles, func(ur UserRole) string { return ur.ID }, key, data)
+	case SectionUserSets:
+		b.UserSets, err = set(b.UserSets, func(us UserSet) string { return us.ID }, key, data)
+	case SectionResourceSets:
+		b.ResourceSets, err = set(b.ResourceSets, func(rs ResourceSet) string { return rs.ID }, key, data)
+	case SectionPermissions:
+		b.Permissions, err = set(b.Permissions, func(p Permission) string { return p.ID }, key, data)
+	case SectionRelationships:
+		b.Relationships, err = set(b.Relationships, func(r Relationship) string { return r.Tuple }, key, data)
+	case SectionSchema:
+		b.Schema = nil
+		if len(data) > 0 {
+			b.Schema = &Schema{}
+			err = json.Unmarshal(data, b.Schema)
+		}
+	default:
+		return fmt.Errorf("unknown section %q", section)
+	}
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", section, key, err)
+	}
+	return nil
+}
+
+// set replaces, appends or removes the entity with the given key
+func set[T any](entities []T, keyOf func(T) string, key string, data json.RawMessage) ([]T, error) {
+	index := -1
+	for i, e := range entities {
+		if keyOf(e) == key {
+			index = i
+			break
+		}
+	}
+
+	if len(data) == 0 {
+		if index < 0 {
+			return entities, nil
+		}
+		return append(entities[:index:index], entities[index+1:]...), nil
+	}
+
+	var entity T
+	if err := json.Unmarshal(data, &entity); err != nil {
+		return nil, err
+	}
+	if index < 0 {
+		return append(entities, entity), nil
+	}
+	entities[index] = entity
+	return entities, nil
+}
+
+// Writer streams a bundle section by section, so that a model is exported without being held in memory.
+// Sections must be written in the order of the Bundle fields; Close ends the bundle.
+type Writer struct {
+	w       io.Writer
+	err     error
+	inArray bool
+	first   bool
+}
+
+// NewWriter writes the header of a bundle of the given tenant
+func NewWriter(w io.Writer, tenant string, exportedAt time.Time) *Writer {
+	bw := &Writer{w: w}
+	bw.write("{")
+	bw.key("format")
+	bw.value(Format)
+	bw.write(",")
+	bw.key("version")
+	bw.value(Version)
+	bw.write(",")
+	bw.key("tenant")
+	bw.value(tenant)
+	bw.write(",")
+	bw.key("exported_at")
+	bw.value(exportedAt)
+	return bw
+}
+
+// Section starts a list of entities, ending the previous one
+func (w *Writer) Section(name string) {
+	w.endSection()
+	w.write(",")
+	w.key(name)
+	w.write("[")
+	w.inArray = true
+	w.first = true
+}
+
+// Add writes an entity of the current section
+func (w *Writer) Add(entity interface{}) {
+	if !w.first {
+		w.write(",")
+	}
+	w.first = false
+	w.value(entity)
+}
+
+// Schema writes the relation schema in use, ending the previous section
+func (w *Writer) Schema(schema *Schema) {
+	w.endSection()
+	w.write(",")
+	w.key(SectionSchema)
+	w.value(schema)
+}
+
+// Close ends the bundle and returns the first error met while writing it
+func (w *Writer) Close() error {
+	w.endSection()
+	w.write("}\n")
+	return w.err
+}
+
+// endSection closes the list of entities being written, if any
+func (w *Writer) endSection() {
+	if w.inArray {
+		w.write("]")
+		w.inArray = false
+	}
+}
+
+// key writes the key of a field
+func (w *Writer) key(name string) {
+	w.value(name)
+	w.write(":")
+}
+
+// value writes a JSON encoded value
+func (w *Writer) value(v interface{}) {
+	if w.err != nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		w.err = err
+		return
+	}
+	w.write(string(data))
+}
+
+// write writes raw output unless an error occurred before
+func (w *Writer) write(s string) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = io.WriteString(w.w, s)
+}
@@ -0,0 +1,437 @@
+package condition
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Group operators chain nested conditions together
+const (
+	OperatorAnd = "and"
+	OperatorOr  = "or"
+	OperatorNot = "not"
+)
+
+// Comparison operators compare an attribute with the expected value
+const (
+	OperatorEq          = "eq"
+	OperatorNe          = "ne"
+	OperatorIn          = "in"
+	OperatorNotIn       = "not_in"
+	OperatorGt          = "gt"
+	OperatorGte         = "gte"
+	OperatorLt          = "lt"
+	OperatorLte         = "lte"
+	OperatorContains    = "contains"
+	OperatorNotContains = "not_contains"
+	OperatorStartsWith  = "starts_with"
+	OperatorEndsWith    = "ends_with"
+	OperatorRegex       = "regex"
+	OperatorExists      = "exists"
+	OperatorCIDR        = "cidr"
+)
+
+// Condition is a node of a condition tree.
+//
+// A group node chains its children with "and", "or" or "not" (none of the children may match):
+//
+//	{"operator": "and", "conditions": [...]}
+//
+// A comparison node compares an attribute, addressed with a dot separated path, with a value:
+//
+//	{"attribute": "department", "operator": "in", "value": ["finance", "audit"]}
+type Condition struct {
+	Operator   string      `json:"operator"`
+	Attribute  string      `json:"attribute,omitempty"`
+	Value      interface{} `json:"value,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// Parse decodes and validates a JSON serialized condition tree.
+// It returns nil when data is empty.
+func Parse(data []byte) (*Condition, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	var c Condition
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid conditions: %w", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid conditions: %w", err)
+	}
+
+	return &c, nil
+}
+
+// IsGroup reports whether the condition chains nested conditions
+func (c *Condition) IsGroup() bool {
+	switch c.Operator {
+	case OperatorAnd, OperatorOr, OperatorNot:
+		return true
+	}
+	return false
+}
+
+// Validate checks that the condition tree is well formed
+func (c *Condition) Validate() error {
+	if c.IsGroup() {
+		if len(c.Conditions) == 0 {
+			return fmt.Errorf("%s group requires at least one condition", c.Operator)
+		}
+		for i := range c.Conditions {
+			if err := c.Conditions[i].Validate(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if c.Attribute == "" {
+		return fmt.Errorf("attribute is required for operator %q", c.Operator)
+	}
+
+	switch c.Operator {
+	case OperatorEq, OperatorNe, OperatorGt, OperatorGte, OperatorLt, OperatorLte,
+		OperatorContains, OperatorNotContains, OperatorExists:
+		return nil
+	case OperatorStartsWith, OperatorEndsWith:
+		if _, ok := c.Value.(string); !ok {
+			return fmt.Errorf("operator %q requires a string value", c.Operator)
+		}
+		return nil
+	case OperatorIn, OperatorNotIn:
+		if _, ok := c.Value.([]interface{}); !ok {
+			return fmt.Errorf("operator %q requires a list value", c.Operator)
+		}
+		return nil
+	case OperatorCIDR:
+		ranges, ok := cidrRanges(c.Value)
+		if !ok {
+			return fmt.Errorf("operator %q requires a CIDR range or a list of CIDR ranges", c.Operator)
+		}
+		for _, r := range ranges {
+			if _, _, err := net.ParseCIDR(r); err != nil {
+				return fmt.Errorf("invalid CIDR range %q: %w", r, err)
+			}
+		}
+		return nil
+	case OperatorRegex:
+		pattern, ok := c.Value.(string)
+		if !ok {
+			return fmt.Errorf("operator %q requires a string value", c.Operator)
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid regex %q: %w", pattern, err)
+		}
+		return nil
+	case "":
+		return fmt.Errorf("operator is required")
+	}
+
+	return fmt.Errorf("unknown operator %q", c.Operator)
+}
+
+// Evaluate reports whether the attributes satisfy the condition tree
+func (c *Condition) Evaluate(attributes map[string]interface{}) bool {
+	switch c.Operator {
+	case OperatorAnd:
+		for i := range c.Conditions {
+			if !c.Conditions[i].Evaluate(attributes) {
+				return false
+			}
+		}
+		return true
+	case OperatorOr:
+		for i := range c.Conditions {
+			if c.Conditions[i].Evaluate(attributes) {
+				return true
+			}
+		}
+		return false
+	case OperatorNot:
+		for i := range c.Conditions {
+			if c.Conditions[i].Evaluate(attributes) {
+				return false
+			}
+		}
+		return true
+	}
+
+	actual, found := Lookup(attributes, c.Attribute)
+	return compare(c.Operator, actual, found, c.Value)
+}
+
+// Reads reports whether the condition tree reads an attribute under root, as "env.hour" is under "env"
+func (c *Condition) Reads(root string) bool {
+	if c.IsGroup() {
+		for i := range c.Conditions {
+			if c.Conditions[i].Reads(root) {
+				return true
+			}
+		}
+		return false
+	}
+	return c.Attribute == root || strings.HasPrefix(c.Attribute, root+".")
+}
+
+// Trace records how a node of a condition tree was evaluated
+type Trace struct {
+	Operator   string      `json:"operator"`
+	Attribute  string      `json:"attribute,omitempty"`
+	Expected   interface{} `json:"expected,omitempty"`
+	Actual     interface{} `json:"actual,omitempty"`
+	Missing    bool        `json:"missing,omitempty"`
+	Matched    bool        `json:"matched"`
+	Conditions []Trace     `json:"conditions,omitempty"`
+}
+
+// Explain evaluates the condition tree like Evaluate and records, for every node, whether it
+// matched and, for comparisons, the actual value compared with the expected one.
+// Unlike Evaluate, every child of a group is evaluated.
+func (c *Condition) Explain(attributes map[string]interface{}) Trace {
+	trace := Trace{Operator: c.Operator}
+	if c.IsGroup() {
+		matchedChildren := 0
+		trace.Conditions = make([]Trace, len(c.Conditions))
+		for i := range c.Conditions {
+			trace.Conditions[i] = c.Conditions[i].Explain(attributes)
+			if trace.Conditions[i].Matched {
+				matchedChildren++
+			}
+		}
+		switch c.Operator {
+		case OperatorAnd:
+			trace.Matched = matchedChildren == len(c.Conditions)
+		case OperatorOr:
+			trace.Matched = matchedChildren > 0
+		case OperatorNot:
+			trace.Matched = matchedChildren == 0
+		}
+		return trace
+	}
+
+	actual, found := Lookup(attributes, c.Attribute)
+	trace.Attribute = c.Attribute
+	trace.Expected = c.Value
+	trace.Actual = actual
+	trace.Missing = !found
+	trace.Matched = compare(c.Operator, actual, found, c.Value)
+	return trace
+}
+
+// Lookup resolves a dot separated attribute path such as "address.city"
+func Lookup(attributes map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = attributes
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// Attributes decodes JSON serialized attributes into a map.
+// Empty or non-object attributes decode to an empty map.
+func Attributes(data []byte) map[string]interface{} {
+	attributes := map[string]interface{}{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &attributes); err != nil || attributes == nil {
+			return map[string]interface{}{}
+		}
+	}
+	return attributes
+}
+
+// compare applies a comparison operator to an attribute value
+func compare(operator string, actual interface{}, found bool, expected interface{}) bool {
+	if operator == OperatorExists {
+		want, ok := expected.(bool)
+		if !ok {
+			want = true
+		}
+		return found == want
+	}
+
+	if !found {
+		// A missing attribute only satisfies negative operators
+		return operator == OperatorNe || operator == OperatorNotIn || operator == OperatorNotContains
+	}
+
+	switch operator {
+	case OperatorEq:
+		return equal(actual, expected)
+	case OperatorNe:
+		return !equal(actual, expected)
+	case OperatorIn:
+		return contains(expected, actual)
+	case OperatorNotIn:
+		return !contains(expected, actual)
+	case OperatorGt, OperatorGte, OperatorLt, OperatorLte:
+		return order(operator, actual, expected)
+	case OperatorContains:
+		return contains(actual, expected)
+	case OperatorNotContains:
+		return !contains(actual, expected)
+	case OperatorStartsWith:
+		s, ok := actual.(string)
+		return ok && strings.HasPrefix(s, expected.(string))
+	case OperatorEndsWith:
+		s, ok := actual.(string)
+		return ok && strings.HasSuffix(s, expected.(string))
+	case OperatorRegex:
+		s, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		matched, err := regexp.MatchString(expected.(string), s)
+		return err == nil && matched
+	case OperatorCIDR:
+		return inCIDR(actual, expected)
+	}
+
+	return false
+}
+
+// cidrRanges returns the CIDR ranges held by a string or a list of strings
+func cidrRanges(v interface{}) ([]string, bool) {
+	switch r := v.(type) {
+	case string:
+		return []string{r}, true
+	case []interface{}:
+		ranges := make([]string, len(r))
+		for i, item := range r {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			ranges[i] = s
+		}
+		return ranges, len(ranges) > 0
+	}
+	return nil, false
+}
+
+// inCIDR reports whether an IP address falls within any of the CIDR ranges
+func inCIDR(actual, expected interface{}) bool {
+	s, ok := actual.(string)
+	if !ok {
+		return false
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return false
+	}
+
+	ranges, _ := cidrRanges(expected)
+	for _, r := range ranges {
+		if _, network, err := net.ParseCIDR(r); err == nil && network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal compares two decoded JSON values, treating numbers of any type as equal by value
+func equal(a, b interface{}) bool {
+	if bothStrings(a, b) {
+		return a == b
+	}
+	if x, ok := toNumber(a); ok {
+		if y, ok := toNumber(b); ok {
+			return x == y
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// contains reports whether a list holds an element or a string holds a substring
+func contains(container, element interface{}) bool {
+	switch c := container.(type) {
+	case []interface{}:
+		for _, item := range c {
+			if equal(item, element) {
+				return true
+			}
+		}
+	case string:
+		if s, ok := element.(string); ok {
+			return strings.Contains(c, s)
+		}
+	}
+	return false
+}
+
+// order compares numbers by value and strings lexically
+func order(operator string, actual, expected interface{}) bool {
+	var cmp int
+	x, okX := toNumber(actual)
+	y, okY := toNumber(expected)
+	switch {
+	case okX && okY && !bothStrings(actual, expected):
+		switch {
+		case x < y:
+			cmp = -1
+		case x > y:
+			cmp = 1
+		}
+	default:
+		a, okA := actual.(string)
+		b, okB := expected.(string)
+		if !okA || !okB {
+			return false
+		}
+		cmp = strings.Compare(a, b)
+	}
+
+	switch operator {
+	case OperatorGt:
+		return cmp > 0
+	case OperatorGte:
+		return cmp >= 0
+	case OperatorLt:
+		return cmp < 0
+	case OperatorLte:
+		return cmp <= 0
+	}
+	return false
+}
+
+// bothStrings reports whether both values are strings
+func bothStrings(a, b interface{}) bool {
+	_, okA := a.(string)
+	_, okB := b.(string)
+	return okA && okB
+}
+
+// toNumber converts decoded JSON numbers and numeric strings to float64
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -42,6 +43,11 @@ func NewPostgresDB(host, user, password, dbname string, port int, sslmode string
 	// SetConnMaxLifetime sets the maximum amount of time a connection may be reused
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
+	// Scope every statement to the tenant of its context
+	if err := registerTenantCallbacks(db); err != nil {
+		return nil, fmt.Errorf("failed to register tenant callbacks: %w", err)
+	}
+
 	return &PostgresDB{DB: db}, nil
 }
 
@@ -60,47 +66,180 @@ func (p *PostgresDB) Migrate() error {
 
 	// Define models for migration
 	type Resource struct {
-		ID          string `gorm:"primaryKey"`
-		Name        string `gorm:"not null"`
-		Description string
-		Attributes  []byte
-		CreatedAt   time.Time
-		UpdatedAt   time.Time
+		ID                 string `gorm:"primaryKey"`
+		TenantID           string `gorm:"not null;default:'default';index"`
+		Name               string `gorm:"not null"`
+		Description        string
+		Attributes         []byte
+		CombiningAlgorithm string
+		DefaultEffect      string
+		ParentID           *string `gorm:"index"`
+		BlockInheritance   bool    `gorm:"not null;default:false"`
+		CreatedAt          time.Time
+		UpdatedAt          time.Time
+		DeletedAt          *time.Time `gorm:"index"`
 	}
 
 	type Action struct {
 		ID          string `gorm:"primaryKey"`
+		TenantID    string `gorm:"not null;default:'default';index"`
 		ResourceID  string `gorm:"not null;index"`
 		Name        string `gorm:"not null"`
 		Description string
 		Attributes  []byte
 		CreatedAt   time.Time
 		UpdatedAt   time.Time
-		Resource    Resource `gorm:"foreignKey:ResourceID"`
+		DeletedAt   *time.Time `gorm:"index"`
+		Resource    Resource   `gorm:"foreignKey:ResourceID"`
 	}
 
 	type Role struct {
 		ID          string `gorm:"primaryKey"`
+		TenantID    string `gorm:"not null;default:'default';index"`
 		Name        string `gorm:"not null"`
 		Description string
 		CreatedAt   time.Time
 		UpdatedAt   time.Time
+		DeletedAt   *time.Time `gorm:"index"`
+	}
+
+	type RoleParent struct {
+		RoleID    string `gorm:"primaryKey"`
+		ParentID  string `gorm:"primaryKey;index"`
+		TenantID  string `gorm:"not null;default:'default';index"`
+		CreatedAt time.Time
 	}
 
 	type User struct {
 		ID         string `gorm:"primaryKey"`
-		Username   string `gorm:"not null;uniqueIndex"`
+		TenantID   string `gorm:"not null;default:'default';index;uniqueIndex:idx_users_tenant_username;uniqueIndex:idx_users_tenant_email"`
+		Username   string `gorm:"not null;uniqueIndex:idx_users_tenant_username"`
 		Attributes []byte
-		Email      string `gorm:"uniqueIndex"`
+		Email      string `gorm:"uniqueIndex:idx_users_tenant_email"`
 		CreatedAt  time.Time
 		UpdatedAt  time.Time
+		DeletedAt  *time.Time `gorm:"index"`
+	}
+
+	type UserRole struct {
+		ID        string `gorm:"primaryKey"`
+		TenantID  string `gorm:"not null;default:'default';index"`
+		UserID    string `gorm:"not null;uniqueIndex:idx_user_roles_user_role"`
+		RoleID    string `gorm:"not null;uniqueIndex:idx_user_roles_user_role;index"`
+		CreatedAt time.Time
+	}
+
+	type UserSet struct {
+		ID          string `gorm:"primaryKey"`
+		TenantID    string `gorm:"not null;default:'default';index"`
+		Name        string `gorm:"not null"`
+		Description string
+		Conditions  []byte
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+		DeletedAt   *time.Time `gorm:"index"`
+	}
+
+	type ResourceSet struct {
+		ID          string `gorm:"primaryKey"`
+		TenantID    string `gorm:"not null;default:'default';index"`
+		Name        string `gorm:"not null"`
+		Description string
+		Conditions  []byte
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+		DeletedAt   *time.Time `gorm:"index"`
+	}
+
+	type Permission struct {
+		ID            string  `gorm:"primaryKey"`
+		TenantID      string  `gorm:"not null;default:'default';index"`
+		RoleID        string  `gorm:"index"`
+		UserID        *string `gorm:"index"`
+		UserSetID     *string `gorm:"index"`
+		ResourceID    *string `gorm:"index"`
+		ResourceSetID *string `gorm:"index"`
+		ActionID      *string `gorm:"index"`
+		ActionName    *string
+		Effect        string `gorm:"not null"`
+		Priority      int    `gorm:"not null;default:0"`
+		Conditions    []byte
+		CreatedAt     time.Time
+		UpdatedAt     time.Time
+		DeletedAt     *time.Time `gorm:"index"`
+	}
+
+	type RelationTuple struct {
+		ID              string `gorm:"primaryKey"`
+		TenantID        string `gorm:"not null;default:'default';uniqueIndex:idx_relation_tuples_tenant_tuple"`
+		ObjectType      string `gorm:"not null;uniqueIndex:idx_relation_tuples_tenant_tuple;index:idx_relation_tuples_object"`
+		ObjectID        string `gorm:"not null;uniqueIndex:idx_relation_tuples_tenant_tuple;index:idx_relation_tuples_object"`
+		Relation        string `gorm:"not null;uniqueIndex:idx_relation_tuples_tenant_tuple;index:idx_relation_tuples_object"`
+		SubjectType     string `gorm:"not null;uniqueIndex:idx_relation_tuples_tenant_tuple;index:idx_relation_tuples_subject"`
+		SubjectID       string `gorm:"not null;uniqueIndex:idx_relation_tuples_tenant_tuple;index:idx_relation_tuples_subject"`
+		SubjectRelation string `gorm:"not null;default:'';uniqueIndex:idx_relation_tuples_tenant_tuple"`
+		CreatedAt       time.Time
+	}
+
+	type RelationSchema struct {
+		ID         string `gorm:"primaryKey"`
+		TenantID   string `gorm:"not null;default:'default';uniqueIndex:idx_relation_schemas_tenant_version"`
+		Version    int    `gorm:"not null;uniqueIndex:idx_relation_schemas_tenant_version"`
+		Definition []byte `gorm:"not null"`
+		CreatedAt  time.Time
+	}
+
+	type Revision struct {
+		ID          int64  `gorm:"primaryKey;autoIncrement"`
+		TenantID    string `gorm:"not null;default:'default';index"`
+		Description string `gorm:"not null"`
+		Changes     []byte `gorm:"not null"`
+		CreatedAt   time.Time
 	}
 
 	// Run migrations
-	err := p.DB.AutoMigrate(&Resource{}, &Action{}, &Role{}, &User{})
+	err := p.DB.AutoMigrate(&Resource{}, &Action{}, &Role{}, &RoleParent{}, &User{}, &UserRole{}, &UserSet{}, &ResourceSet{}, &Permission{}, &RelationTuple{}, &RelationSchema{}, &Revision{})
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	// Unique indexes created before tenants existed would prevent tenants from reusing names
+	legacyIndexes := []struct {
+		model interface{}
+		name  string
+	}{
+		{&User{}, "idx_users_username"},
+		{&User{}, "idx_users_email"},
+		{&RelationTuple{}, "idx_relation_tuples_tuple"},
+		{&RelationSchema{}, "idx_relation_schemas_version"},
+	}
+	for _, index := range legacyIndexes {
+		if !p.DB.Migrator().HasIndex(index.model, index.name) {
+			continue
+		}
+		if err := p.DB.Migrator().DropIndex(index.model, index.name); err != nil {
+			return fmt.Errorf("failed to drop index %s: %w", index.name, err)
+		}
+	}
+
 	return nil
 }
+
+// txKey is the context key of the transaction carried by a context
+type txKey struct{}
+
+// WithContext returns a session bound to ctx that joins the transaction ctx carries, if any
+func (p *PostgresDB) WithContext(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return p.DB.WithContext(ctx)
+}
+
+// InTransaction runs fn in a transaction that every statement made through WithContext with the
+// context passed to fn joins. The transaction is committed when fn returns nil and rolled back otherwise.
+func (p *PostgresDB) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return p.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
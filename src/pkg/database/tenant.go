@@ -0,0 +1,65 @@
+package database
+
+import (
+	"github.com/arifsetyawan/validra/src/pkg/tenant"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tenantField is the field of the models scoped to a tenant
+const tenantField = "TenantID"
+
+// registerTenantCallbacks scopes every statement on a model with a TenantID field to the tenant carried by
+// the statement's context: created rows are stamped with the tenant and queries, updates and deletes only
+// see the rows of that tenant. Repositories therefore never cross tenant boundaries as long as they pass
+// the request context along.
+func registerTenantCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("validra:tenant", stampTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("validra:tenant", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("validra:tenant", func(db *gorm.DB) {
+		// Save writes every column, so keep the tenant of the row as well
+		stampTenant(db)
+		scopeTenant(db)
+	}); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("validra:tenant", scopeTenant); err != nil {
+		return err
+	}
+	return callbacks.Row().Before("gorm:row").Register("validra:tenant", scopeTenant)
+}
+
+// stampTenant sets the tenant of the rows written by the statement
+func stampTenant(db *gorm.DB) {
+	if !tenantScoped(db) {
+		return
+	}
+	db.Statement.SetColumn(tenantField, tenant.FromContext(db.Statement.Context), true)
+}
+
+// scopeTenant restricts the statement to the rows of the tenant
+func scopeTenant(db *gorm.DB) {
+	if !tenantScoped(db) {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: "tenant_id"},
+			Value:  tenant.FromContext(db.Statement.Context),
+		},
+	}})
+}
+
+// tenantScoped reports whether the statement applies to a model scoped to a tenant
+func tenantScoped(db *gorm.DB) bool {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return false
+	}
+	_, ok := db.Statement.Schema.FieldsByName[tenantField]
+	return ok
+}
@@ -0,0 +1,281 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/arifsetyawan/validra/src/pkg/condition"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the version of the document format understood by this package
+const Version = 1
+
+// Effects a permission can have
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// Document declares the authorization model of a tenant: its resources and their actions, resource
+// sets, user sets, roles and the permissions granted to roles and user sets. Entities are identified
+// by name and refer to each other by name, so a document can be kept in a repository and reviewed.
+//
+//	version: 1
+//	resources:
+//	  - name: invoices
+//	    parent: finance
+//	    actions:
+//	      - name: read
+//	roles:
+//	  - name: accountant
+//	    parents: [staff]
+//	    permissions:
+//	      - resource: invoices
+//	        action: read
+type Document struct {
+	Version int `json:"version"`
+	// Tenant optionally names the tenant the document describes
+	Tenant       string        `json:"tenant,omitempty"`
+	Resources    []Resource    `json:"resources,omitempty"`
+	ResourceSets []ResourceSet `json:"resource_sets,omitempty"`
+	UserSets     []UserSet     `json:"user_sets,omitempty"`
+	Roles        []Role        `json:"roles,omitempty"`
+}
+
+// Resource declares a resource and its actions
+type Resource struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Attributes  json.RawMessage `json:"attributes,omitempty"`
+	// Parent names the resource this resource is nested under
+	Parent             string   `json:"parent,omitempty"`
+	BlockInheritance   bool     `json:"block_inheritance,omitempty"`
+	CombiningAlgorithm string   `json:"combining_algorithm,omitempty"`
+	DefaultEffect      string   `json:"default_effect,omitempty"`
+	Actions            []Action `json:"actions,omitempty"`
+}
+
+// Action declares an action of a resource
+type Action struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Attributes  json.RawMessage `json:"attributes,omitempty"`
+}
+
+// ResourceSet declares a resource set
+type ResourceSet struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Conditions  json.RawMessage `json:"conditions"`
+}
+
+// UserSet declares a user set and the permissions granted to its members
+type UserSet struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Conditions  json.RawMessage `json:"conditions"`
+	Permissions []Permission    `json:"permissions,omitempty"`
+}
+
+// Role declares a role, the roles it inherits from and the permissions granted to it
+type Role struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Parents     []string     `json:"parents,omitempty"`
+	Permissions []Permission `json:"permissions,omitempty"`
+}
+
+// Permission declares a grant on an action of a resource or on a resource set
+type Permission struct {
+	Resource    string          `json:"resource,omitempty"`
+	Action      string          `json:"action,omitempty"`
+	ResourceSet string          `json:"resource_set,omitempty"`
+	Effect      string          `json:"effect,omitempty"`
+	Priority    int             `json:"priority,omitempty"`
+	Conditions  json.RawMessage `json:"conditions,omitempty"`
+}
+
+// Parse decodes and validates a YAML or JSON serialized document.
+// Unknown fields are rejected so that typos do not silently change the policy.
+func Parse(data []byte) (*Document, error) {
+	// Decode YAML (a superset of JSON) generically, then through JSON to reuse the field names
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("invalid policy: document is empty")
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+
+	var d Document
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&d); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+	if err := d.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+
+	return &d, nil
+}
+
+// Validate checks that names are unique, that permissions name their target and that conditions
+// are well formed. References to entities the document does not declare are left to the caller,
+// as they may already exist.
+func (d *Document) Validate() error {
+	if d.Version != Version {
+		return fmt.Errorf("unsupported version %d, expected %d", d.Version, Version)
+	}
+
+	resources := make(map[string]bool, len(d.Resources))
+	for _, r := range d.Resources {
+		if r.Name == "" {
+			return fmt.Errorf("resource name is required")
+		}
+		if resources[r.Name] {
+			return fmt.Errorf("resource %q is declared more than once", r.Name)
+		}
+		resources[r.Name] = true
+
+		if r.Parent == r.Name {
+			return fmt.Errorf("resource %q cannot be its own parent", r.Name)
+		}
+		switch r.CombiningAlgorithm {
+		case "", "deny-overrides", "permit-overrides", "first-applicable":
+		default:
+			return fmt.Errorf("resource %q: unknown combining algorithm %q", r.Name, r.CombiningAlgorithm)
+		}
+		if r.DefaultEffect != "" && r.DefaultEffect != EffectAllow && r.DefaultEffect != EffectDeny {
+			return fmt.Errorf("resource %q: default effect must be either allow or deny", r.Name)
+		}
+		if err := validateAttributes(r.Attributes); err != nil {
+			return fmt.Errorf("resource %q: %w", r.Name, err)
+		}
+
+		actions := make(map[string]bool, len(r.Actions))
+		for _, a := range r.Actions {
+			if a.Name == "" {
+				return fmt.Errorf("resource %q: action name is required", r.Name)
+			}
+			if actions[a.Name] {
+				return fmt.Errorf("resource %q: action %q is declared more than once", r.Name, a.Name)
+			}
+			actions[a.Name] = true
+			if err := validateAttributes(a.Attributes); err != nil {
+				return fmt.Errorf("resource %q: action %q: %w", r.Name, a.Name, err)
+			}
+		}
+	}
+
+	resourceSets := make(map[string]bool, len(d.ResourceSets))
+	for _, rs := range d.ResourceSets {
+		if rs.Name == "" {
+			return fmt.Errorf("resource set name is required")
+		}
+		if resourceSets[rs.Name] {
+			return fmt.Errorf("resource set %q is declared more than once", rs.Name)
+		}
+		resourceSets[rs.Name] = true
+		if err := validateConditions(rs.Conditions, true); err != nil {
+			return fmt.Errorf("resource set %q: %w", rs.Name, err)
+		}
+	}
+
+	userSets := make(map[string]bool, len(d.UserSets))
+	for _, us := range d.UserSets {
+		if us.Name == "" {
+			return fmt.Errorf("user set name is required")
+		}
+		if userSets[us.Name] {
+			return fmt.Errorf("user set %q is declared more than once", us.Name)
+		}
+		userSets[us.Name] = true
+		if err := validateConditions(us.Conditions, true); err != nil {
+			return fmt.Errorf("user set %q: %w", us.Name, err)
+		}
+		for i, p := range us.Permissions {
+			if err := p.validate(); err != nil {
+				return fmt.Errorf("user set %q: permission %d: %w", us.Name, i+1, err)
+			}
+		}
+	}
+
+	roles := make(map[string]bool, len(d.Roles))
+	for _, r := range d.Roles {
+		if r.Name == "" {
+			return fmt.Errorf("role name is required")
+		}
+		if roles[r.Name] {
+			return fmt.Errorf("role %q is declared more than once", r.Name)
+		}
+		roles[r.Name] = true
+
+		for _, parent := range r.Parents {
+			if parent == r.Name {
+				return fmt.Errorf("role %q cannot inherit from itself", r.Name)
+			}
+		}
+		for i, p := range r.Permissions {
+			if err := p.validate(); err != nil {
+				return fmt.Errorf("role %q: permission %d: %w", r.Name, i+1, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// EffectOrDefault returns the effect of the permission, allow when it is not set
+func (p Permission) EffectOrDefault() string {
+	if p.Effect == "" {
+		return EffectAllow
+	}
+	return p.Effect
+}
+
+// validate checks that a permission targets either an action of a resource or a resource set,
+// optionally restricted to the actions of a name
+func (p Permission) validate() error {
+	if p.ResourceSet != "" {
+		if p.Resource != "" {
+			return fmt.Errorf("resource set permissions cannot target a resource")
+		}
+	} else if p.Resource == "" || p.Action == "" {
+		return fmt.Errorf("resource and action, or resource set, are required")
+	}
+	if p.Effect != "" && p.Effect != EffectAllow && p.Effect != EffectDeny {
+		return fmt.Errorf("effect must be either allow or deny")
+	}
+	return validateConditions(p.Conditions, false)
+}
+
+// validateConditions checks the condition grammar, requiring conditions when asked to
+func validateConditions(data json.RawMessage, required bool) error {
+	conditions, err := condition.Parse(data)
+	if err != nil {
+		return err
+	}
+	if conditions == nil && required {
+		return fmt.Errorf("invalid conditions: conditions are required")
+	}
+	return nil
+}
+
+// validateAttributes checks that attributes, when set, are an object
+func validateAttributes(data json.RawMessage) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	var attributes map[string]interface{}
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return fmt.Errorf("attributes must be an object")
+	}
+	return nil
+}
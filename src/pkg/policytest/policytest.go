@@ -0,0 +1,158 @@
+package policytest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/arifsetyawan/validra/src/pkg/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the version of the suite format understood by this package
+const Version = 1
+
+// Outcomes a test can expect
+const (
+	ExpectAllow = "allow"
+	ExpectDeny  = "deny"
+)
+
+// Suite declares a policy model, the users and relationships to check it with and the decisions
+// expected from permission checks. Users, roles, resources and actions are identified by name.
+//
+//	version: 1
+//	policy: policy.yaml
+//	users:
+//	  - username: alice
+//	    attributes:
+//	      department: finance
+//	    roles: [accountant]
+//	tests:
+//	  - name: accountants read invoices
+//	    user: alice
+//	    action: read
+//	    resource: invoices
+//	    expect: allow
+type Suite struct {
+	Version int `json:"version"`
+	// Policy is the path of a policy document, relative to the suite
+	Policy string `json:"policy,omitempty"`
+	// Bundle is the path of a bundle, relative to the suite. It is imported before the policy is applied.
+	Bundle string `json:"bundle,omitempty"`
+	// Schema is the relation schema definition checks use for relationships
+	Schema        json.RawMessage `json:"schema,omitempty"`
+	Users         []User          `json:"users,omitempty"`
+	Relationships []string        `json:"relationships,omitempty"`
+	Tests         []Test          `json:"tests"`
+}
+
+// User declares a user and the roles assigned to it
+type User struct {
+	Username   string          `json:"username"`
+	Attributes json.RawMessage `json:"attributes,omitempty"`
+	Roles      []string        `json:"roles,omitempty"`
+}
+
+// Test asserts the decision of a permission check
+type Test struct {
+	Name     string                 `json:"name"`
+	User     string                 `json:"user"`
+	Action   string                 `json:"action"`
+	Resource string                 `json:"resource"`
+	Context  map[string]interface{} `json:"context,omitempty"`
+	Expect   string                 `json:"expect"`
+}
+
+// Parse decodes and validates a YAML or JSON serialized suite.
+// Unknown fields are rejected so that typos do not silently change a test.
+func Parse(data []byte) (*Suite, error) {
+	// Decode YAML (a superset of JSON) generically, then through JSON to reuse the field names
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid suite: %w", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("invalid suite: document is empty")
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid suite: %w", err)
+	}
+
+	var s Suite
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&s); err != nil {
+		return nil, fmt.Errorf("invalid suite: %w", err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid suite: %w", err)
+	}
+
+	return &s, nil
+}
+
+// Validate checks that the suite declares a model, that usernames and test names are unique and
+// that every test names its check and expected outcome. Whether the users, actions and resources
+// of a test exist is left to the checks, which deny them otherwise.
+func (s *Suite) Validate() error {
+	if s.Version != Version {
+		return fmt.Errorf("unsupported version %d, expected %d", s.Version, Version)
+	}
+	if s.Policy == "" && s.Bundle == "" {
+		return fmt.Errorf("a policy or a bundle is required")
+	}
+	if len(s.Schema) > 0 && string(s.Schema) != "null" {
+		if _, err := schema.Parse(s.Schema); err != nil {
+			return err
+		}
+	}
+
+	users := make(map[string]bool, len(s.Users))
+	for _, u := range s.Users {
+		if u.Username == "" {
+			return fmt.Errorf("username is required")
+		}
+		if users[u.Username] {
+			return fmt.Errorf("user %q is declared more than once", u.Username)
+		}
+		users[u.Username] = true
+
+		if len(u.Attributes) > 0 && string(u.Attributes) != "null" {
+			var attributes map[string]interface{}
+			if err := json.Unmarshal(u.Attributes, &attributes); err != nil {
+				return fmt.Errorf("user %q: attributes must be an object", u.Username)
+			}
+		}
+	}
+
+	for i, r := range s.Relationships {
+		if r == "" {
+			return fmt.Errorf("relationship %d is empty", i+1)
+		}
+	}
+
+	if len(s.Tests) == 0 {
+		return fmt.Errorf("at least one test is required")
+	}
+	tests := make(map[string]bool, len(s.Tests))
+	for i, t := range s.Tests {
+		if t.Name == "" {
+			return fmt.Errorf("test %d: name is required", i+1)
+		}
+		if tests[t.Name] {
+			return fmt.Errorf("test %q is declared more than once", t.Name)
+		}
+		tests[t.Name] = true
+
+		if t.User == "" || t.Action == "" || t.Resource == "" {
+			return fmt.Errorf("test %q: user, action and resource are required", t.Name)
+		}
+		if t.Expect != ExpectAllow && t.Expect != ExpectDeny {
+			return fmt.Errorf("test %q: expect must be either allow or deny", t.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Rewrite operators describe how the subjects of a relation are derived
+const (
+	// OperatorThis selects the subjects stored as tuples for the relation itself
+	OperatorThis = "this"
+	// OperatorComputedUserset selects the subjects of another relation on the same object
+	OperatorComputedUserset = "computed_userset"
+	// OperatorTupleToUserset follows the tuples of the tupleset relation and selects the
+	// subjects of a relation on each object they point at
+	OperatorTupleToUserset = "tuple_to_userset"
+	// OperatorUnion selects the subjects of any child
+	OperatorUnion = "union"
+	// OperatorIntersection selects the subjects of every child
+	OperatorIntersection = "intersection"
+	// OperatorExclusion selects the subjects of the first child that are not subjects of the second
+	OperatorExclusion = "exclusion"
+)
+
+// Schema declares the relations of every object type and how they are derived.
+//
+// A relation without a rewrite is only made of stored tuples. Rewrites are trees:
+//
+//	{"operator": "union", "children": [
+//	  {"operator": "this"},
+//	  {"operator": "computed_userset", "relation": "editor"},
+//	  {"operator": "tuple_to_userset", "tupleset": "parent", "relation": "viewer"}
+//	]}
+type Schema struct {
+	Types []TypeDefinition `json:"types"`
+}
+
+// TypeDefinition declares the relations of an object type
+type TypeDefinition struct {
+	Name      string               `json:"name"`
+	Relations []RelationDefinition `json:"relations"`
+}
+
+// RelationDefinition declares a relation and, optionally, how it is derived
+type RelationDefinition struct {
+	Name    string   `json:"name"`
+	Rewrite *Rewrite `json:"rewrite,omitempty"`
+}
+
+// Rewrite is a node of a relation rewrite tree
+type Rewrite struct {
+	Operator string    `json:"operator"`
+	Relation string    `json:"relation,omitempty"`
+	Tupleset string    `json:"tupleset,omitempty"`
+	Children []Rewrite `json:"children,omitempty"`
+}
+
+// Parse decodes and validates a JSON serialized schema
+func Parse(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	return &s, nil
+}
+
+// Validate checks that type and relation names are unique and that every rewrite only
+// refers to relations the schema declares
+func (s *Schema) Validate() error {
+	if len(s.Types) == 0 {
+		return fmt.Errorf("at least one type is required")
+	}
+
+	types := make(map[string]bool, len(s.Types))
+	for _, t := range s.Types {
+		if !validName(t.Name) {
+			return fmt.Errorf("invalid type name %q", t.Name)
+		}
+		if types[t.Name] {
+			return fmt.Errorf("type %q is declared more than once", t.Name)
+		}
+		types[t.Name] = true
+
+		relations := make(map[string]bool, len(t.Relations))
+		for _, r := range t.Relations {
+			if !validName(r.Name) {
+				return fmt.Errorf("type %q: invalid relation name %q", t.Name, r.Name)
+			}
+			if relations[r.Name] {
+				return fmt.Errorf("type %q: relation %q is declared more than once", t.Name, r.Name)
+			}
+			relations[r.Name] = true
+		}
+	}
+
+	for i := range s.Types {
+		t := &s.Types[i]
+		for _, r := range t.Relations {
+			if r.Rewrite == nil {
+				continue
+			}
+			if err := s.validateRewrite(t, r.Rewrite); err != nil {
+				return fmt.Errorf("type %q: relation %q: %w", t.Name, r.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateRewrite checks a rewrite tree declared on the given type
+func (s *Schema) validateRewrite(t *TypeDefinition, rewrite *Rewrite) error {
+	switch rewrite.Operator {
+	case OperatorThis:
+		return nil
+	case OperatorComputedUserset:
+		if _, ok := t.relation(rewrite.Relation); !ok {
+			return fmt.Errorf("computed_userset refers to undeclared relation %q", rewrite.Relation)
+		}
+		return nil
+	case OperatorTupleToUserset:
+		tupleset, ok := t.relation(rewrite.Tupleset)
+		if !ok {
+			return fmt.Errorf("tuple_to_userset refers to undeclared tupleset %q", rewrite.Tupleset)
+		}
+		if !tupleset.AllowsDirect() {
+			return fmt.Errorf("tuple_to_userset tupleset %q must be stored as tuples", rewrite.Tupleset)
+		}
+		if !s.declaresRelation(rewrite.Relation) {
+			return fmt.Errorf("tuple_to_userset refers to relation %q that no type declares", rewrite.Relation)
+		}
+		return nil
+	case OperatorUnion, OperatorIntersection, OperatorExclusion:
+		if rewrite.Operator == OperatorExclusion && len(rewrite.Children) != 2 {
+			return fmt.Errorf("exclusion requires exactly two children")
+		}
+		if len(rewrite.Children) == 0 {
+			return fmt.Errorf("%s requires at least one child", rewrite.Operator)
+		}
+		for i := range rewrite.Children {
+			if err := s.validateRewrite(t, &rewrite.Children[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown operator %q", rewrite.Operator)
+}
+
+// declaresRelation reports whether any type declares the relation
+func (s *Schema) declaresRelation(name string) bool {
+	for i := range s.Types {
+		if _, ok := s.Types[i].relation(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// HasType reports whether the schema declares the object type
+func (s *Schema) HasType(name string) bool {
+	_, ok := s.typeDefinition(name)
+	return ok
+}
+
+// Relation returns the definition of a relation declared on an object type
+func (s *Schema) Relation(objectType, relation string) (*RelationDefinition, bool) {
+	t, ok := s.typeDefinition(objectType)
+	if !ok {
+		return nil, false
+	}
+	return t.relation(relation)
+}
+
+// typeDefinition returns the definition of an object type
+func (s *Schema) typeDefinition(name string) (*TypeDefinition, bool) {
+	for i := range s.Types {
+		if s.Types[i].Name == name {
+			return &s.Types[i], true
+		}
+	}
+	return nil, false
+}
+
+// relation returns the definition of a relation declared on the type
+func (t *TypeDefinition) relation(name string) (*RelationDefinition, bool) {
+	for i := range t.Relations {
+		if t.Relations[i].Name == name {
+			return &t.Relations[i], true
+		}
+	}
+	return nil, false
+}
+
+// Effective returns the rewrite of the relation, which defaults to its stored tuples
+func (r *RelationDefinition) Effective() Rewrite {
+	if r.Rewrite == nil {
+		return Rewrite{Operator: OperatorThis}
+	}
+	return *r.Rewrite
+}
+
+// AllowsDirect reports whether tuples can be written for the relation
+func (r *RelationDefinition) AllowsDirect() bool {
+	return r.Rewrite == nil || r.Rewrite.usesThis()
+}
+
+// usesThis reports whether the rewrite tree selects stored tuples anywhere
+func (r *Rewrite) usesThis() bool {
+	if r.Operator == OperatorThis {
+		return true
+	}
+	for i := range r.Children {
+		if r.Children[i].usesThis() {
+			return true
+		}
+	}
+	return false
+}
+
+// validName reports whether name can be used as a type or relation name
+func validName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, ":#@ ")
+}
@@ -0,0 +1,37 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// Default is the tenant used when none is carried by the context
+const Default = "default"
+
+// idPattern restricts tenant IDs to lowercase letters, digits, dashes and underscores
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// contextKey is the key the tenant ID is stored under in a context
+type contextKey struct{}
+
+// WithID returns a copy of ctx carrying the tenant ID
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant ID carried by ctx, or Default when there is none
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
+
+// Validate checks that id is a well-formed tenant ID
+func Validate(id string) error {
+	if !idPattern.MatchString(id) {
+		return fmt.Errorf("invalid tenant: must be 1 to 63 lowercase letters, digits, dashes or underscores")
+	}
+	return nil
+}